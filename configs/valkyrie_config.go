@@ -15,11 +15,16 @@ type LogConfig struct {
 	Level  string          `yaml:"level" default:"info"`
 	Async  AsyncLogConfig  `yaml:"async"`
 	Output OutputLogConfig `yaml:"output"`
+	// Outputs additional outputs logged to at the same time as Output
+	Outputs []OutputLogConfig `yaml:"outputs,omitempty"`
 }
 
 type HTTPLogConfig struct {
 	HeaderWhitelist      *[]string `yaml:"header_whitelist,omitempty"`
 	ContentTypeWhitelist *[]string `yaml:"content_type_whitelist,omitempty"`
+	// Redact rules of values replaced in logged headers, query strings and bodies, in addition to the token fields
+	// of providers. Either names of fields and parameters, such as "userId", or JSON paths such as "$.player.id".
+	Redact []string `yaml:"redact,omitempty"`
 }
 
 // AsyncLogConfig Configuration for asynchronous logging
@@ -32,9 +37,22 @@ type AsyncLogConfig struct {
 // OutputLogConfig Configuration for logging output
 type OutputLogConfig struct {
 	// Type configures where to output logs.
-	// Supported types: "stdout", "stderr", "file"
+	// Supported types: "stdout", "stderr", "file", "syslog", "otlp", "tcp"
 	Type string `yaml:"type" default:"stdout"`
 
+	// Level is the minimum level of logs written to the output, in addition to the log level
+	Level string `yaml:"level,omitempty"`
+
+	// Address of the "syslog" server, such as "udp://syslog:514" or "tcp://syslog:601" (udp if no scheme is given),
+	// or of the "tcp" server receiving newline-delimited JSON, such as "logstash:5000"
+	Address string `yaml:"address,omitempty"`
+
+	// Facility of "syslog" messages, such as "daemon" or "local0", defaults to "local0"
+	Facility string `yaml:"facility,omitempty"`
+
+	// URL of the "otlp" log collector, defaults to the tracing url with the path "/v1/logs"
+	URL string `yaml:"url,omitempty"`
+
 	// Filename is the file to write logs to.  Backup log files will be retained
 	// in the same directory.
 	Filename string `yaml:"filename,omitempty"`
@@ -58,6 +76,31 @@ type OutputLogConfig struct {
 	Compress bool `yaml:"compress,omitempty"`
 }
 
+// RecordingConfig Configuration of the traffic recorder, writing provider requests and responses together
+// with the PAM calls they caused as JSON lines to a rotating file
+type RecordingConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Filename is the file to write recordings to, defaults to "valkyrie-traffic.jsonl"
+	Filename string `yaml:"filename,omitempty"`
+
+	// MaxSize is the maximum size in megabytes of the file before it gets rotated, defaults to 100 megabytes
+	MaxSize int `yaml:"max_size,omitempty"`
+
+	// MaxAge is the maximum number of days to retain rotated files
+	MaxAge int `yaml:"max_age,omitempty"`
+
+	// MaxBackups is the maximum number of rotated files to retain
+	MaxBackups int `yaml:"max_backups,omitempty"`
+
+	// Compress determines if rotated files are compressed using gzip
+	Compress bool `yaml:"compress,omitempty"`
+
+	// Redact names of headers, query parameters and JSON fields, or JSON paths such as "$.player.id", whose values
+	// are replaced before recording, in addition to Authorization, Cookie, Set-Cookie and authToken
+	Redact []string `yaml:"redact,omitempty"`
+}
+
 // TelemetryConfig holds general configuration for telemetry (tracing & metrics)
 type TelemetryConfig struct {
 	Metric      MetricConfig `yaml:"metric,omitempty"`
@@ -72,6 +115,26 @@ type TraceConfig struct {
 	URL             string  `yaml:"url,omitempty"`
 	GoogleProjectID string  `yaml:"google_project_id,omitempty"`
 	SampleRatio     float64 `yaml:"sample_ratio" default:"0.01"`
+	// Sampling by route or provider, replacing SampleRatio for matching requests, which also samples errors
+	// and slow requests regardless of ratio
+	Sampling *SamplingConfig `yaml:"sampling,omitempty"`
+}
+
+// SamplingConfig Configuration of the sampling of traces
+type SamplingConfig struct {
+	// Rules evaluated in order, the first matching a request decides its sample ratio, otherwise SampleRatio
+	Rules []SamplingRule `yaml:"rules,omitempty"`
+	// SlowThreshold traces of requests taking at least this long are sampled, regardless of ratio
+	SlowThreshold time.Duration `yaml:"slow_threshold,omitempty"`
+}
+
+// SamplingRule sample ratio of the requests to a route or provider
+type SamplingRule struct {
+	// Route matches request paths starting with it, such as "/providers/evolution/debit"
+	Route string `yaml:"route,omitempty"`
+	// Provider matches requests to the routes of the named provider
+	Provider string  `yaml:"provider,omitempty"`
+	Ratio    float64 `yaml:"ratio"`
 }
 
 // MetricConfig Configuration setup for metrics
@@ -92,11 +155,39 @@ type ProviderConf struct {
 	URL string `yaml:"url"`
 	// BasePath used to distinguish endpoints exposed by Valkyrie
 	BasePath string `yaml:"base_path,omitempty"`
+	// Retry policy for outgoing requests to the provider, replacing the http_client retry policy
+	Retry *RetryConfig `yaml:"retry,omitempty"`
+	// HTTPClientProfile name of the http_client_profiles entry used for outgoing requests to the provider
+	HTTPClientProfile string `yaml:"http_client_profile,omitempty"`
 }
 
 // PamConf Configured information for the used Player Account Manager/wallet
 type PamConf = map[string]any
 
+// JackpotsConfig Configuration of the ledger of jackpot contributions and wins booked through the PAM, reported by
+// the operator endpoint "/jackpots/contributions"
+type JackpotsConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Filename is the file the ledger is persisted to, next to a snapshot of it, kept in memory only if empty
+	Filename string `yaml:"filename,omitempty"`
+
+	// RetentionDays is the number of days summaries are kept, defaults to 90 days
+	RetentionDays int `yaml:"retention_days,omitempty"`
+}
+
+// FXConf Configuration of the currency conversion between the play currency used by providers and the currency of
+// player wallets. "source" selects the exchange rate source, with the rest of the configuration specific to it.
+type FXConf = map[string]any
+
+// AuditConf Configuration of the audit log of wallet transactions. "sink" selects where records are written, with
+// the rest of the configuration specific to it.
+type AuditConf = map[string]any
+
+// ResponsibleGamingConf Configuration of the responsible gaming checks of stakes. "rules" lists the rules evaluated
+// before every stake, each selected by its "rule" with the rest of the configuration specific to it.
+type ResponsibleGamingConf = map[string]any
+
 // ValkyrieConfig Parsed valkyrie configuration
 type ValkyrieConfig struct {
 	HTTPServer       HTTPServerConfig `yaml:"http_server"`
@@ -110,6 +201,18 @@ type ValkyrieConfig struct {
 	Version          string           `yaml:"-"`
 	Logging          LogConfig        `yaml:"logging,omitempty"`
 	HTTPClient       HTTPClientConfig `yaml:"http_client"`
+	// HTTPClientProfiles named http client configurations, each with its own connection pool
+	HTTPClientProfiles map[string]HTTPClientConfig `yaml:"http_client_profiles,omitempty"`
+	// Recording of provider traffic, used to replay sessions with "valkyrie replay"
+	Recording RecordingConfig `yaml:"recording,omitempty"`
+	// FX currency conversion, disabled unless configured
+	FX FXConf `yaml:"fx,omitempty"`
+	// Audit log of all wallet transactions, verified with "valkyrie audit verify", disabled unless configured
+	Audit AuditConf `yaml:"audit,omitempty"`
+	// Jackpots ledger of jackpot contributions and wins, disabled unless enabled
+	Jackpots JackpotsConfig `yaml:"jackpots,omitempty"`
+	// ResponsibleGaming checks of stakes against loss limits, session time limits and reality checks, disabled unless configured
+	ResponsibleGaming ResponsibleGamingConf `yaml:"responsible_gaming,omitempty"`
 }
 
 // HTTPServerConfig Configuration used for valkyrie servers
@@ -131,10 +234,41 @@ type HTTPServerConfig struct {
 
 // HTTPClientConfig Configuration for outgoing requests
 type HTTPClientConfig struct {
-	ReadTimeout    time.Duration `yaml:"read_timeout" default:"10s"`    // Maximum duration for full response reading (including body)
-	WriteTimeout   time.Duration `yaml:"write_timeout" default:"3s"`    // Maximum duration for full request writing (including body)
-	RequestTimeout time.Duration `yaml:"request_timeout" default:"10s"` // Maximum duration to wait for the request response (on timeout request will continue in background, try setting read/write timeout to interrupt actual request)
-	IdleTimeout    time.Duration `yaml:"idle_timeout" default:"30s"`    // Idle keep-alive connections are closed after this duration.
+	ReadTimeout         time.Duration `yaml:"read_timeout" default:"10s"`       // Maximum duration for full response reading (including body)
+	WriteTimeout        time.Duration `yaml:"write_timeout" default:"3s"`       // Maximum duration for full request writing (including body)
+	RequestTimeout      time.Duration `yaml:"request_timeout" default:"10s"`    // Maximum duration to wait for the request response (on timeout request will continue in background, try setting read/write timeout to interrupt actual request)
+	IdleTimeout         time.Duration `yaml:"idle_timeout" default:"30s"`       // Idle keep-alive connections are closed after this duration.
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host" default:"512"` // Maximum number of connections per host
+	MaxResponseBodySize int           `yaml:"max_response_body_size,omitempty"` // Maximum size in bytes of response bodies, unlimited if not set
+	Retry               RetryConfig   `yaml:"retry"`                            // Retry policy for outgoing requests
+	TLS                 TLSConfig     `yaml:"tls,omitempty"`                    // TLS configuration for outgoing https requests
+	Proxy               ProxyConfig   `yaml:"proxy,omitempty"`                  // Proxy used for outgoing requests
+}
+
+// ProxyConfig Proxy for outgoing requests
+type ProxyConfig struct {
+	URL             string `yaml:"url,omitempty"`              // Proxy url, http://[user:password@]host:port for HTTP CONNECT or socks5://host:port
+	NoProxy         string `yaml:"no_proxy,omitempty"`         // Comma separated hosts, domains, IP prefixes and CIDRs not using the proxy, in NO_PROXY format
+	FromEnvironment bool   `yaml:"from_environment,omitempty"` // Use HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables instead of url and no_proxy
+}
+
+// TLSConfig TLS configuration for outgoing requests. Certificate and CA files are reloaded when changed.
+type TLSConfig struct {
+	CertFile       string        `yaml:"cert_file,omitempty"`          // Client certificate (PEM) used for mutual TLS, requires key_file
+	KeyFile        string        `yaml:"key_file,omitempty"`           // Private key (PEM) of the client certificate
+	CAFile         string        `yaml:"ca_file,omitempty"`            // CA bundle (PEM) used to verify servers instead of the system CAs
+	MinVersion     string        `yaml:"min_version,omitempty"`        // Minimum TLS version: 1.0, 1.1, 1.2 or 1.3, defaults to 1.2
+	ServerName     string        `yaml:"server_name,omitempty"`        // Overrides the server name used for SNI and verification
+	ReloadInterval time.Duration `yaml:"reload_interval" default:"1m"` // How often files are checked for changes
+}
+
+// RetryConfig Retry policy for outgoing requests. Requests failing to connect or on closed connections
+// are always retried, while retryable status codes are only retried for idempotent requests.
+type RetryConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts" default:"2"`       // Maximum number of attempts, including the first one
+	InitialBackoff time.Duration `yaml:"initial_backoff" default:"50ms"` // Backoff before the first retry, doubled for each following retry
+	MaxBackoff     time.Duration `yaml:"max_backoff" default:"1s"`       // Upper limit of the backoff between attempts
+	StatusCodes    []int         `yaml:"status_codes,omitempty"`         // Response status codes to retry, for example 502 and 503
 }
 
 // Read reads yaml file at provided location and parse it into a `ValkyrieConfig`
@@ -29,10 +29,21 @@ var defaultHTTPServerConfig = HTTPServerConfig{
 }
 
 var defaultHTTPClientConfig = HTTPClientConfig{
-	ReadTimeout:    10 * time.Second,
-	WriteTimeout:   3 * time.Second,
-	IdleTimeout:    30 * time.Second,
-	RequestTimeout: 10 * time.Second,
+	ReadTimeout:     10 * time.Second,
+	WriteTimeout:    3 * time.Second,
+	IdleTimeout:     30 * time.Second,
+	RequestTimeout:  10 * time.Second,
+	MaxConnsPerHost: 512,
+	TLS:             defaultTLSConfig,
+	Retry:           defaultRetryConfig,
+}
+
+var defaultTLSConfig = TLSConfig{ReloadInterval: time.Minute}
+
+var defaultRetryConfig = RetryConfig{
+	MaxAttempts:    2,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
 }
 
 var defaultLogConfig = LogConfig{
@@ -255,10 +266,13 @@ http_client:
 				OperatorAddress: ":8084",
 			},
 			HTTPClient: HTTPClientConfig{
-				ReadTimeout:    2 * time.Second,
-				WriteTimeout:   100 * time.Millisecond,
-				IdleTimeout:    10 * time.Second,
-				RequestTimeout: 2 * time.Second,
+				ReadTimeout:     2 * time.Second,
+				WriteTimeout:    100 * time.Millisecond,
+				IdleTimeout:     10 * time.Second,
+				RequestTimeout:  2 * time.Second,
+				MaxConnsPerHost: 512,
+				TLS:             defaultTLSConfig,
+				Retry:           defaultRetryConfig,
 			},
 		},
 	},
@@ -284,6 +298,85 @@ providers:
 			HTTPClient: defaultHTTPClientConfig,
 		},
 	},
+	{
+		name:        "yaml with provider retry policy parsed successfully",
+		envFilePath: "",
+		yamlData: `
+http_client:
+  retry:
+    max_attempts: 3
+    status_codes: [502, 503]
+providers:
+- name: some
+  retry:
+    max_attempts: 1
+`,
+		want: &ValkyrieConfig{
+			Providers: []ProviderConf{
+				{
+					Name: "some",
+					Retry: &RetryConfig{
+						MaxAttempts:    1,
+						InitialBackoff: 50 * time.Millisecond,
+						MaxBackoff:     time.Second,
+					},
+				},
+			},
+			Telemetry:  defaultTelemetryConfig,
+			Logging:    defaultLogConfig,
+			HTTPServer: defaultHTTPServerConfig,
+			HTTPClient: HTTPClientConfig{
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    3 * time.Second,
+				IdleTimeout:     30 * time.Second,
+				RequestTimeout:  10 * time.Second,
+				MaxConnsPerHost: 512,
+				TLS:             defaultTLSConfig,
+				Retry: RetryConfig{
+					MaxAttempts:    3,
+					InitialBackoff: 50 * time.Millisecond,
+					MaxBackoff:     time.Second,
+					StatusCodes:    []int{502, 503},
+				},
+			},
+		},
+	},
+	{
+		name:        "yaml with http client profiles parsed successfully",
+		envFilePath: "",
+		yamlData: `
+http_client_profiles:
+  slow:
+    request_timeout: 30s
+    max_conns_per_host: 10
+providers:
+- name: some
+  http_client_profile: slow
+`,
+		want: &ValkyrieConfig{
+			Providers: []ProviderConf{
+				{
+					Name:              "some",
+					HTTPClientProfile: "slow",
+				},
+			},
+			Telemetry:  defaultTelemetryConfig,
+			Logging:    defaultLogConfig,
+			HTTPServer: defaultHTTPServerConfig,
+			HTTPClient: defaultHTTPClientConfig,
+			HTTPClientProfiles: map[string]HTTPClientConfig{
+				"slow": {
+					ReadTimeout:     10 * time.Second,
+					WriteTimeout:    3 * time.Second,
+					IdleTimeout:     30 * time.Second,
+					RequestTimeout:  30 * time.Second,
+					MaxConnsPerHost: 10,
+					TLS:             defaultTLSConfig,
+					Retry:           defaultRetryConfig,
+				},
+			},
+		},
+	},
 	{
 		name:        "yaml with file logging output parsed successfully",
 		envFilePath: "",
@@ -429,7 +522,20 @@ func TestValkConfigFile(t *testing.T) {
 			ProviderAddress: ":8083",
 			OperatorAddress: ":8084",
 		},
-		HTTPClient: defaultHTTPClientConfig,
+		HTTPClient: HTTPClientConfig{
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    3 * time.Second,
+			IdleTimeout:     30 * time.Second,
+			RequestTimeout:  10 * time.Second,
+			MaxConnsPerHost: 512,
+			TLS:             defaultTLSConfig,
+			Retry: RetryConfig{
+				MaxAttempts:    2,
+				InitialBackoff: 50 * time.Millisecond,
+				MaxBackoff:     time.Second,
+				StatusCodes:    []int{502, 503},
+			},
+		},
 	}
 	cfg, err := Read(&file)
 	require.NoError(t, err)
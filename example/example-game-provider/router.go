@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 	"github.com/valkyrie-fnd/valkyrie/provider"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
@@ -13,6 +14,8 @@ const ProviderName = "example" // Example game provider
 // init will register the provider and operator endpoints.
 // If this provider is part of valkyrie config they will be exposed by Valkyrie
 func init() {
+	// Registering fields with session tokens, which are redacted when requests and responses are logged.
+	redact.Register("token")
 	// Registering the provider endpoints.
 	provider.ProviderFactory().
 		Register(ProviderName, func(args provider.ProviderArgs) (*provider.Router, error) {
@@ -4,7 +4,7 @@
 // This allows for multiple Handler functions to handle a PipelineContext without coupling them with
 // the sender code calling Pipeline.Execute(). The chain of Handler functions can be dynamically composed at
 // runtime by registering them using the Pipeline.Register() function. Registered handlers in the Pipeline are run in
-// the order that they are registered.
+// order of priority, and in the order that they are registered within the same priority.
 //
 // This is especially useful when there is a need to introduce cross-cutting concerns to some component, such as
 // caching or tracing, without coupling the component with its respective caching or tracing libraries.
@@ -35,10 +35,33 @@
 // It is important that intermediary Handler functions calls PipelineContext.Next() to continue the chain of handlers.
 // Any potential error returned by PipelineContext.Next() should also be returned by its respective Handler to properly
 // propagate errors back to the function calling Pipeline.Execute().
+//
+// A Handler may also answer a request itself by setting the result with PipelineContext.SetResult() and returning
+// without calling PipelineContext.Next(), which skips the remaining handlers and the finalizer. Results are typed when
+// executing the pipeline with Evaluate(), where the result of the finalizer is set on the PipelineContext, so that
+// handlers wrapping it can read it using ResultAs() and replace it. This allows caching, idempotency or fault
+// injection to be implemented as handlers, for example a cache answering balance requests:
+//
+//	pipeline.RegisterWith(Options[any]{When: PayloadIs[*BalanceRequest]}, func(pc PipelineContext[any]) error {
+//		if balance, found := cache.Get(pc.Payload()); found {
+//			pc.SetResult(balance)
+//			return nil
+//		}
+//		err := pc.Next()
+//		if balance, ok := ResultAs[*Balance](pc); ok && err == nil {
+//			cache.Set(pc.Payload(), balance)
+//		}
+//		return err
+//	})
+//	balance, err := Evaluate(ctx, pipeline, any(&BalanceRequest{}), func(pc PipelineContext[any]) (*Balance, error) {
+//		return client.GetBalance(pc.Context())
+//	})
 package pipeline
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -50,6 +73,7 @@ type Handler[T any] func(cc PipelineContext[T]) error
 type pipelineContext[T any] struct {
 	ctx     context.Context
 	payload T
+	result  any
 
 	handlers []Handler[T]
 	idx      int
@@ -79,16 +103,58 @@ func (c *pipelineContext[T]) Payload() T {
 	return c.payload
 }
 
+func (c *pipelineContext[T]) Result() any {
+	return c.result
+}
+
+func (c *pipelineContext[T]) SetResult(result any) {
+	c.result = result
+}
+
 type PipelineContext[T any] interface {
 	Next() error
 	Context() context.Context
 	SetContext(ctx context.Context)
 	Payload() T
+	// Result returns the result of the pipeline, nil until set by a handler or the finalizer
+	Result() any
+	// SetResult sets or replaces the result of the pipeline
+	SetResult(result any)
+}
+
+// ResultAs returns the result of the pipeline as type R, and false if no result of type R is set.
+func ResultAs[R any, T any](pc PipelineContext[T]) (R, bool) {
+	result, ok := pc.Result().(R)
+	return result, ok
+}
+
+// Finalizer is the final step of a pipeline executed with Evaluate, producing its result
+type Finalizer[T any, R any] func(pc PipelineContext[T]) (R, error)
+
+// Options of handlers registered using Pipeline.RegisterWith
+type Options[T any] struct {
+	// Priority of the handlers, handlers with higher priority run before handlers with lower priority.
+	// Handlers registered using Pipeline.Register have priority 0.
+	Priority int
+	// When runs the handlers only for payloads matching the predicate if set, see PayloadIs
+	When func(payload T) bool
+}
+
+// PayloadIs is a predicate for Options.When matching payloads of type P, for pipelines with interface payloads
+func PayloadIs[P any](payload any) bool {
+	_, ok := payload.(P)
+	return ok
+}
+
+// registration is a handler registered with its priority
+type registration[T any] struct {
+	handler  Handler[T]
+	priority int
 }
 
 // Pipeline keeps track of all registered handlers that should be executed by the pipeline.
 type Pipeline[T any] struct {
-	handlers []Handler[T]
+	handlers []registration[T]
 	lock     sync.RWMutex
 }
 
@@ -98,22 +164,45 @@ func NewPipeline[T any]() *Pipeline[T] {
 
 // Register one or more handlers
 func (p *Pipeline[T]) Register(h Handler[T], handlers ...Handler[T]) {
+	p.RegisterWith(Options[T]{}, h, handlers...)
+}
+
+// RegisterWith registers one or more handlers with a priority, and optionally a predicate on the payload
+// deciding if the handlers should run.
+func (p *Pipeline[T]) RegisterWith(opts Options[T], h Handler[T], handlers ...Handler[T]) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	p.handlers = append(p.handlers, h)
-	if len(handlers) > 0 {
-		p.handlers = append(p.handlers, handlers...)
+	for _, handler := range append([]Handler[T]{h}, handlers...) {
+		if opts.When != nil {
+			handler = conditional(opts.When, handler)
+		}
+		p.handlers = append(p.handlers, registration[T]{handler: handler, priority: opts.Priority})
 	}
+	sort.SliceStable(p.handlers, func(i, j int) bool {
+		return p.handlers[i].priority > p.handlers[j].priority
+	})
 }
 
-// Handlers retrieves a copy of registered handlers
+// conditional wraps a handler to only run for payloads matching the predicate
+func conditional[T any](when func(payload T) bool, h Handler[T]) Handler[T] {
+	return func(pc PipelineContext[T]) error {
+		if !when(pc.Payload()) {
+			return pc.Next()
+		}
+		return h(pc)
+	}
+}
+
+// Handlers retrieves a copy of registered handlers, in the order they run
 func (p *Pipeline[T]) Handlers() []Handler[T] {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
 	copiedHandlers := make([]Handler[T], len(p.handlers))
-	copy(copiedHandlers, p.handlers)
+	for i, r := range p.handlers {
+		copiedHandlers[i] = r.handler
+	}
 
 	return copiedHandlers
 }
@@ -137,3 +226,36 @@ func (p *Pipeline[T]) Execute(ctx context.Context, payload T, finalizer Handler[
 	// start the pipeline
 	return pc.Next()
 }
+
+// Evaluate runs all handlers in the pipeline like Pipeline.Execute, and returns the result of the pipeline.
+// The result of a successful finalizer is set as the result of the pipeline, which the handlers may read and
+// replace, or set without calling PipelineContext.Next() to answer the request without running the finalizer.
+// The partial result of a failed finalizer, such as the balance of a rejected transaction, is returned along with
+// its error unless a handler set a result, but it is not visible to handlers.
+func Evaluate[R any, T any](ctx context.Context, p *Pipeline[T], payload T, finalizer Finalizer[T, R]) (R, error) {
+	pc := pipelineContext[T]{
+		ctx:     ctx,
+		payload: payload,
+	}
+	var failed R
+	pc.handlers = append(p.Handlers(), func(pc PipelineContext[T]) error {
+		result, err := finalizer(pc)
+		if err != nil {
+			failed = result
+			return err
+		}
+		pc.SetResult(result)
+		return nil
+	})
+
+	err := pc.Next()
+
+	if pc.result == nil {
+		return failed, err
+	}
+	result, ok := pc.result.(R)
+	if !ok {
+		return result, fmt.Errorf("pipeline result of type %T is not a %T", pc.result, result)
+	}
+	return result, err
+}
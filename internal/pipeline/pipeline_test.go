@@ -91,3 +91,137 @@ func Test_Sample(t *testing.T) {
 		return nil
 	})
 }
+
+func Test_Evaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  Handler[int]
+		expected string
+		err      error
+		finalize bool
+	}{
+		{
+			name:     "result of finalizer",
+			handler:  func(pc PipelineContext[int]) error { return pc.Next() },
+			expected: "1",
+			finalize: true,
+		},
+		{
+			name: "handler short-circuits with result",
+			handler: func(pc PipelineContext[int]) error {
+				pc.SetResult("cached")
+				return nil
+			},
+			expected: "cached",
+		},
+		{
+			name: "handler reads and replaces result",
+			handler: func(pc PipelineContext[int]) error {
+				err := pc.Next()
+				result, ok := ResultAs[string](pc)
+				assert.True(t, ok)
+				pc.SetResult(result + " replaced")
+				return err
+			},
+			expected: "1 replaced",
+			finalize: true,
+		},
+		{
+			name: "handler short-circuits with error",
+			handler: func(pc PipelineContext[int]) error {
+				return assert.AnError
+			},
+			err: assert.AnError,
+		},
+		{
+			name: "handler sets result of wrong type",
+			handler: func(pc PipelineContext[int]) error {
+				pc.SetResult(1)
+				return nil
+			},
+			err: fmt.Errorf("pipeline result of type int is not a string"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pipeline := NewPipeline[int]()
+			pipeline.Register(test.handler)
+			finalized := false
+
+			result, err := Evaluate(context.TODO(), pipeline, 1, func(pc PipelineContext[int]) (string, error) {
+				finalized = true
+				return fmt.Sprint(pc.Payload()), nil
+			})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.finalize, finalized)
+		})
+	}
+}
+
+func Test_Evaluate_FinalizerError(t *testing.T) {
+	pipeline := NewPipeline[int]()
+	pipeline.Register(func(pc PipelineContext[int]) error {
+		err := pc.Next()
+		_, ok := ResultAs[*string](pc)
+		assert.False(t, ok, "result of failed finalizer is not set")
+		return err
+	})
+
+	result, err := Evaluate(context.TODO(), pipeline, 1, func(pc PipelineContext[int]) (*string, error) {
+		return nil, assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+	assert.Nil(t, result)
+
+	balance := "balance"
+	result, err = Evaluate(context.TODO(), pipeline, 1, func(pc PipelineContext[int]) (*string, error) {
+		return &balance, assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+	assert.Equal(t, &balance, result, "result of failed finalizer is returned with its error")
+}
+
+func Test_Pipeline_RegisterWith(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  any
+		expected []string
+	}{
+		{
+			name:     "handlers run in order of priority",
+			payload:  1,
+			expected: []string{"high", "int", "first", "second", "low"},
+		},
+		{
+			name:     "conditional handler skipped for other payload types",
+			payload:  "foo",
+			expected: []string{"high", "first", "second", "low"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pipeline := NewPipeline[any]()
+			var called []string
+			handler := func(name string) Handler[any] {
+				return func(pc PipelineContext[any]) error {
+					called = append(called, name)
+					return pc.Next()
+				}
+			}
+			pipeline.Register(handler("first"))
+			pipeline.RegisterWith(Options[any]{Priority: -1}, handler("low"))
+			pipeline.RegisterWith(Options[any]{Priority: 10}, handler("high"))
+			pipeline.RegisterWith(Options[any]{Priority: 1, When: PayloadIs[int]}, handler("int"))
+			pipeline.Register(handler("second"))
+
+			err := pipeline.Execute(context.TODO(), test.payload, func(pc PipelineContext[any]) error {
+				return nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, called)
+		})
+	}
+}
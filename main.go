@@ -35,6 +35,13 @@ const banner = `
      ░                             ░ ░
 `
 
+// commands available as first argument, for example "valkyrie replay"
+var commands = map[string]func(ctx context.Context, args []string, out io.Writer) int{
+	"audit":    auditCommand,
+	"replay":   replayCommand,
+	"simulate": simulateCommand,
+}
+
 func main() {
 	os.Exit(mainReal(listenForSignal(), os.Stdout))
 }
@@ -43,6 +50,12 @@ func mainReal(ctx context.Context, out io.Writer) int {
 	// Load .env.local if found
 	_ = godotenv.Load(".env.local")
 
+	if len(os.Args) > 1 {
+		if command, found := commands[os.Args[1]]; found {
+			return command(ctx, os.Args[2:], out)
+		}
+	}
+
 	versionFlag := flag.Bool("version", false, "Print the version")
 
 	// Read config location
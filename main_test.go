@@ -38,6 +38,30 @@ func TestMainReal(t *testing.T) {
 			1,
 			"Failed to read config",
 		},
+		{
+			"Replaying without recording",
+			[]string{"replay"},
+			2,
+			"replay: -file is required",
+		},
+		{
+			"Simulating without sessions",
+			[]string{"simulate", "-provider", "evolution"},
+			2,
+			"simulate: -provider and -session are required",
+		},
+		{
+			"Verifying audit log without file",
+			[]string{"audit", "verify"},
+			2,
+			"audit verify: -file is required",
+		},
+		{
+			"Verifying audit log with invalid anchor",
+			[]string{"audit", "verify", "-file", "audit.jsonl", "-anchor", "12"},
+			2,
+			"audit verify: invalid -anchor",
+		},
 		{
 			"Starting Valkyrie with test config",
 			[]string{"-config", "./configs/testdata/valkyrie_config.test.yml"},
@@ -0,0 +1,140 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxMinorUnits limits custom currencies to the 18 decimals of ether
+const maxMinorUnits = 18
+
+// Currency with the number of decimals of its minor unit and its rounding rule
+type Currency struct {
+	// Code ISO 4217 code, or code of a custom currency
+	Code string
+	// MinorUnits number of decimals of the minor unit, for example 2 for EUR cents
+	MinorUnits int32
+	// Rounding rule used by Round
+	Rounding Rounding
+	// Custom is set for currencies not part of ISO 4217
+	Custom bool
+}
+
+// Round rounds d to the minor unit of the currency, using its rounding rule
+func (c Currency) Round(d decimal.Decimal) decimal.Decimal {
+	return c.Rounding.Round(d, c.MinorUnits)
+}
+
+// Exact returns d if it can be expressed in minor units of the currency without rounding, otherwise an error
+func (c Currency) Exact(d decimal.Decimal) (decimal.Decimal, error) {
+	return Exact(d, c.MinorUnits)
+}
+
+// ToMinor converts d to minor units of the currency, failing if d has more decimals than the currency
+func (c Currency) ToMinor(d decimal.Decimal) (int64, error) {
+	return ToUnits(d, c.MinorUnits)
+}
+
+// FromMinor converts minor units of the currency to an amount
+func (c Currency) FromMinor(units int64) decimal.Decimal {
+	return FromUnits(units, c.MinorUnits)
+}
+
+func (c Currency) String() string {
+	return c.Code
+}
+
+// iso4217 currencies with their minor units, excluding funds and precious metals without minor units
+var iso4217 = map[string]int32{
+	// currencies without minor units
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0, "RWF": 0,
+	"UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	// currencies with three decimals
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	// currencies with four decimals
+	"CLF": 4, "UYW": 4,
+	// currencies with two decimals
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "AOA": 2, "ARS": 2, "AUD": 2, "AWG": 2, "AZN": 2, "BAM": 2,
+	"BBD": 2, "BDT": 2, "BGN": 2, "BMD": 2, "BND": 2, "BOB": 2, "BOV": 2, "BRL": 2, "BSD": 2, "BTN": 2,
+	"BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2, "CHE": 2, "CHF": 2, "CHW": 2, "CNY": 2, "COP": 2,
+	"COU": 2, "CRC": 2, "CUP": 2, "CVE": 2, "CZK": 2, "DKK": 2, "DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2,
+	"ETB": 2, "EUR": 2, "FJD": 2, "FKP": 2, "GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2, "GMD": 2, "GTQ": 2,
+	"GYD": 2, "HKD": 2, "HNL": 2, "HTG": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "IRR": 2, "JMD": 2,
+	"KES": 2, "KGS": 2, "KHR": 2, "KPW": 2, "KYD": 2, "KZT": 2, "LAK": 2, "LBP": 2, "LKR": 2, "LRD": 2,
+	"LSL": 2, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2, "MMK": 2, "MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2,
+	"MVR": 2, "MWK": 2, "MXN": 2, "MXV": 2, "MYR": 2, "MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2, "NOK": 2,
+	"NPR": 2, "NZD": 2, "PAB": 2, "PEN": 2, "PGK": 2, "PHP": 2, "PKR": 2, "PLN": 2, "QAR": 2, "RON": 2,
+	"RSD": 2, "RUB": 2, "SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2, "SGD": 2, "SHP": 2, "SLE": 2,
+	"SOS": 2, "SRD": 2, "SSP": 2, "STN": 2, "SVC": 2, "SYP": 2, "SZL": 2, "THB": 2, "TJS": 2, "TMT": 2,
+	"TOP": 2, "TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2, "UAH": 2, "USD": 2, "USN": 2, "UYU": 2, "UZS": 2,
+	"VED": 2, "VES": 2, "WST": 2, "XCD": 2, "XCG": 2, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWG": 2,
+}
+
+// custom currencies commonly used by game providers. Crypto currencies round down to never credit more than what
+// the smallest unit allows.
+var custom = []Currency{
+	{Code: "BTC", MinorUnits: 8, Rounding: RoundDown},
+	{Code: "mBTC", MinorUnits: 5, Rounding: RoundDown},
+	{Code: "uBTC", MinorUnits: 2, Rounding: RoundDown},
+	{Code: "BCH", MinorUnits: 8, Rounding: RoundDown},
+	{Code: "LTC", MinorUnits: 8, Rounding: RoundDown},
+	{Code: "DOGE", MinorUnits: 8, Rounding: RoundDown},
+	{Code: "ETH", MinorUnits: 18, Rounding: RoundDown},
+	{Code: "XRP", MinorUnits: 6, Rounding: RoundDown},
+	{Code: "TRX", MinorUnits: 6, Rounding: RoundDown},
+	{Code: "USDT", MinorUnits: 6, Rounding: RoundDown},
+	{Code: "USDC", MinorUnits: 6, Rounding: RoundDown},
+	// play money of demo games
+	{Code: "FUN", MinorUnits: 2},
+}
+
+var (
+	currencies = map[string]Currency{}
+	lock       sync.RWMutex
+)
+
+func init() {
+	for code, minorUnits := range iso4217 {
+		currencies[code] = Currency{Code: code, MinorUnits: minorUnits}
+	}
+	for _, c := range custom {
+		c.Custom = true
+		currencies[c.Code] = c
+	}
+}
+
+// Lookup returns the currency with the code, matching the code as is or in upper case
+func Lookup(code string) (Currency, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	if c, found := currencies[code]; found {
+		return c, true
+	}
+	c, found := currencies[strings.ToUpper(code)]
+	return c, found
+}
+
+// Register adds or replaces a custom currency, such as a crypto or virtual currency. ISO 4217 currencies
+// cannot be replaced.
+func Register(c Currency) error {
+	if c.Code == "" {
+		return fmt.Errorf("currency code is required")
+	}
+	if c.MinorUnits < 0 || c.MinorUnits > maxMinorUnits {
+		return fmt.Errorf("currency '%s' minor units %d not within 0-%d", c.Code, c.MinorUnits, maxMinorUnits)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if existing, found := currencies[c.Code]; found && !existing.Custom {
+		return fmt.Errorf("currency '%s' is an ISO 4217 currency", c.Code)
+	}
+	c.Custom = true
+	currencies[c.Code] = c
+	return nil
+}
@@ -0,0 +1,117 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code       string
+		found      bool
+		minorUnits int32
+		custom     bool
+	}{
+		{code: "EUR", found: true, minorUnits: 2},
+		{code: "sek", found: true, minorUnits: 2},
+		{code: "JPY", found: true, minorUnits: 0},
+		{code: "KWD", found: true, minorUnits: 3},
+		{code: "CLF", found: true, minorUnits: 4},
+		{code: "BTC", found: true, minorUnits: 8, custom: true},
+		{code: "mBTC", found: true, minorUnits: 5, custom: true},
+		{code: "ETH", found: true, minorUnits: 18, custom: true},
+		{code: "XXX"},
+		{code: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			c, found := Lookup(test.code)
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.minorUnits, c.MinorUnits)
+			assert.Equal(t, test.custom, c.Custom)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		err      string
+	}{
+		{name: "custom currency", currency: Currency{Code: "GC", MinorUnits: 0}},
+		{name: "replacing custom currency", currency: Currency{Code: "FUN", MinorUnits: 0}},
+		{name: "ISO currency", currency: Currency{Code: "EUR", MinorUnits: 4}, err: "currency 'EUR' is an ISO 4217 currency"},
+		{name: "missing code", currency: Currency{MinorUnits: 2}, err: "currency code is required"},
+		{name: "too many minor units", currency: Currency{Code: "GC", MinorUnits: 19}, err: "currency 'GC' minor units 19 not within 0-18"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Register(test.currency)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			c, found := Lookup(test.currency.Code)
+			assert.True(t, found)
+			assert.True(t, c.Custom)
+			assert.Equal(t, test.currency.MinorUnits, c.MinorUnits)
+		})
+	}
+}
+
+func TestCurrency_Round(t *testing.T) {
+	tests := []struct {
+		code     string
+		amount   string
+		expected string
+	}{
+		{code: "EUR", amount: "1.005", expected: "1.01"},
+		{code: "EUR", amount: "-1.005", expected: "-1.01"},
+		{code: "JPY", amount: "100.5", expected: "101"},
+		{code: "KWD", amount: "1.2345", expected: "1.235"},
+		{code: "BTC", amount: "0.123456789", expected: "0.12345678"},
+		{code: "mBTC", amount: "1.999999", expected: "1.99999"},
+	}
+	for _, test := range tests {
+		t.Run(test.code+" "+test.amount, func(t *testing.T) {
+			c, found := Lookup(test.code)
+			require.True(t, found)
+			assert.Equal(t, test.expected, c.Round(decimal.RequireFromString(test.amount)).String())
+		})
+	}
+}
+
+func TestCurrency_ToMinor(t *testing.T) {
+	tests := []struct {
+		code     string
+		amount   string
+		expected int64
+		err      string
+	}{
+		{code: "EUR", amount: "12.34", expected: 1234},
+		{code: "EUR", amount: "-0.01", expected: -1},
+		{code: "JPY", amount: "1000", expected: 1000},
+		{code: "BHD", amount: "1.001", expected: 1001},
+		{code: "BTC", amount: "1.00000001", expected: 100000001},
+		{code: "EUR", amount: "0.001", err: "amount 0.001 has more than 2 decimals"},
+		{code: "ETH", amount: "10", err: "amount 10 is too large for units with 18 decimals"},
+	}
+	for _, test := range tests {
+		t.Run(test.code+" "+test.amount, func(t *testing.T) {
+			c, _ := Lookup(test.code)
+			units, err := c.ToMinor(decimal.RequireFromString(test.amount))
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, units)
+			assert.True(t, c.FromMinor(units).Equal(decimal.RequireFromString(test.amount)))
+		})
+	}
+}
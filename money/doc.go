@@ -0,0 +1,9 @@
+// Package money provides currencies with their number of decimals, and exact conversions and rounding of amounts.
+//
+// Currencies are looked up by code using Lookup. The ISO 4217 currencies are built in together with common crypto
+// and virtual currencies, and custom currencies can be added using Register.
+//
+// Amounts are decimal.Decimal values. Conversions to integers, such as amounts in minor units or providers using
+// amounts multiplied by a fixed factor, are done using ToUnits and FromUnits which never lose precision, and rounding
+// is done explicitly using a Rounding, either the Rounding of a Currency or one chosen by the caller.
+package money
@@ -0,0 +1,62 @@
+package fx
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/money"
+)
+
+// Converter converts amounts between currencies using the rates of a RateSource
+type Converter struct {
+	source RateSource
+}
+
+// NewConverter creates a Converter using rates from source
+func NewConverter(source RateSource) *Converter {
+	return &Converter{source: source}
+}
+
+// NewConverterFromConfig creates a Converter using the rate source of config, or nil if nothing is configured
+func NewConverterFromConfig(config configs.FXConf) (*Converter, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+	source, err := GetRateSource(SourceArgs{Config: config})
+	if err != nil {
+		return nil, err
+	}
+	return NewConverter(source), nil
+}
+
+// Rate returns the rate of exchange from one currency to another. Same currencies are exchanged at rate 1.
+func (c *Converter) Rate(ctx context.Context, from, to string) (Rate, error) {
+	if from == to {
+		return Rate{From: from, To: to, Value: decimal.NewFromInt(1), Time: time.Now()}, nil
+	}
+	return c.source.Rate(ctx, from, to)
+}
+
+// Convert converts amount from one currency to another, rounded using the rounding rule of the target currency.
+// It returns the converted amount together with the applied rate. Amounts in the same currency are returned as is.
+func (c *Converter) Convert(ctx context.Context, amount decimal.Decimal, from, to string) (decimal.Decimal, Rate, error) {
+	rate, err := c.Rate(ctx, from, to)
+	if err != nil {
+		return decimal.Zero, Rate{}, err
+	}
+	if from == to {
+		return amount, rate, nil
+	}
+	return rate.Convert(amount, rounding(to)), rate, nil
+}
+
+// rounding returns the rounding rule of currency, rounding half up for currencies unknown to the money package
+func rounding(currency string) money.Rounding {
+	if c, found := money.Lookup(currency); found {
+		return c.Rounding
+	}
+	return money.RoundHalfUp
+}
@@ -0,0 +1,19 @@
+// Package fx converts wallet amounts between the play currency used towards providers and the currency of the
+// player wallet in the PAM.
+//
+// Exchange rates are provided by a RateSource, built from configuration by SourceFactory using the configured
+// "source". The built in "file" source reads rates from a yaml file, which is reloaded when it changes:
+//
+//	base: EUR
+//	rates:
+//	  USD: 1.0832
+//	  SEK: 11.4675
+//	  mBTC: 0.0158
+//
+// A Converter converts amounts using the rates of a RateSource, and an Exchange uses it to convert the amounts of
+// a provider's wallet requests. The play currency is chosen at game launch and is the currency of provider
+// requests, while the wallet currency is the currency of the player session in the PAM. Transactions are converted
+// to the wallet currency with the applied rate and provider amounts recorded on the transaction, and balances are
+// converted back to the play currency. Settlements of a stake, such as payouts and cancels, are converted to the
+// wallet currency of the stake instead of the player session, which may have ended, optionally reusing its rate.
+package fx
@@ -0,0 +1,267 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/money"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	// walletCurrencyTTL how long the wallet currency of a session is cached
+	walletCurrencyTTL = time.Hour
+	// maxWalletCurrencies limits the number of cached sessions
+	maxWalletCurrencies = 10000
+)
+
+// Exchange converts the wallet amounts of a provider between the play currency of its requests and the currency of
+// the player wallet, resolved from the player session in the PAM. A nil Exchange converts nothing, which is the case
+// when currency conversion is not configured.
+type Exchange struct {
+	converter *Converter
+	pamClient pam.PamClient
+	provider  string
+	mu        sync.Mutex
+	wallets   map[string]walletCurrency
+}
+
+type walletCurrency struct {
+	currency string
+	expires  time.Time
+}
+
+// NewExchange creates an Exchange for provider, or nil if converter is nil
+func NewExchange(converter *Converter, pamClient pam.PamClient, provider string) *Exchange {
+	if converter == nil {
+		return nil
+	}
+	return &Exchange{
+		converter: converter,
+		pamClient: pamClient,
+		provider:  provider,
+		wallets:   map[string]walletCurrency{},
+	}
+}
+
+// Transaction wraps mapper, converting the amounts of the mapped transaction from the play currency to the wallet
+// currency. The applied rate and the amounts in play currency are recorded in the CurrencyConversion of the
+// transaction.
+func (e *Exchange) Transaction(mapper pam.AddTransactionRequestMapper) pam.AddTransactionRequestMapper {
+	if e == nil {
+		return mapper
+	}
+	return func(rounder pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		ctx, req, err := mapper(rounder)
+		if err != nil || req == nil {
+			return ctx, req, err
+		}
+		if err = e.toWallet(ctx, req); err != nil {
+			return ctx, nil, err
+		}
+		return ctx, req, nil
+	}
+}
+
+// Balance converts balance from the wallet currency of the player session identified by token to playCurrency, if
+// set. Amounts are rounded down, never reporting more to the provider than is available in the wallet.
+func (e *Exchange) Balance(ctx context.Context, token, correlationID, playCurrency string, balance *pam.Balance) error {
+	if e == nil || balance == nil || playCurrency == "" {
+		return nil
+	}
+	wallet, err := e.walletCurrency(ctx, token, correlationID)
+	if err != nil {
+		return err
+	}
+	return e.toPlay(ctx, wallet, playCurrency, balance)
+}
+
+// Settlement wraps mapper like Transaction, for payouts, refunds and cancels of stake. The wallet currency is taken
+// from the stake instead of the player session, which may have ended. With stakeRate, the rate applied to the stake
+// is reused instead of the current rate, so that refunds and cancels return exactly what was staked.
+func (e *Exchange) Settlement(mapper pam.AddTransactionRequestMapper, stake *pam.Transaction, stakeRate bool) pam.AddTransactionRequestMapper {
+	if e == nil {
+		return mapper
+	}
+	return func(rounder pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		ctx, req, err := mapper(rounder)
+		if err != nil || req == nil {
+			return ctx, req, err
+		}
+		play := req.Body.Currency
+		if play == "" || play == stake.Currency {
+			return ctx, req, nil
+		}
+		rate, err := e.settlementRate(ctx, play, stake, stakeRate)
+		if err != nil {
+			return ctx, nil, err
+		}
+		convertTransaction(&req.Body, rate)
+		return ctx, req, nil
+	}
+}
+
+// SettlementBalance converts balance like Balance, from the wallet currency of stake
+func (e *Exchange) SettlementBalance(ctx context.Context, stake *pam.Transaction, playCurrency string, balance *pam.Balance) error {
+	if e == nil || balance == nil || playCurrency == "" {
+		return nil
+	}
+	return e.toPlay(ctx, stake.Currency, playCurrency, balance)
+}
+
+// settlementRate returns the rate from play to the wallet currency of stake, which must have been converted from play
+func (e *Exchange) settlementRate(ctx context.Context, play string, stake *pam.Transaction, stakeRate bool) (Rate, error) {
+	conversion := stake.CurrencyConversion
+	if conversion == nil || conversion.ProviderCurrency != play {
+		return Rate{}, fmt.Errorf("stake in %s was not converted from %s", stake.Currency, play)
+	}
+	if !stakeRate {
+		return e.converter.Rate(ctx, play, stake.Currency)
+	}
+	value, err := decimal.NewFromString(conversion.Rate)
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid rate of stake: %w", err)
+	}
+	return Rate{From: play, To: stake.Currency, Value: value, Time: conversion.RateTime}, nil
+}
+
+func (e *Exchange) toPlay(ctx context.Context, wallet, play string, balance *pam.Balance) error {
+	if wallet == play {
+		return nil
+	}
+	rate, err := e.converter.Rate(ctx, wallet, play)
+	if err != nil {
+		return err
+	}
+	convert := func(a pam.Amount) pam.Amount {
+		return pam.Amount(rate.Convert(decimal.Decimal(a), money.RoundDown))
+	}
+	balance.CashAmount = convert(balance.CashAmount)
+	balance.BonusAmount = convert(balance.BonusAmount)
+	balance.PromoAmount = convert(balance.PromoAmount)
+	return nil
+}
+
+func (e *Exchange) toWallet(ctx context.Context, req *pam.AddTransactionRequest) error {
+	t := &req.Body
+	play := t.Currency
+	if play == "" {
+		return nil
+	}
+	wallet, err := e.walletCurrency(ctx, req.Params.XPlayerToken, req.Params.XCorrelationID)
+	if err != nil {
+		return err
+	}
+	if wallet == play {
+		return nil
+	}
+	rate, err := e.converter.Rate(ctx, play, wallet)
+	if err != nil {
+		return err
+	}
+	convertTransaction(t, rate)
+	return nil
+}
+
+// convertTransaction converts the amounts of t from the play currency to the wallet currency using rate, recording
+// the conversion on t
+func convertTransaction(t *pam.Transaction, rate Rate) {
+	play, wallet := rate.From, rate.To
+	r := rounding(wallet)
+	convert := func(a pam.Amount) pam.Amount {
+		return pam.Amount(rate.Convert(decimal.Decimal(a), r))
+	}
+	convertPtr := func(a *pam.Amount) *pam.Amount {
+		if a == nil {
+			return nil
+		}
+		c := convert(*a)
+		return &c
+	}
+
+	t.CurrencyConversion = &pam.CurrencyConversion{
+		ProviderCurrency:    play,
+		ProviderCashAmount:  t.CashAmount,
+		ProviderBonusAmount: t.BonusAmount,
+		ProviderPromoAmount: t.PromoAmount,
+		Rate:                rate.Value.String(),
+		RateTime:            rate.Time,
+	}
+	t.Currency = wallet
+	t.CashAmount = convert(t.CashAmount)
+	t.BonusAmount = convert(t.BonusAmount)
+	t.PromoAmount = convert(t.PromoAmount)
+	if t.Tip != nil {
+		t.Tip = &pam.Tip{TipAmount: convertPtr(t.Tip.TipAmount)}
+	}
+	if t.Jackpots != nil {
+		jackpots := make([]pam.Jackpot, len(*t.Jackpots))
+		for i, j := range *t.Jackpots {
+			j.JackpotAmount = convertPtr(j.JackpotAmount)
+			if j.JackpotBuckets != nil {
+				buckets := make([]pam.JackpotBucket, len(*j.JackpotBuckets))
+				for k, b := range *j.JackpotBuckets {
+					if b.Currency == nil || *b.Currency == play {
+						b.BucketAmount = convertPtr(b.BucketAmount)
+						b.Currency = &wallet
+					}
+					buckets[k] = b
+				}
+				j.JackpotBuckets = &buckets
+			}
+			jackpots[i] = j
+		}
+		t.Jackpots = &jackpots
+	}
+	if t.RoundTransactions != nil {
+		roundTransactions := make([]pam.RoundTransaction, len(*t.RoundTransactions))
+		for i, rt := range *t.RoundTransactions {
+			rt.CashAmount = convertPtr(rt.CashAmount)
+			rt.JackpotContribution = convertPtr(rt.JackpotContribution)
+			roundTransactions[i] = rt
+		}
+		t.RoundTransactions = &roundTransactions
+	}
+}
+
+// walletCurrency returns the currency of the player session identified by token, cached to avoid fetching the
+// session for every request
+func (e *Exchange) walletCurrency(ctx context.Context, token, correlationID string) (string, error) {
+	now := time.Now()
+	e.mu.Lock()
+	w, found := e.wallets[token]
+	e.mu.Unlock()
+	if found && now.Before(w.expires) {
+		return w.currency, nil
+	}
+
+	session, err := e.pamClient.GetSession(func() (context.Context, pam.GetSessionRequest, error) {
+		return ctx, pam.GetSessionRequest{Params: pam.GetSessionParams{
+			Provider:       e.provider,
+			XPlayerToken:   token,
+			XCorrelationID: correlationID,
+		}}, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve wallet currency: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.wallets) >= maxWalletCurrencies {
+		for t, w := range e.wallets {
+			if now.After(w.expires) {
+				delete(e.wallets, t)
+			}
+		}
+		if len(e.wallets) >= maxWalletCurrencies {
+			e.wallets = map[string]walletCurrency{}
+		}
+	}
+	e.wallets[token] = walletCurrency{currency: session.Currency, expires: now.Add(walletCurrencyTTL)}
+	return session.Currency, nil
+}
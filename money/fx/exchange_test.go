@@ -0,0 +1,275 @@
+package fx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+type pamStub struct {
+	pam.PamClient
+	currency string
+	err      error
+	sessions int
+}
+
+func (p *pamStub) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, error) {
+	p.sessions++
+	if p.err != nil {
+		return nil, p.err
+	}
+	_, req, _ := rm()
+	return &pam.Session{Token: req.Params.XPlayerToken, Currency: p.currency}, nil
+}
+
+func amount(s string) pam.Amount {
+	return pam.Amount(decimal.RequireFromString(s))
+}
+
+func amountPtr(s string) *pam.Amount {
+	a := amount(s)
+	return &a
+}
+
+func newTestExchange(t *testing.T, stub *pamStub) *Exchange {
+	source, err := NewFileSource("testdata/rates.yaml", 0)
+	require.NoError(t, err)
+	return NewExchange(NewConverter(source), stub, "provider")
+}
+
+func transactionMapper(transaction pam.Transaction) pam.AddTransactionRequestMapper {
+	return func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		return context.Background(), &pam.AddTransactionRequest{
+			Params: pam.AddTransactionParams{XPlayerToken: "token", XCorrelationID: "correlation"},
+			Body:   transaction,
+		}, nil
+	}
+}
+
+func TestExchange_Transaction(t *testing.T) {
+	stub := &pamStub{currency: "SEK"}
+	exchange := newTestExchange(t, stub)
+	eur := "EUR"
+	transaction := pam.Transaction{
+		Currency:    "EUR",
+		CashAmount:  amount("10.123"),
+		BonusAmount: amount("1"),
+		PromoAmount: pam.ZeroAmount,
+		Tip:         &pam.Tip{TipAmount: amountPtr("10.123")},
+		Jackpots: &[]pam.Jackpot{{
+			JackpotAmount:  amountPtr("2"),
+			JackpotBuckets: &[]pam.JackpotBucket{{BucketAmount: amountPtr("0.5"), Currency: &eur}},
+		}},
+		RoundTransactions: &[]pam.RoundTransaction{{CashAmount: amountPtr("3")}},
+	}
+
+	_, req, err := exchange.Transaction(transactionMapper(transaction))(pam.SixDecimalRounder)
+	require.NoError(t, err)
+
+	converted := req.Body
+	assert.Equal(t, "SEK", converted.Currency)
+	assert.Equal(t, "116.41", decimal.Decimal(converted.CashAmount).String())
+	assert.Equal(t, "11.5", decimal.Decimal(converted.BonusAmount).String())
+	assert.Equal(t, "0", decimal.Decimal(converted.PromoAmount).String())
+	assert.Equal(t, "116.41", decimal.Decimal(*converted.Tip.TipAmount).String())
+	jackpot := (*converted.Jackpots)[0]
+	assert.Equal(t, "23", decimal.Decimal(*jackpot.JackpotAmount).String())
+	assert.Equal(t, "5.75", decimal.Decimal(*(*jackpot.JackpotBuckets)[0].BucketAmount).String())
+	assert.Equal(t, "SEK", *(*jackpot.JackpotBuckets)[0].Currency)
+	assert.Equal(t, "34.5", decimal.Decimal(*(*converted.RoundTransactions)[0].CashAmount).String())
+
+	require.NotNil(t, converted.CurrencyConversion)
+	assert.Equal(t, pam.CurrencyConversion{
+		ProviderCurrency:    "EUR",
+		ProviderCashAmount:  amount("10.123"),
+		ProviderBonusAmount: amount("1"),
+		ProviderPromoAmount: pam.ZeroAmount,
+		Rate:                "11.5",
+		RateTime:            time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+	}, *converted.CurrencyConversion)
+
+	assert.Equal(t, "EUR", transaction.Currency, "mapped transaction is not modified")
+	assert.Equal(t, "10.123", decimal.Decimal(*transaction.Tip.TipAmount).String(), "mapped transaction is not modified")
+	assert.Equal(t, "EUR", *(*(*transaction.Jackpots)[0].JackpotBuckets)[0].Currency, "mapped transaction is not modified")
+}
+
+func TestExchange_Transaction_NotConverted(t *testing.T) {
+	transaction := pam.Transaction{Currency: "SEK", CashAmount: amount("10.123")}
+
+	tests := []struct {
+		name     string
+		exchange func(t *testing.T) *Exchange
+		sessions int
+	}{
+		{
+			name:     "not configured",
+			exchange: func(*testing.T) *Exchange { return NewExchange(nil, &pamStub{}, "provider") },
+		},
+		{
+			name:     "play currency is wallet currency",
+			exchange: func(t *testing.T) *Exchange { return newTestExchange(t, &pamStub{currency: "SEK"}) },
+			sessions: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, req, err := tt.exchange(t).Transaction(transactionMapper(transaction))(pam.SixDecimalRounder)
+			require.NoError(t, err)
+			assert.Equal(t, transaction, req.Body)
+		})
+	}
+}
+
+func TestExchange_Transaction_Errors(t *testing.T) {
+	_, req, err := newTestExchange(t, &pamStub{err: assert.AnError}).
+		Transaction(transactionMapper(pam.Transaction{Currency: "EUR"}))(pam.SixDecimalRounder)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, req)
+
+	_, req, err = newTestExchange(t, &pamStub{currency: "NOK"}).
+		Transaction(transactionMapper(pam.Transaction{Currency: "EUR"}))(pam.SixDecimalRounder)
+	assert.ErrorIs(t, err, ErrNoRate)
+	assert.Nil(t, req)
+}
+
+func TestExchange_Settlement(t *testing.T) {
+	stake := &pam.Transaction{
+		TransactionType: pam.WITHDRAW,
+		Currency:        "SEK",
+		CashAmount:      amount("120"),
+		CurrencyConversion: &pam.CurrencyConversion{
+			ProviderCurrency:   "EUR",
+			ProviderCashAmount: amount("10"),
+			Rate:               "12",
+			RateTime:           time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	unconverted := &pam.Transaction{TransactionType: pam.WITHDRAW, Currency: "SEK", CashAmount: amount("10")}
+
+	tests := []struct {
+		name      string
+		stake     *pam.Transaction
+		stakeRate bool
+		currency  string
+		want      string
+		wantRate  string
+		wantErr   string
+	}{
+		{name: "current rate", stake: stake, currency: "EUR", want: "115", wantRate: "11.5"},
+		{name: "rate of stake", stake: stake, stakeRate: true, currency: "EUR", want: "120", wantRate: "12"},
+		{name: "unconverted stake", stake: unconverted, currency: "SEK", want: "10"},
+		{name: "stake not converted from play currency", stake: unconverted, currency: "EUR", wantErr: "stake in SEK was not converted from EUR"},
+		{name: "stake converted from other currency", stake: stake, currency: "USD", wantErr: "stake in SEK was not converted from USD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &pamStub{err: assert.AnError}
+			_, req, err := newTestExchange(t, stub).
+				Settlement(transactionMapper(pam.Transaction{Currency: tt.currency, CashAmount: amount("10")}), tt.stake, tt.stakeRate)(pam.SixDecimalRounder)
+			assert.Zero(t, stub.sessions, "wallet currency is taken from the stake")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, req)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "SEK", req.Body.Currency)
+			assert.Equal(t, tt.want, decimal.Decimal(req.Body.CashAmount).String())
+			if tt.wantRate == "" {
+				assert.Nil(t, req.Body.CurrencyConversion)
+				return
+			}
+			require.NotNil(t, req.Body.CurrencyConversion)
+			assert.Equal(t, tt.wantRate, req.Body.CurrencyConversion.Rate)
+			assert.Equal(t, "10", decimal.Decimal(req.Body.CurrencyConversion.ProviderCashAmount).String())
+		})
+	}
+}
+
+func TestExchange_SettlementBalance(t *testing.T) {
+	stub := &pamStub{err: assert.AnError}
+	balance := pam.Balance{CashAmount: amount("99.99"), BonusAmount: amount("1"), PromoAmount: pam.ZeroAmount}
+
+	err := newTestExchange(t, stub).SettlementBalance(context.Background(), &pam.Transaction{Currency: "SEK"}, "EUR", &balance)
+	require.NoError(t, err)
+	assert.Zero(t, stub.sessions, "wallet currency is taken from the stake")
+	assert.Equal(t, "8.69", decimal.Decimal(balance.CashAmount).String())
+}
+
+func TestExchange_Balance(t *testing.T) {
+	tests := []struct {
+		name         string
+		playCurrency string
+		want         pam.Balance
+		wantErr      error
+	}{
+		{
+			name:         "rounded down to play currency",
+			playCurrency: "EUR",
+			want:         pam.Balance{CashAmount: amount("8.69"), BonusAmount: amount("0.08"), PromoAmount: pam.ZeroAmount},
+		},
+		{
+			name:         "same currency",
+			playCurrency: "SEK",
+			want:         pam.Balance{CashAmount: amount("99.99"), BonusAmount: amount("1"), PromoAmount: pam.ZeroAmount},
+		},
+		{
+			name:         "without play currency",
+			playCurrency: "",
+			want:         pam.Balance{CashAmount: amount("99.99"), BonusAmount: amount("1"), PromoAmount: pam.ZeroAmount},
+		},
+		{
+			name:         "missing rate",
+			playCurrency: "NOK",
+			wantErr:      ErrNoRate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance := pam.Balance{CashAmount: amount("99.99"), BonusAmount: amount("1"), PromoAmount: pam.ZeroAmount}
+			err := newTestExchange(t, &pamStub{currency: "SEK"}).Balance(context.Background(), "token", "correlation", tt.playCurrency, &balance)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, a := range []struct{ want, got pam.Amount }{
+				{tt.want.CashAmount, balance.CashAmount},
+				{tt.want.BonusAmount, balance.BonusAmount},
+				{tt.want.PromoAmount, balance.PromoAmount},
+			} {
+				assert.True(t, decimal.Decimal(a.want).Equal(decimal.Decimal(a.got)), "want %s, got %s", a.want, a.got)
+			}
+		})
+	}
+
+	var exchange *Exchange
+	assert.NoError(t, exchange.Balance(context.Background(), "token", "", "EUR", &pam.Balance{}), "nil exchange converts nothing")
+}
+
+func TestExchange_walletCurrency(t *testing.T) {
+	stub := &pamStub{currency: "SEK"}
+	exchange := newTestExchange(t, stub)
+
+	for i := 0; i < 3; i++ {
+		currency, err := exchange.walletCurrency(context.Background(), "token", "")
+		require.NoError(t, err)
+		assert.Equal(t, "SEK", currency)
+	}
+	assert.Equal(t, 1, stub.sessions, "wallet currency is cached per session")
+
+	_, err := exchange.walletCurrency(context.Background(), "other", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stub.sessions)
+
+	exchange.wallets["token"] = walletCurrency{currency: "SEK", expires: time.Now().Add(-time.Second)}
+	_, err = exchange.walletCurrency(context.Background(), "token", "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, stub.sessions, "expired wallet currency is fetched again")
+}
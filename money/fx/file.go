@@ -0,0 +1,154 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultReloadInterval = time.Minute
+	// divisionPlaces is the precision of cross rates calculated from the rates of a file
+	divisionPlaces = 18
+)
+
+func init() {
+	SourceFactory().Register("file", func(args SourceArgs) (RateSource, error) {
+		var config fileConfig
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+			Result:     &config,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = decoder.Decode(args.Config); err != nil {
+			return nil, fmt.Errorf("invalid fx file config: %w", err)
+		}
+		return NewFileSource(config.File, config.ReloadInterval)
+	})
+}
+
+type fileConfig struct {
+	// File yaml file with the rates
+	File string `mapstructure:"file"`
+	// ReloadInterval how often the file is checked for changes, default 1m
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+// rateFile is the content of a rates file, with rates relative to the base currency
+type rateFile struct {
+	Base string `yaml:"base"`
+	// Time the rates were published, the modification time of the file if not set
+	Time  time.Time                  `yaml:"time"`
+	Rates map[string]decimal.Decimal `yaml:"rates"`
+}
+
+// FileSource provides rates read from a yaml file, which is reloaded when changed
+type FileSource struct {
+	file           string
+	reloadInterval time.Duration
+	mu             sync.Mutex
+	checked        time.Time
+	modTime        time.Time
+	rates          rateFile
+}
+
+// NewFileSource creates a FileSource reading rates from file, checking it for changes every reloadInterval
+func NewFileSource(file string, reloadInterval time.Duration) (*FileSource, error) {
+	if file == "" {
+		return nil, errors.New("fx file is not configured")
+	}
+	if reloadInterval <= 0 {
+		reloadInterval = defaultReloadInterval
+	}
+	s := &FileSource{file: file, reloadInterval: reloadInterval}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Rate returns the rate from one currency to another, cross calculated from their rates against the base currency
+func (s *FileSource) Rate(_ context.Context, from, to string) (Rate, error) {
+	s.reloadIfChanged()
+	s.mu.Lock()
+	rates := s.rates
+	s.mu.Unlock()
+
+	fromRate, fromFound := rates.rate(from)
+	toRate, toFound := rates.rate(to)
+	if !fromFound || !toFound {
+		return Rate{}, fmt.Errorf("%w from %s to %s", ErrNoRate, from, to)
+	}
+	return Rate{From: from, To: to, Value: toRate.DivRound(fromRate, divisionPlaces), Time: rates.Time}, nil
+}
+
+// rate returns the rate of currency against the base currency
+func (r rateFile) rate(currency string) (decimal.Decimal, bool) {
+	if currency == r.Base {
+		return decimal.NewFromInt(1), true
+	}
+	rate, found := r.Rates[currency]
+	return rate, found
+}
+
+// reloadIfChanged reloads the file if the reload interval has passed and it changed.
+// Failing reloads keep the previously loaded rates.
+func (s *FileSource) reloadIfChanged() {
+	s.mu.Lock()
+	due := time.Since(s.checked) >= s.reloadInterval
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+	if err := s.load(); err != nil {
+		log.Warn().Err(err).Msg("Failed to reload fx rates, using previously loaded")
+	}
+}
+
+// load (re)loads the rates if the file changed since last load
+func (s *FileSource) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checked = time.Now()
+
+	info, err := os.Stat(s.file)
+	if err != nil {
+		return fmt.Errorf("failed to read fx file: %w", err)
+	}
+	if info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	content, err := os.ReadFile(s.file)
+	if err != nil {
+		return fmt.Errorf("failed to read fx file: %w", err)
+	}
+	var rates rateFile
+	if err = yaml.Unmarshal(content, &rates); err != nil {
+		return fmt.Errorf("failed to parse fx file '%s': %w", s.file, err)
+	}
+	if rates.Base == "" {
+		return fmt.Errorf("fx file '%s' has no base currency", s.file)
+	}
+	for currency, rate := range rates.Rates {
+		if !rate.IsPositive() {
+			return fmt.Errorf("fx file '%s' has non positive rate %s for %s", s.file, rate, currency)
+		}
+	}
+	if rates.Time.IsZero() {
+		rates.Time = info.ModTime()
+	}
+
+	s.rates, s.modTime = rates, info.ModTime()
+	return nil
+}
@@ -0,0 +1,165 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSource_Rate(t *testing.T) {
+	source, err := NewFileSource("testdata/rates.yaml", 0)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    string
+		wantErr error
+	}{
+		{name: "from base", from: "EUR", to: "SEK", want: "11.5"},
+		{name: "to base", from: "SEK", to: "EUR", want: "0.086956521739130435"},
+		{name: "cross rate", from: "USD", to: "SEK", want: "9.2"},
+		{name: "custom currency", from: "EUR", to: "mBTC", want: "0.02"},
+		{name: "unknown currency", from: "EUR", to: "NOK", wantErr: ErrNoRate},
+		{name: "codes are case sensitive", from: "EUR", to: "sek", wantErr: ErrNoRate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := source.Rate(context.Background(), tt.from, tt.to)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, rate.From)
+			assert.Equal(t, tt.to, rate.To)
+			assert.Equal(t, tt.want, rate.Value.String())
+			assert.Equal(t, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), rate.Time.UTC())
+		})
+	}
+}
+
+func TestFileSource_Reload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rates.yaml")
+	writeRates := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+	}
+	modTime := time.Now().Add(-time.Hour)
+	writeRates("base: EUR\nrates:\n  SEK: 11.5\n", modTime)
+
+	source, err := NewFileSource(file, time.Nanosecond)
+	require.NoError(t, err)
+	rate, err := source.Rate(context.Background(), "EUR", "SEK")
+	require.NoError(t, err)
+	assert.Equal(t, "11.5", rate.Value.String())
+	assert.True(t, modTime.Equal(rate.Time), "rates without time use the modification time of the file")
+
+	writeRates("base: EUR\nrates:\n  SEK: 11.75\n", modTime.Add(time.Minute))
+	rate, err = source.Rate(context.Background(), "EUR", "SEK")
+	require.NoError(t, err)
+	assert.Equal(t, "11.75", rate.Value.String(), "changed file is reloaded")
+
+	writeRates("base: EUR\nrates:\n  SEK: -1\n", modTime.Add(2*time.Minute))
+	rate, err = source.Rate(context.Background(), "EUR", "SEK")
+	require.NoError(t, err)
+	assert.Equal(t, "11.75", rate.Value.String(), "invalid file keeps previous rates")
+}
+
+func TestNewFileSource(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: "base: EUR\nrates:\n  SEK: 11.5\n"},
+		{name: "without rates", content: "base: EUR\n"},
+		{name: "without base", content: "rates:\n  SEK: 11.5\n", wantErr: "has no base currency"},
+		{name: "zero rate", content: "base: EUR\nrates:\n  SEK: 0\n", wantErr: "non positive rate 0 for SEK"},
+		{name: "invalid rate", content: "base: EUR\nrates:\n  SEK: abc\n", wantErr: "failed to parse fx file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+			_, err := NewFileSource(file, 0)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, err := NewFileSource(filepath.Join(dir, "missing.yaml"), 0)
+	assert.ErrorContains(t, err, "failed to read fx file")
+	_, err = NewFileSource("", 0)
+	assert.ErrorContains(t, err, "fx file is not configured")
+}
+
+func TestNewConverterFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		wantNil bool
+		wantErr string
+	}{
+		{name: "not configured", config: nil, wantNil: true},
+		{name: "file source", config: map[string]any{"source": "file", "file": "testdata/rates.yaml", "reload_interval": "10s"}},
+		{name: "missing source", config: map[string]any{"file": "testdata/rates.yaml"}, wantErr: `required fx field "source" not found`},
+		{name: "unknown source", config: map[string]any{"source": "bank"}, wantErr: "'bank' not found"},
+		{name: "invalid reload interval", config: map[string]any{"source": "file", "file": "testdata/rates.yaml", "reload_interval": "often"}, wantErr: "invalid fx file config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverterFromConfig(tt.config)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNil, converter == nil)
+		})
+	}
+}
+
+func TestConverter_Convert(t *testing.T) {
+	source, err := NewFileSource("testdata/rates.yaml", 0)
+	require.NoError(t, err)
+	converter := NewConverter(source)
+
+	tests := []struct {
+		name    string
+		amount  string
+		from    string
+		to      string
+		want    string
+		wantErr bool
+	}{
+		{name: "rounded half up to cents", amount: "10.123", from: "EUR", to: "SEK", want: "116.41"},
+		{name: "same currency", amount: "10.123456", from: "EUR", to: "EUR", want: "10.123456"},
+		{name: "custom currency rounds down", amount: "0.0299999", from: "EUR", to: "mBTC", want: "0.00059"},
+		{name: "custom currency", amount: "12.3456", from: "EUR", to: "mBTC", want: "0.24691"},
+		{name: "missing rate", amount: "1", from: "EUR", to: "NOK", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rate, err := converter.Convert(context.Background(), decimal.RequireFromString(tt.amount), tt.from, tt.to)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNoRate)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+			assert.Equal(t, tt.to, rate.To)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package fx
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/money"
+)
+
+// amountPlaces is the precision of converted amounts in currencies unknown to the money package
+const amountPlaces = 6
+
+// Rate of exchange from one currency to another, where an amount in From multiplied by Value is the amount in To
+type Rate struct {
+	From  string
+	To    string
+	Value decimal.Decimal
+	// Time the rate was published by its source
+	Time time.Time
+}
+
+// Convert converts amount in the From currency to the To currency, rounded to the minor units of To using rounding
+func (r Rate) Convert(amount decimal.Decimal, rounding money.Rounding) decimal.Decimal {
+	places := int32(amountPlaces)
+	if c, found := money.Lookup(r.To); found {
+		places = c.MinorUnits
+	}
+	return rounding.Round(amount.Mul(r.Value), places)
+}
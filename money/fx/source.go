@@ -0,0 +1,59 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/internal"
+)
+
+// ErrNoRate is returned by rate sources without a rate between two currencies
+var ErrNoRate = errors.New("no exchange rate")
+
+// RateSource provides exchange rates between currencies
+type RateSource interface {
+	// Rate returns the current rate of exchange from one currency to another
+	Rate(ctx context.Context, from, to string) (Rate, error)
+}
+
+// SourceArgs composes all arguments required to build a rate source
+type SourceArgs struct {
+	Config configs.FXConf
+}
+
+type sourceFactory = internal.AbstractFactory[SourceArgs, RateSource]
+
+var (
+	once    sync.Once
+	factory *sourceFactory
+)
+
+// SourceFactory returns a single instance to the rate source factory
+func SourceFactory() *sourceFactory {
+	// Make the factory a singleton
+	once.Do(func() {
+		factory = internal.NewAbstractFactory[SourceArgs, RateSource]()
+	})
+
+	return factory
+}
+
+// GetRateSource builds the rate source selected by the "source" of the configuration
+func GetRateSource(args SourceArgs) (RateSource, error) {
+	val, found := args.Config["source"]
+	if !found {
+		return nil, fmt.Errorf("required fx field \"source\" not found")
+	}
+	name, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("fx field \"source\" has unknown type %v", val)
+	}
+	source, err := SourceFactory().Build(name, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build fx rate source: %w", err)
+	}
+	return source, nil
+}
@@ -0,0 +1,73 @@
+package money
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rounding rule used when an amount has more decimals than allowed
+type Rounding int
+
+const (
+	// RoundHalfUp rounds to the nearest value, halves away from zero
+	RoundHalfUp Rounding = iota
+	// RoundHalfEven rounds to the nearest value, halves to the nearest even value (bankers rounding)
+	RoundHalfEven
+	// RoundDown rounds towards zero, truncating the amount
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+// Round rounds d to the number of decimal places
+func (r Rounding) Round(d decimal.Decimal, places int32) decimal.Decimal {
+	switch r {
+	case RoundHalfEven:
+		return d.RoundBank(places)
+	case RoundDown:
+		return d.RoundDown(places)
+	case RoundUp:
+		return d.RoundUp(places)
+	default:
+		return d.Round(places)
+	}
+}
+
+func (r Rounding) String() string {
+	switch r {
+	case RoundHalfUp:
+		return "half_up"
+	case RoundHalfEven:
+		return "half_even"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	default:
+		return fmt.Sprintf("Rounding(%d)", int(r))
+	}
+}
+
+// Exact returns d if it has at most the number of decimal places, otherwise an error since rounding it would
+// lose precision
+func Exact(d decimal.Decimal, places int32) (decimal.Decimal, error) {
+	rounded := d.Round(places)
+	if !rounded.Equal(d) {
+		return decimal.Zero, fmt.Errorf("rounding will result in lost precision %s -> %s", d, rounded)
+	}
+	return rounded, nil
+}
+
+// Format formats d with at least minPlaces decimals, and more if needed to not lose precision
+func Format(d decimal.Decimal, minPlaces int32) string {
+	places := minPlaces
+	if exp := -d.Exponent(); exp > places {
+		places = exp
+	}
+	// Drop trailing zeros beyond minPlaces
+	for places > minPlaces && d.Truncate(places-1).Equal(d) {
+		places--
+	}
+	return d.StringFixed(places)
+}
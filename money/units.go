@@ -0,0 +1,26 @@
+package money
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ToUnits converts d to an integer number of units, where a unit is 10^-places, for example cents with 2 places.
+// Fails if d has more decimals than places or does not fit in an int64, round d first to convert it anyway.
+func ToUnits(d decimal.Decimal, places int32) (int64, error) {
+	shifted := d.Shift(places)
+	if !shifted.IsInteger() {
+		return 0, fmt.Errorf("amount %s has more than %d decimals", d, places)
+	}
+	units := shifted.BigInt()
+	if !units.IsInt64() {
+		return 0, fmt.Errorf("amount %s is too large for units with %d decimals", d, places)
+	}
+	return units.Int64(), nil
+}
+
+// FromUnits converts an integer number of units, where a unit is 10^-places, to an amount
+func FromUnits(units int64, places int32) decimal.Decimal {
+	return decimal.New(units, -places)
+}
@@ -0,0 +1,109 @@
+package money
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// places generates a number of decimal places within the supported range
+func places(r *rand.Rand) int32 {
+	return int32(r.Intn(maxMinorUnits + 1))
+}
+
+func TestFromUnits_ToUnits_RoundTrip(t *testing.T) {
+	property := func(units int64, seed int64) bool {
+		p := places(rand.New(rand.NewSource(seed)))
+		converted, err := ToUnits(FromUnits(units, p), p)
+		return err == nil && converted == units
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestToUnits_FromUnits_RoundTrip(t *testing.T) {
+	property := func(value int32, exp uint8, seed int64) bool {
+		// at most 9 places for int32 values to fit in int64 units
+		p := places(rand.New(rand.NewSource(seed))) % 10
+		// amounts with at most p decimals
+		d := decimal.New(int64(value), -int32(exp)%(p+1))
+		units, err := ToUnits(d, p)
+		return err == nil && FromUnits(units, p).Equal(d)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestToUnits_LostPrecision(t *testing.T) {
+	property := func(value int32, seed int64) bool {
+		if value%10 == 0 {
+			value++
+		}
+		p := places(rand.New(rand.NewSource(seed)))
+		_, err := ToUnits(decimal.New(int64(value), -p-1), p)
+		return err != nil
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount    string
+		minPlaces int32
+		expected  string
+	}{
+		{amount: "1", minPlaces: 2, expected: "1.00"},
+		{amount: "1.5", minPlaces: 2, expected: "1.50"},
+		{amount: "1.234", minPlaces: 2, expected: "1.234"},
+		{amount: "1.2340000", minPlaces: 2, expected: "1.234"},
+		{amount: "0.00000001", minPlaces: 6, expected: "0.00000001"},
+		{amount: "-12.5", minPlaces: 0, expected: "-12.5"},
+		{amount: "1000", minPlaces: 0, expected: "1000"},
+	}
+	for _, test := range tests {
+		t.Run(test.amount, func(t *testing.T) {
+			assert.Equal(t, test.expected, Format(decimal.RequireFromString(test.amount), test.minPlaces))
+		})
+	}
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	property := func(value int64, exp uint8, minPlaces uint8) bool {
+		d := decimal.New(value, -int32(exp%20))
+		parsed, err := decimal.NewFromString(Format(d, int32(minPlaces%10)))
+		return err == nil && parsed.Equal(d)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestExact(t *testing.T) {
+	_, err := Exact(decimal.RequireFromString("1.0000001"), 6)
+	assert.EqualError(t, err, "rounding will result in lost precision 1.0000001 -> 1")
+
+	d, err := Exact(decimal.RequireFromString("1.000001"), 6)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.000001", d.String())
+}
+
+func TestRounding_Round(t *testing.T) {
+	tests := []struct {
+		rounding Rounding
+		amount   string
+		expected string
+	}{
+		{rounding: RoundHalfUp, amount: "2.5", expected: "3"},
+		{rounding: RoundHalfUp, amount: "-2.5", expected: "-3"},
+		{rounding: RoundHalfEven, amount: "2.5", expected: "2"},
+		{rounding: RoundHalfEven, amount: "3.5", expected: "4"},
+		{rounding: RoundDown, amount: "2.9", expected: "2"},
+		{rounding: RoundDown, amount: "-2.9", expected: "-2"},
+		{rounding: RoundUp, amount: "2.1", expected: "3"},
+		{rounding: RoundUp, amount: "-2.1", expected: "-3"},
+	}
+	for _, test := range tests {
+		t.Run(test.rounding.String()+" "+test.amount, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.rounding.Round(decimal.RequireFromString(test.amount), 0).String())
+		})
+	}
+}
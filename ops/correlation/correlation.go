@@ -0,0 +1,66 @@
+package correlation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header of the correlation ID in requests and responses
+	Header = "X-Correlation-ID"
+	// RequestIDHeader alternative header the correlation ID is taken from
+	RequestIDHeader = "X-Request-ID"
+
+	maxLength = 128
+)
+
+type idKey struct{}
+
+type providerRequestIDKey struct{}
+
+// NewID generates a correlation ID
+func NewID() string {
+	return uuid.NewString()
+}
+
+// Valid returns if id can be used as correlation ID, being at most 128 printable ASCII characters without spaces
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithID returns ctx with the correlation ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// ID returns the correlation ID of ctx, or "" if there is none
+func ID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// WithProviderRequestID returns ctx with the native request ID of the provider
+func WithProviderRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, providerRequestIDKey{}, id)
+}
+
+// ProviderRequestID returns the native request ID of the provider of ctx, or "" if there is none
+func ProviderRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(providerRequestIDKey{}).(string)
+	return id
+}
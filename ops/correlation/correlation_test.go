@@ -0,0 +1,40 @@
+package correlation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"3f1c2a9e-0b7d-4c55-9a51-6c3e1f2b8d40", true},
+		{"req:42/a", true},
+		{"", false},
+		{"with space", false},
+		{"new\nline", false},
+		{"ümlaut", false},
+		{strings.Repeat("a", 129), false},
+	}
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			assert.Equal(t, test.want, Valid(test.id))
+		})
+	}
+	assert.True(t, Valid(NewID()))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, ID(ctx))
+	assert.Empty(t, ProviderRequestID(ctx))
+
+	ctx = WithProviderRequestID(WithID(ctx, "correlation"), "native")
+	assert.Equal(t, "correlation", ID(ctx))
+	assert.Equal(t, "native", ProviderRequestID(ctx))
+}
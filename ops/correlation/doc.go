@@ -0,0 +1,7 @@
+// Package correlation keeps the correlation ID of a request in its context, so that it is propagated to PAM
+// calls, outbound requests, logs and responses.
+//
+// The correlation ID is taken from the X-Correlation-ID or X-Request-ID header of inbound requests, or generated
+// when missing. The native request ID of a provider, such as the request UUID of Evolution or Caleta, is kept
+// separately as the provider request ID.
+package correlation
@@ -0,0 +1,57 @@
+package ops
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
+)
+
+const (
+	correlationIDFieldName        = "correlationId"
+	providerRequestIDFieldName    = "providerRequestId"
+	correlationIDAttributeKey     = attribute.Key("valkyrie.correlation_id")
+	providerRequestIDAttributeKey = attribute.Key("valkyrie.provider.request_id")
+)
+
+// CorrelationMiddleware gives every request a correlation ID, taken from the X-Correlation-ID or X-Request-ID
+// header or else generated. It is kept in the request context, added to logs and the request span, and returned
+// in the X-Correlation-ID response header. Needs to be added before LoggingMiddleware.
+func CorrelationMiddleware(apps ...*fiber.App) {
+	for _, app := range apps {
+		app.Use(correlationContext)
+	}
+}
+
+func correlationContext(c *fiber.Ctx) error {
+	id := c.Get(correlation.Header)
+	if !correlation.Valid(id) {
+		id = c.Get(correlation.RequestIDHeader)
+	}
+	if !correlation.Valid(id) {
+		id = correlation.NewID()
+	}
+
+	ctx := correlation.WithID(c.UserContext(), id)
+	l := log.Ctx(ctx).With().Str(correlationIDFieldName, id).Logger()
+	c.SetUserContext(l.WithContext(ctx))
+	trace.SpanFromContext(ctx).SetAttributes(correlationIDAttributeKey.String(id))
+	c.Set(correlation.Header, id)
+
+	return c.Next()
+}
+
+// withProviderRequestID records the native request ID of a provider in ctx, its logger and its span
+func withProviderRequestID(ctx context.Context, id string) context.Context {
+	if id == "" || id == correlation.ProviderRequestID(ctx) {
+		return ctx
+	}
+	ctx = correlation.WithProviderRequestID(ctx, id)
+	trace.SpanFromContext(ctx).SetAttributes(providerRequestIDAttributeKey.String(id))
+	l := log.Ctx(ctx).With().Str(providerRequestIDFieldName, id).Logger()
+	return l.WithContext(ctx)
+}
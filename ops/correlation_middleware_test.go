@@ -0,0 +1,132 @@
+package ops
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func TestCorrelationMiddleware(t *testing.T) {
+	captor := &strings.Builder{}
+	previous := zerolog.DefaultContextLogger
+	logger := zerolog.New(captor)
+	zerolog.DefaultContextLogger = &logger
+	t.Cleanup(func() { zerolog.DefaultContextLogger = previous })
+
+	app := fiber.New()
+	CorrelationMiddleware(app)
+	app.Get("/", func(c *fiber.Ctx) error {
+		zerolog.Ctx(c.UserContext()).Info().Msg("handled")
+		return c.SendString(correlation.ID(c.UserContext()))
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"correlation id header", map[string]string{correlation.Header: "abc-123", correlation.RequestIDHeader: "req-1"}, "abc-123"},
+		{"request id header", map[string]string{correlation.RequestIDHeader: "req-1"}, "req-1"},
+		{"invalid header", map[string]string{correlation.Header: "not valid"}, ""},
+		{"generated", nil, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			captor.Reset()
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			id := string(body)
+			if test.want != "" {
+				assert.Equal(t, test.want, id)
+			} else {
+				assert.True(t, correlation.Valid(id))
+				assert.NotEqual(t, "not valid", id)
+			}
+			assert.Equal(t, id, resp.Header.Get(correlation.Header))
+			assert.Contains(t, captor.String(), `"correlationId":"`+id+`"`)
+		})
+	}
+}
+
+// correlationPamStub captures the context and request mapped by AddTransaction
+type correlationPamStub struct {
+	pam.PamClient
+	ctx context.Context
+	req *pam.AddTransactionRequest
+}
+
+func (p *correlationPamStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	var err error
+	p.ctx, p.req, err = rm(pam.SixDecimalRounder)
+	return &pam.TransactionResult{}, err
+}
+
+func TestCorrelatePAMRequests(t *testing.T) {
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		native            string
+		correlationID     string
+		providerRequestID string
+	}{
+		{"native request id", correlation.WithID(context.Background(), "corr"), "uuid-1", "corr", "uuid-1"},
+		{"no native request id", correlation.WithID(context.Background(), "corr"), "", "corr", ""},
+		{"outside request", context.Background(), "uuid-1", "uuid-1", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stub := &correlationPamStub{}
+			_, err := CorrelatePAMRequests(stub).AddTransaction(func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+				return test.ctx, &pam.AddTransactionRequest{Params: pam.AddTransactionParams{XCorrelationID: test.native}}, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, test.correlationID, stub.req.Params.XCorrelationID)
+			assert.Equal(t, test.providerRequestID, correlation.ProviderRequestID(stub.ctx))
+		})
+	}
+}
+
+func Test_httpCorrelationHandler(t *testing.T) {
+	handler := HTTPCorrelationHandler[FastHTTPPayload]()
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"added", "", "corr"},
+		{"already set", "pam-corr", "pam-corr"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := fasthttp.AcquireRequest()
+			if test.header != "" {
+				req.Header.Set(correlation.Header, test.header)
+			}
+			pc := &mockPipelineContext[FastHTTPPayload]{
+				ctx:     correlation.WithID(context.Background(), "corr"),
+				payload: mockPayload{request: req, response: fasthttp.AcquireResponse()},
+			}
+
+			assert.NoError(t, handler(pc))
+			assert.Equal(t, test.want, string(req.Header.Peek(correlation.Header)))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ops
+
+import (
+	"context"
+
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// correlatingPamClient propagates the correlation ID of requests to the PAM
+type correlatingPamClient struct {
+	pam.PamClient
+}
+
+// CorrelatePAMRequests wraps client, sending the correlation ID of the request context to the PAM. Providers map
+// their native request ID, if any, to the correlation ID of PAM requests, which is instead recorded as the provider
+// request ID. Needs to wrap the other PAM client decorators, so that they see the correlation ID.
+func CorrelatePAMRequests(client pam.PamClient) pam.PamClient {
+	return &correlatingPamClient{PamClient: client}
+}
+
+// correlate returns the correlation ID to send to the PAM, with ctx recording the native request ID of the provider
+func correlate(ctx context.Context, native string) (context.Context, string) {
+	id := correlation.ID(ctx)
+	if ctx == nil || id == "" {
+		// outside requests, such as in tests, the native request ID is used
+		return ctx, native
+	}
+	return withProviderRequestID(ctx, native), id
+}
+
+func (c *correlatingPamClient) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, error) {
+	return c.PamClient.GetSession(func() (context.Context, pam.GetSessionRequest, error) {
+		ctx, req, err := rm()
+		ctx, req.Params.XCorrelationID = correlate(ctx, req.Params.XCorrelationID)
+		return ctx, req, err
+	})
+}
+
+func (c *correlatingPamClient) RefreshSession(rm pam.RefreshSessionRequestMapper) (*pam.Session, error) {
+	return c.PamClient.RefreshSession(func() (context.Context, pam.RefreshSessionRequest, error) {
+		ctx, req, err := rm()
+		ctx, req.Params.XCorrelationID = correlate(ctx, req.Params.XCorrelationID)
+		return ctx, req, err
+	})
+}
+
+func (c *correlatingPamClient) GetBalance(rm pam.GetBalanceRequestMapper) (*pam.Balance, error) {
+	return c.PamClient.GetBalance(func() (context.Context, pam.GetBalanceRequest, error) {
+		ctx, req, err := rm()
+		ctx, req.Params.XCorrelationID = correlate(ctx, req.Params.XCorrelationID)
+		return ctx, req, err
+	})
+}
+
+func (c *correlatingPamClient) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam.Transaction, error) {
+	return c.PamClient.GetTransactions(func() (context.Context, pam.GetTransactionsRequest, error) {
+		ctx, req, err := rm()
+		ctx, req.Params.XCorrelationID = correlate(ctx, req.Params.XCorrelationID)
+		return ctx, req, err
+	})
+}
+
+func (c *correlatingPamClient) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	return c.PamClient.AddTransaction(func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		ctx, req, err := rm(r)
+		if req != nil {
+			ctx, req.Params.XCorrelationID = correlate(ctx, req.Params.XCorrelationID)
+		}
+		return ctx, req, err
+	})
+}
+
+func (c *correlatingPamClient) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRound, error) {
+	return c.PamClient.GetGameRound(func() (context.Context, pam.GetGameRoundRequest, error) {
+		ctx, req, err := rm()
+		ctx, req.Params.XCorrelationID = correlate(ctx, req.Params.XCorrelationID)
+		return ctx, req, err
+	})
+}
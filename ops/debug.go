@@ -0,0 +1,250 @@
+package ops
+
+import (
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DebugKindProvider debug window of the requests to a provider
+	DebugKindProvider = "provider"
+	// DebugKindPlayer debug window of the requests of a player
+	DebugKindPlayer = "player"
+)
+
+var (
+	// logLevel is the level of logging outside debug windows
+	logLevel atomic.Int32
+	// debugOutput is the log output of debug windows, which is not filtered by logLevel
+	debugOutput io.Writer
+	// debugWindows currently enabled
+	debugWindows = &windows{entries: map[windowKey]time.Time{}}
+	// pprofEnabled enables the pprof endpoints of the operator server
+	pprofEnabled atomic.Bool
+)
+
+func init() {
+	logLevel.Store(int32(zerolog.TraceLevel))
+	_, pprof := os.LookupEnv("PPROF")
+	pprofEnabled.Store(pprof)
+}
+
+// SetLogLevel changes the level of logging, which stays lowered to debug while debug windows are enabled
+func SetLogLevel(level zerolog.Level) {
+	logLevel.Store(int32(level))
+	applyLogLevel()
+}
+
+// LogLevel returns the level of logging outside debug windows
+func LogLevel() zerolog.Level {
+	return zerolog.Level(logLevel.Load())
+}
+
+// applyLogLevel sets the global level, lowered to debug while debug windows are enabled so that their debug
+// logging is not discarded. Debug logging outside the windows is then filtered by levelFilterWriter.
+func applyLogLevel() {
+	level := LogLevel()
+	if debugWindows.any() && level > zerolog.DebugLevel {
+		level = zerolog.DebugLevel
+	}
+	zerolog.SetGlobalLevel(level)
+}
+
+// SetPprofEnabled turns the pprof endpoints on or off
+func SetPprofEnabled(enabled bool) {
+	pprofEnabled.Store(enabled)
+}
+
+// PprofEnabled returns if the pprof endpoints are on, initially if the PPROF environment variable is set
+func PprofEnabled() bool {
+	return pprofEnabled.Load()
+}
+
+// levelFilterWriter discards events below logLevel, which are only logged while debug windows are enabled
+type levelFilterWriter struct {
+	io.Writer
+}
+
+func (w levelFilterWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < LogLevel() {
+		return len(p), nil
+	}
+	if lw, ok := w.Writer.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Writer.Write(p)
+}
+
+// DebugWindow debug logging of the requests to a provider, or of a player, until a point in time
+type DebugWindow struct {
+	Kind  string    `json:"kind"`
+	ID    string    `json:"id"`
+	Until time.Time `json:"until"`
+	// Routes of the provider
+	Routes []string `json:"routes,omitempty"`
+}
+
+type windowKey struct {
+	kind string
+	id   string
+}
+
+// windows keeps the enabled debug windows, with the routes of the provider windows
+type windows struct {
+	mu      sync.RWMutex
+	entries map[windowKey]time.Time
+	routes  map[string][]string
+}
+
+func (w *windows) any() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	now := time.Now()
+	for _, until := range w.entries {
+		if now.Before(until) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *windows) active(kind, id string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	until, found := w.entries[windowKey{kind, id}]
+	return found && time.Now().Before(until)
+}
+
+// provider returns if path is a route of a provider with an enabled debug window
+func (w *windows) provider(path string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	now := time.Now()
+	for key, until := range w.entries {
+		if key.kind != DebugKindProvider || !now.Before(until) {
+			continue
+		}
+		for _, route := range w.routes[key.id] {
+			if strings.HasPrefix(path, route) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EnableProviderDebug enables debug logging of requests to the routes of a provider for a duration
+func EnableProviderDebug(provider string, routes []string, duration time.Duration) DebugWindow {
+	debugWindows.mu.Lock()
+	if debugWindows.routes == nil {
+		debugWindows.routes = map[string][]string{}
+	}
+	debugWindows.routes[provider] = routes
+	debugWindows.mu.Unlock()
+	return enableDebug(DebugKindProvider, provider, duration)
+}
+
+// EnablePlayerDebug enables debug logging of requests of a player for a duration. Requests are only known to be of
+// the player once the PAM has been called, which is why requests are logged after they have been handled.
+func EnablePlayerDebug(playerID string, duration time.Duration) DebugWindow {
+	return enableDebug(DebugKindPlayer, playerID, duration)
+}
+
+func enableDebug(kind, id string, duration time.Duration) DebugWindow {
+	until := time.Now().Add(duration)
+	window := DebugWindow{Kind: kind, ID: id, Until: until}
+	debugWindows.mu.Lock()
+	debugWindows.entries[windowKey{kind, id}] = until
+	if kind == DebugKindProvider {
+		window.Routes = debugWindows.routes[id]
+	}
+	debugWindows.mu.Unlock()
+
+	applyLogLevel()
+	// restore the log level when the window ends
+	time.AfterFunc(duration, applyLogLevel)
+	return window
+}
+
+// DisableDebug ends a debug window, returning false if it was not enabled
+func DisableDebug(kind, id string) bool {
+	debugWindows.mu.Lock()
+	key := windowKey{kind, id}
+	until, found := debugWindows.entries[key]
+	delete(debugWindows.entries, key)
+	debugWindows.mu.Unlock()
+
+	applyLogLevel()
+	return found && time.Now().Before(until)
+}
+
+// DebugWindows returns the enabled debug windows, removing the ended ones
+func DebugWindows() []DebugWindow {
+	debugWindows.mu.Lock()
+	defer debugWindows.mu.Unlock()
+	now := time.Now()
+	result := []DebugWindow{}
+	for key, until := range debugWindows.entries {
+		if !now.Before(until) {
+			delete(debugWindows.entries, key)
+			continue
+		}
+		window := DebugWindow{Kind: key.kind, ID: key.id, Until: until}
+		if key.kind == DebugKindProvider {
+			window.Routes = debugWindows.routes[key.id]
+		}
+		result = append(result, window)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// debugLogger returns a logger of l logging debug events regardless of the log level
+func debugLogger(l zerolog.Logger) zerolog.Logger {
+	if debugOutput != nil {
+		l = l.Output(debugOutput)
+	}
+	return l.Level(zerolog.DebugLevel)
+}
+
+type debugMarkKey struct{}
+
+// debugMark is set when a request turns out to be of a player with a debug window
+type debugMark struct {
+	player atomic.Bool
+}
+
+func withDebugMark(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugMarkKey{}, &debugMark{})
+}
+
+// markPlayerDebug switches ctx to debug logging if playerID has a debug window, and marks the request for
+// logging when it has been handled
+func markPlayerDebug(ctx context.Context, playerID string) context.Context {
+	if ctx == nil || playerID == "" || !debugWindows.active(DebugKindPlayer, playerID) {
+		return ctx
+	}
+	if mark, ok := ctx.Value(debugMarkKey{}).(*debugMark); ok {
+		mark.player.Store(true)
+	}
+	l := debugLogger(*zerolog.Ctx(ctx)).With().Str("debugPlayer", playerID).Logger()
+	return l.WithContext(ctx)
+}
+
+func isPlayerDebugMarked(ctx context.Context) bool {
+	mark, ok := ctx.Value(debugMarkKey{}).(*debugMark)
+	return ok && mark.player.Load()
+}
@@ -0,0 +1,150 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultDebugDuration = 15 * time.Minute
+	maxDebugDuration     = 24 * time.Hour
+)
+
+// LogLevelRequest changes the log level
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// DebugWindowRequest enables a debug window, for 15 minutes unless Duration is given
+type DebugWindowRequest struct {
+	// Duration such as "30m", at most 24h
+	Duration string `json:"duration,omitempty"`
+}
+
+// PprofRequest turns the pprof endpoints on or off
+type PprofRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// LogLevelEndpoint returns the log level
+func LogLevelEndpoint() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(LogLevelRequest{Level: LogLevel().String()})
+	}
+}
+
+// SetLogLevelEndpoint changes the log level, such as "debug" or "info"
+func SetLogLevelEndpoint() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req LogLevelRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+		}
+		level, err := zerolog.ParseLevel(req.Level)
+		if err != nil || req.Level == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fmt.Sprintf("invalid level '%s'", req.Level))
+		}
+		previous := LogLevel()
+		SetLogLevel(level)
+		log.Info().Stringer("level", level).Stringer("previous", previous).Msg("Log level changed")
+		return c.JSON(LogLevelRequest{Level: level.String()})
+	}
+}
+
+// DebugWindowsEndpoint returns the enabled debug windows
+func DebugWindowsEndpoint() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(DebugWindows())
+	}
+}
+
+// EnableProviderDebugEndpoint enables a debug window for the ":provider", whose routes are given by providerRoutes
+func EnableProviderDebugEndpoint(providerRoutes map[string][]string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		provider := strings.ToLower(c.Params("provider"))
+		routes, found := providerRoutes[provider]
+		if !found {
+			return c.Status(fiber.StatusNotFound).JSON(fmt.Sprintf("provider '%s' not configured", provider))
+		}
+		duration, err := debugDuration(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+		}
+		window := EnableProviderDebug(provider, routes, duration)
+		log.Info().Str("provider", provider).Time("until", window.Until).Msg("Debug logging of provider enabled")
+		return c.JSON(window)
+	}
+}
+
+// EnablePlayerDebugEndpoint enables a debug window for the ":playerId"
+func EnablePlayerDebugEndpoint() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		duration, err := debugDuration(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+		}
+		window := EnablePlayerDebug(c.Params("playerId"), duration)
+		log.Info().Str("playerId", window.ID).Time("until", window.Until).Msg("Debug logging of player enabled")
+		return c.JSON(window)
+	}
+}
+
+// DisableDebugEndpoint ends the debug window of kind for the id in the path parameter param
+func DisableDebugEndpoint(kind, param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params(param)
+		if kind == DebugKindProvider {
+			id = strings.ToLower(id)
+		}
+		if !DisableDebug(kind, id) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		log.Info().Str("kind", kind).Str("id", id).Msg("Debug logging disabled")
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// PprofEndpoint returns if the pprof endpoints are on
+func PprofEndpoint() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(PprofRequest{Enabled: PprofEnabled()})
+	}
+}
+
+// SetPprofEndpoint turns the pprof endpoints on or off
+func SetPprofEndpoint() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req PprofRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+		}
+		SetPprofEnabled(req.Enabled)
+		log.Info().Bool("enabled", req.Enabled).Msg("Pprof endpoints toggled")
+		return c.JSON(req)
+	}
+}
+
+func debugDuration(c *fiber.Ctx) (time.Duration, error) {
+	var req DebugWindowRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return 0, err
+		}
+	}
+	if req.Duration == "" {
+		return defaultDebugDuration, nil
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %w", err)
+	}
+	if duration <= 0 || duration > maxDebugDuration {
+		return 0, fmt.Errorf("duration must be positive and at most %s", maxDebugDuration)
+	}
+	return duration, nil
+}
@@ -0,0 +1,66 @@
+package ops
+
+import (
+	"context"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// playerDebugPamClient switches PAM calls of players with a debug window to debug logging
+type playerDebugPamClient struct {
+	pam.PamClient
+}
+
+// DebugPAMPlayers wraps client, logging PAM calls of players with a debug window, and the provider requests
+// causing them, at debug level
+func DebugPAMPlayers(client pam.PamClient) pam.PamClient {
+	return &playerDebugPamClient{PamClient: client}
+}
+
+func (c *playerDebugPamClient) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, error) {
+	var ctx context.Context
+	res, err := c.PamClient.GetSession(func() (context.Context, pam.GetSessionRequest, error) {
+		var (
+			req  pam.GetSessionRequest
+			mErr error
+		)
+		ctx, req, mErr = rm()
+		return ctx, req, mErr
+	})
+	// the player of a session is only known from the response
+	if res != nil {
+		markPlayerDebug(ctx, res.PlayerId)
+	}
+	return res, err
+}
+
+func (c *playerDebugPamClient) GetBalance(rm pam.GetBalanceRequestMapper) (*pam.Balance, error) {
+	return c.PamClient.GetBalance(func() (context.Context, pam.GetBalanceRequest, error) {
+		ctx, req, err := rm()
+		return markPlayerDebug(ctx, req.PlayerID), req, err
+	})
+}
+
+func (c *playerDebugPamClient) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam.Transaction, error) {
+	return c.PamClient.GetTransactions(func() (context.Context, pam.GetTransactionsRequest, error) {
+		ctx, req, err := rm()
+		return markPlayerDebug(ctx, req.PlayerID), req, err
+	})
+}
+
+func (c *playerDebugPamClient) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	return c.PamClient.AddTransaction(func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		ctx, req, err := rm(r)
+		if req != nil {
+			ctx = markPlayerDebug(ctx, req.PlayerID)
+		}
+		return ctx, req, err
+	})
+}
+
+func (c *playerDebugPamClient) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRound, error) {
+	return c.PamClient.GetGameRound(func() (context.Context, pam.GetGameRoundRequest, error) {
+		ctx, req, err := rm()
+		return markPlayerDebug(ctx, req.PlayerID), req, err
+	})
+}
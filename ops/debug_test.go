@@ -0,0 +1,152 @@
+package ops
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// setupDebugLogging logs at info level to captor, restoring logging when the test ends
+func setupDebugLogging(t *testing.T) *strings.Builder {
+	captor := &strings.Builder{}
+	previousLogger, previousLevel, previousOutput := log.Logger, LogLevel(), debugOutput
+	previousContextLogger := zerolog.DefaultContextLogger
+	t.Cleanup(func() {
+		log.Logger, debugOutput = previousLogger, previousOutput
+		zerolog.DefaultContextLogger = previousContextLogger
+		debugWindows.mu.Lock()
+		debugWindows.entries = map[windowKey]time.Time{}
+		debugWindows.mu.Unlock()
+		SetLogLevel(previousLevel)
+	})
+	debugOutput = captor
+	log.Logger = zerolog.New(levelFilterWriter{Writer: captor})
+	zerolog.DefaultContextLogger = &log.Logger
+	SetLogLevel(zerolog.InfoLevel)
+	return captor
+}
+
+func debugApp(client pam.PamClient) *fiber.App {
+	app := fiber.New()
+	LoggingMiddleware(app)
+	app.Post("/providers/:provider/debit", func(c *fiber.Ctx) error {
+		_, err := client.AddTransaction(func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+			return c.UserContext(), &pam.AddTransactionRequest{PlayerID: c.Query("player")}, nil
+		})
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"status": "OK"})
+	})
+	return app
+}
+
+func TestDebugWindows(t *testing.T) {
+	tests := []struct {
+		name   string
+		enable func()
+		logged []string
+	}{
+		{
+			name:   "no debug windows",
+			enable: func() {},
+		},
+		{
+			name: "provider debug window",
+			enable: func() {
+				EnableProviderDebug("evolution", []string{"/providers/evolution"}, time.Minute)
+			},
+			logged: []string{"/providers/evolution/debit?player=p1", "/providers/evolution/debit?player=p2"},
+		},
+		{
+			name: "player debug window",
+			enable: func() {
+				EnablePlayerDebug("p2", time.Minute)
+			},
+			logged: []string{"/providers/evolution/debit?player=p2", "/providers/redtiger/debit?player=p2"},
+		},
+		{
+			name: "ended debug window",
+			enable: func() {
+				EnablePlayerDebug("p2", time.Nanosecond)
+				time.Sleep(time.Millisecond)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			captor := setupDebugLogging(t)
+			app := debugApp(DebugPAMPlayers(transactionPamStub{}))
+			test.enable()
+
+			for _, path := range []string{
+				"/providers/evolution/debit?player=p1",
+				"/providers/evolution/debit?player=p2",
+				"/providers/redtiger/debit?player=p1",
+				"/providers/redtiger/debit?player=p2",
+			} {
+				_, err := app.Test(httptest.NewRequest(fiber.MethodPost, path, nil))
+				require.NoError(t, err)
+			}
+
+			output := captor.String()
+			assert.Equal(t, len(test.logged)*2, strings.Count(output, "http server re"), output)
+			for _, path := range test.logged {
+				assert.Contains(t, output, path)
+			}
+		})
+	}
+}
+
+func TestDebugEndpoints(t *testing.T) {
+	setupDebugLogging(t)
+	app := fiber.New()
+	app.Get("/log-level", LogLevelEndpoint())
+	app.Put("/log-level", SetLogLevelEndpoint())
+	app.Get("/windows", DebugWindowsEndpoint())
+	app.Post("/providers/:provider", EnableProviderDebugEndpoint(map[string][]string{"evolution": {"/providers/evolution"}}))
+	app.Delete("/providers/:provider", DisableDebugEndpoint(DebugKindProvider, "provider"))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		result string
+	}{
+		{"get log level", fiber.MethodGet, "/log-level", "", fiber.StatusOK, `{"level":"info"}`},
+		{"set log level", fiber.MethodPut, "/log-level", `{"level":"warn"}`, fiber.StatusOK, `{"level":"warn"}`},
+		{"set invalid log level", fiber.MethodPut, "/log-level", `{"level":"loud"}`, fiber.StatusBadRequest, `"invalid level 'loud'"`},
+		{"enable provider", fiber.MethodPost, "/providers/Evolution", `{"duration":"1m"}`, fiber.StatusOK, `"id":"evolution"`},
+		{"enable unknown provider", fiber.MethodPost, "/providers/unknown", "", fiber.StatusNotFound, `not configured`},
+		{"enable provider too long", fiber.MethodPost, "/providers/evolution", `{"duration":"48h"}`, fiber.StatusBadRequest, `at most 24h`},
+		{"list windows", fiber.MethodGet, "/windows", "", fiber.StatusOK, `"routes":["/providers/evolution"]`},
+		{"disable provider", fiber.MethodDelete, "/providers/evolution", "", fiber.StatusNoContent, ``},
+		{"disable disabled provider", fiber.MethodDelete, "/providers/evolution", "", fiber.StatusNotFound, ``},
+		{"list no windows", fiber.MethodGet, "/windows", "", fiber.StatusOK, `[]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.status, resp.StatusCode)
+			body := make([]byte, 1024)
+			n, _ := resp.Body.Read(body)
+			assert.Contains(t, string(body[:n]), test.result)
+		})
+	}
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+}
@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/semconv/v1.17.0"
 
 	"github.com/valkyrie-fnd/valkyrie/internal/pipeline"
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
 )
 
 type FastHTTPPayload interface {
@@ -23,7 +24,18 @@ type FastHTTPPayload interface {
 
 // InstrumentHTTPClient will instrument a fasthttp-based pipeline with telemetry handlers
 func InstrumentHTTPClient[T FastHTTPPayload](pipeline *pipeline.Pipeline[T]) {
-	pipeline.Register(HTTPTracingHandler[T](), HTTPLoggingHandler[T](), HTTPMetricHandler[T]())
+	pipeline.Register(HTTPTracingHandler[T](), HTTPCorrelationHandler[T](), HTTPLoggingHandler[T](), HTTPMetricHandler[T]())
+}
+
+// HTTPCorrelationHandler adds the correlation ID of the request context to outbound requests, unless already set
+func HTTPCorrelationHandler[T FastHTTPPayload]() pipeline.Handler[T] {
+	return func(pc pipeline.PipelineContext[T]) error {
+		headers := &pc.Payload().Request().Header
+		if id := correlation.ID(pc.Context()); id != "" && len(headers.Peek(correlation.Header)) == 0 {
+			headers.Set(correlation.Header, id)
+		}
+		return pc.Next()
+	}
 }
 
 func HTTPTracingHandler[T FastHTTPPayload]() pipeline.Handler[T] {
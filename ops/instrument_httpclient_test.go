@@ -11,10 +11,12 @@ import (
 type mockPipelineContext[T any] struct {
 	ctx     context.Context
 	payload T
+	result  any
+	err     error
 }
 
 func (m *mockPipelineContext[T]) Next() error {
-	return nil
+	return m.err
 }
 
 func (m *mockPipelineContext[T]) Context() context.Context {
@@ -29,6 +31,14 @@ func (m *mockPipelineContext[T]) Payload() T {
 	return m.payload
 }
 
+func (m *mockPipelineContext[T]) Result() any {
+	return m.result
+}
+
+func (m *mockPipelineContext[T]) SetResult(result any) {
+	m.result = result
+}
+
 type mockPayload struct {
 	request  *fasthttp.Request
 	response *fasthttp.Response
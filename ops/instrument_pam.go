@@ -9,6 +9,7 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
@@ -33,9 +34,11 @@ func InstrumentVPluginPAMClient(pipeline *pipeline.Pipeline[any]) {
 		PAMMetricHandler(VPluginName, rpcAttributes...))
 }
 
-// InstrumentGenericPAMClient will instrument a genericpam-based pipeline with telemetry handlers
+// InstrumentGenericPAMClient will instrument a genericpam-based pipeline with telemetry handlers. Tracing
+// headers are propagated by the http client, within the PAM operation span.
 func InstrumentGenericPAMClient(pipeline *pipeline.Pipeline[any]) {
-	pipeline.Register(PAMMetricHandler(GenericPAMName))
+	pipeline.Register(PAMTracingHandler(GenericPAMName),
+		PAMMetricHandler(GenericPAMName))
 }
 
 func PAMMetricHandler(name string, attributes ...attribute.KeyValue) pipeline.Handler[any] {
@@ -85,15 +88,74 @@ func PAMMetricHandler(name string, attributes ...attribute.KeyValue) pipeline.Ha
 	}
 }
 
+// PAMTracingHandler starts a span for PAM operations, with the operation, provider and transaction type of the
+// request, and the error codes of failed operations
 func PAMTracingHandler(tracerName string, attributes ...attribute.KeyValue) pipeline.Handler[any] {
 	return func(pc pipeline.PipelineContext[any]) error {
-		ctx, span := otel.Tracer(tracerName).Start(pc.Context(), getRequestName(pc.Payload()), trace.WithAttributes(attributes...))
+		operation := getRequestName(pc.Payload())
+		attrs := append([]attribute.KeyValue{pamOperationKey.String(operation)}, attributes...)
+		attrs = append(attrs, pamRequestAttributes(pc.Payload())...)
+		ctx, span := otel.Tracer(tracerName).Start(pc.Context(), operation,
+			trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
 		defer span.End()
 
 		pc.SetContext(ctx)
 
-		return pc.Next()
+		err := pc.Next()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(pamErrorAttributes(err)...)
+		}
+		return err
+	}
+}
+
+const (
+	pamOperationKey       = attribute.Key("pam.operation")
+	pamProviderKey        = attribute.Key("pam.provider")
+	pamTransactionTypeKey = attribute.Key("pam.transaction.type")
+	pamErrorCodeKey       = attribute.Key("pam.error.code")
+	valkErrorCodeKey      = attribute.Key("valkyrie.error.code")
+)
+
+// pamRequestAttributes returns the provider, and for transactions the type, of a PAM request
+func pamRequestAttributes(req any) []attribute.KeyValue {
+	var provider pam.Provider
+	switch r := req.(type) {
+	case *pam.GetSessionRequest:
+		provider = r.Params.Provider
+	case *pam.RefreshSessionRequest:
+		provider = r.Params.Provider
+	case *pam.GetBalanceRequest:
+		provider = r.Params.Provider
+	case *pam.GetTransactionsRequest:
+		provider = r.Params.Provider
+	case *pam.AddTransactionRequest:
+		return []attribute.KeyValue{
+			pamProviderKey.String(r.Params.Provider),
+			pamTransactionTypeKey.String(string(r.Body.TransactionType)),
+		}
+	case *pam.GetGameRoundRequest:
+		provider = r.Params.Provider
+	default:
+		return nil
+	}
+	return []attribute.KeyValue{pamProviderKey.String(provider)}
+}
+
+// pamErrorAttributes returns the ValkErrorCode of err, and the error code of the PAM if it returned one
+func pamErrorAttributes(err error) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	var valkErr pam.ValkyrieError
+	if errors.As(err, &valkErr) {
+		attrs = append(attrs, valkErrorCodeKey.Int(int(valkErr.ValkErrorCode)))
+	}
+	var pamErr *pam.PamError
+	if errors.As(err, &pamErr) {
+		attrs = append(attrs, pamErrorCodeKey.String(string(pamErr.Code)))
 	}
+	return attrs
 }
 
 // getRequestName, return "GetBalance" from "GetBalanceRequest"
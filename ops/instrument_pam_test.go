@@ -5,6 +5,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
@@ -22,6 +28,38 @@ func Test_pamTracingHandler(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func Test_pamTracingHandler_Attributes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	handler := PAMTracingHandler(GenericPAMName)
+	pc := &mockPipelineContext[any]{
+		ctx: context.TODO(),
+		payload: &pam.AddTransactionRequest{
+			Params: pam.AddTransactionParams{Provider: "evolution"},
+			Body:   pam.Transaction{TransactionType: pam.WITHDRAW},
+		},
+		err: pam.ToValkyrieError(&pam.PamError{Code: pam.PAMERRCASHOVERDRAFT, Message: "overdraft"}),
+	}
+
+	err := handler(pc)
+
+	assert.Error(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "AddTransaction", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		pamOperationKey.String("AddTransaction"),
+		pamProviderKey.String("evolution"),
+		pamTransactionTypeKey.String("WITHDRAW"),
+		valkErrorCodeKey.Int(int(pam.ValkErrOpCashOverdraft)),
+		pamErrorCodeKey.String(string(pam.PAMERRCASHOVERDRAFT)),
+	}, spans[0].Attributes())
+}
+
 func Test_applyTracingFromContextHandler(t *testing.T) {
 	handler := ApplyTracingFromContextHandler()
 	tests := []struct {
@@ -0,0 +1,82 @@
+package ops
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	TransactionsMeterName = "pam-transactions"
+
+	metricNamePAMTransactionsAmount = "pam.transactions.amount"
+	metricNamePAMTransactionsTips   = "pam.transactions.tips"
+)
+
+// transactionMetricsClient records business metrics of successful transactions
+type transactionMetricsClient struct {
+	pam.PamClient
+	amount metric.Float64Counter
+	tips   metric.Float64Counter
+}
+
+// InstrumentPAMTransactions wraps client, recording the amounts of all successful transactions.
+// Tips are not part of the wagering (GGR) figures, so they are recorded separately in
+// "pam.transactions.tips" instead of "pam.transactions.amount".
+func InstrumentPAMTransactions(client pam.PamClient) pam.PamClient {
+	amount, err := otel.Meter(TransactionsMeterName).Float64Counter(metricNamePAMTransactionsAmount,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription("measures the cash, bonus and promo amounts of transactions, excluding tips"))
+	if err != nil {
+		return client
+	}
+
+	tips, err := otel.Meter(TransactionsMeterName).Float64Counter(metricNamePAMTransactionsTips,
+		metric.WithUnit(unitDimensionless),
+		metric.WithDescription("measures the amounts of tips given"))
+	if err != nil {
+		return client
+	}
+
+	return &transactionMetricsClient{PamClient: client, amount: amount, tips: tips}
+}
+
+func (c *transactionMetricsClient) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	var (
+		ctx context.Context
+		req *pam.AddTransactionRequest
+	)
+	res, err := c.PamClient.AddTransaction(func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		mCtx, mapped, mErr := rm(r)
+		ctx, req = mCtx, mapped
+		return mCtx, mapped, mErr
+	})
+	if err == nil && req != nil {
+		c.record(ctx, req.Body)
+	}
+	return res, err
+}
+
+func (c *transactionMetricsClient) record(ctx context.Context, t pam.AddTransactionJSONRequestBody) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attributes := []attribute.KeyValue{
+		attribute.String("provider", t.Provider),
+		attribute.String("currency", t.Currency),
+	}
+
+	if t.Tip != nil && t.Tip.TipAmount != nil {
+		c.tips.Add(ctx, decimal.Decimal(*t.Tip.TipAmount).InexactFloat64(), metric.WithAttributes(attributes...))
+		return
+	}
+
+	attributes = append(attributes, attribute.String("transaction_type", string(t.TransactionType)))
+	total := decimal.Sum(decimal.Decimal(t.CashAmount), decimal.Decimal(t.BonusAmount), decimal.Decimal(t.PromoAmount))
+	c.amount.Add(ctx, total.InexactFloat64(), metric.WithAttributes(attributes...))
+}
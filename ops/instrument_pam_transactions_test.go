@@ -0,0 +1,75 @@
+package ops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+type transactionPamStub struct {
+	pam.PamClient
+	err error
+}
+
+func (p transactionPamStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	if _, _, err := rm(pam.SixDecimalRounder); err != nil {
+		return nil, err
+	}
+	return &pam.TransactionResult{}, p.err
+}
+
+func TestInstrumentPAMTransactions(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	mapper := func(body pam.AddTransactionJSONRequestBody) pam.AddTransactionRequestMapper {
+		return func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+			return context.Background(), &pam.AddTransactionRequest{Body: body}, nil
+		}
+	}
+	bet := pam.AddTransactionJSONRequestBody{
+		Provider:        "evolution",
+		Currency:        "EUR",
+		TransactionType: pam.WITHDRAW,
+		CashAmount:      testutils.NewFloatAmount(10),
+		BonusAmount:     testutils.NewFloatAmount(2),
+		PromoAmount:     pam.ZeroAmount,
+	}
+	tip := bet
+	tip.CashAmount = testutils.NewFloatAmount(5)
+	tip.BonusAmount = pam.ZeroAmount
+	tip.Tip = &pam.Tip{TipAmount: &tip.CashAmount}
+
+	client := InstrumentPAMTransactions(transactionPamStub{})
+	_, err := client.AddTransaction(mapper(bet))
+	require.NoError(t, err)
+	_, err = client.AddTransaction(mapper(tip))
+	require.NoError(t, err)
+	_, err = InstrumentPAMTransactions(transactionPamStub{err: assert.AnError}).AddTransaction(mapper(bet))
+	require.Error(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	sums := map[string]float64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			for _, dp := range m.Data.(metricdata.Sum[float64]).DataPoints {
+				sums[m.Name] += dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		metricNamePAMTransactionsAmount: 12,
+		metricNamePAMTransactionsTips:   5,
+	}, sums, "tips and failed transactions are not part of the transaction amounts")
+}
@@ -0,0 +1,168 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+const (
+	// traceIDFieldName and spanIDFieldName are the log fields of the current span, added by TracingMiddleware
+	traceIDFieldName = "traceId"
+	spanIDFieldName  = "spanId"
+)
+
+// otlpLogWriter exports events as OTLP log records, correlated with the traces of their traceId and spanId fields
+type otlpLogWriter struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+func newOTLPLogWriter(output configs.OutputLogConfig, telemetry configs.TelemetryConfig) (*otlpLogWriter, error) {
+	exporter, err := otlploghttp.New(context.Background(), getOTLPLogOptions(output, telemetry)...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(telemetry.ServiceName),
+			semconv.ServiceNamespace(telemetry.Namespace),
+		)),
+	)
+	return &otlpLogWriter{provider: provider, logger: provider.Logger("valkyrie")}, nil
+}
+
+// getOTLPLogOptions returns options given the url of the output, or else the url of the tracing collector
+func getOTLPLogOptions(output configs.OutputLogConfig, telemetry configs.TelemetryConfig) []otlploghttp.Option {
+	options := []otlploghttp.Option{
+		otlploghttp.WithCompression(otlploghttp.GzipCompression), // enable compression by default
+	}
+	url, path := output.URL, ""
+	if url == "" {
+		url, path = telemetry.Tracing.URL, "/v1/logs"
+	}
+
+	if url == "" {
+		options = append(options, otlploghttp.WithInsecure()) // use HTTP by default
+		return options
+	}
+
+	if scheme, remainder, found := strings.Cut(url, "://"); found {
+		if scheme == "http" {
+			options = append(options, otlploghttp.WithInsecure())
+		}
+		url = remainder
+	}
+
+	endpoint, urlPath, found := strings.Cut(url, "/")
+	options = append(options, otlploghttp.WithEndpoint(endpoint))
+	if path != "" {
+		options = append(options, otlploghttp.WithURLPath(path))
+	} else if found {
+		options = append(options, otlploghttp.WithURLPath("/"+urlPath))
+	}
+
+	return options
+}
+
+func (w *otlpLogWriter) Write(p []byte) (int, error) {
+	var event map[string]any
+	if err := json.Unmarshal(p, &event); err != nil {
+		return 0, fmt.Errorf("otlp log output: %w", err)
+	}
+	ctx, record := otlpLogRecord(event)
+	w.logger.Emit(ctx, record)
+	return len(p), nil
+}
+
+// Close exports the pending records
+func (w *otlpLogWriter) Close() error {
+	return w.provider.Shutdown(context.Background())
+}
+
+// otlpLogRecord returns the record of an event, and the context of its span
+func otlpLogRecord(event map[string]any) (context.Context, otellog.Record) {
+	var record otellog.Record
+	record.SetObservedTimestamp(time.Now())
+	ctx := context.Background()
+	var spanContext trace.SpanContextConfig
+
+	for key, value := range event {
+		str, isString := value.(string)
+		switch {
+		case key == zerolog.LevelFieldName && isString:
+			level, _ := zerolog.ParseLevel(str)
+			record.SetSeverity(otlpSeverity(level))
+			record.SetSeverityText(str)
+		case key == zerolog.MessageFieldName && isString:
+			record.SetBody(otellog.StringValue(str))
+		case key == zerolog.TimestampFieldName && isString:
+			if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+				record.SetTimestamp(t)
+			}
+		case key == traceIDFieldName && isString:
+			spanContext.TraceID, _ = trace.TraceIDFromHex(str)
+		case key == spanIDFieldName && isString:
+			spanContext.SpanID, _ = trace.SpanIDFromHex(str)
+		default:
+			record.AddAttributes(otellog.KeyValue{Key: key, Value: otlpLogValue(value)})
+		}
+	}
+
+	if sc := trace.NewSpanContext(spanContext); sc.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, sc)
+	}
+	return ctx, record
+}
+
+func otlpLogValue(value any) otellog.Value {
+	switch v := value.(type) {
+	case nil:
+		return otellog.Value{}
+	case string:
+		return otellog.StringValue(v)
+	case bool:
+		return otellog.BoolValue(v)
+	case float64:
+		return otellog.Float64Value(v)
+	default:
+		// nested objects and arrays are kept as JSON
+		b, _ := json.Marshal(v)
+		return otellog.StringValue(string(b))
+	}
+}
+
+func otlpSeverity(level zerolog.Level) otellog.Severity {
+	switch level {
+	case zerolog.TraceLevel:
+		return otellog.SeverityTrace
+	case zerolog.DebugLevel:
+		return otellog.SeverityDebug
+	case zerolog.InfoLevel:
+		return otellog.SeverityInfo
+	case zerolog.WarnLevel:
+		return otellog.SeverityWarn
+	case zerolog.ErrorLevel:
+		return otellog.SeverityError
+	case zerolog.FatalLevel:
+		return otellog.SeverityFatal
+	case zerolog.PanicLevel:
+		return otellog.SeverityFatal4
+	default:
+		return otellog.SeverityUndefined
+	}
+}
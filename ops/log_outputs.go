@@ -0,0 +1,252 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/diode"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+const (
+	outputDialTimeout       = 5 * time.Second
+	outputWriteTimeout      = 5 * time.Second
+	outputReconnectInterval = 5 * time.Second
+	// outputBufferSize and outputPollInterval buffer network outputs when async logging is not configured
+	outputBufferSize   = 100000
+	outputPollInterval = 10 * time.Millisecond
+)
+
+var (
+	droppedLogsOnce    sync.Once
+	droppedLogsCounter metric.Int64Counter
+)
+
+// countDroppedLogs reports logs dropped by an output, because its buffer was full or it was unavailable
+func countDroppedLogs(output string, count int) {
+	droppedLogsOnce.Do(func() {
+		counter, err := otel.Meter("valkyrie-logging").Int64Counter("log.dropped",
+			metric.WithUnit(unitDimensionless),
+			metric.WithDescription("measures the number of log events dropped by log outputs"))
+		if err == nil {
+			droppedLogsCounter = counter
+		}
+	})
+	if droppedLogsCounter != nil {
+		droppedLogsCounter.Add(context.Background(), int64(count),
+			metric.WithAttributes(attribute.String("output", output)))
+	}
+}
+
+// newLogOutputs builds the writers of all outputs, each filtered by its minimum level and buffered when async, and
+// returns the closers of its network outputs. Outputs that cannot be configured are reported to stderr and left out.
+func newLogOutputs(logConfig configs.LogConfig, telemetry configs.TelemetryConfig, profiles *Profiles) (io.Writer, []io.Closer) {
+	outputs := append([]configs.OutputLogConfig{logConfig.Output}, logConfig.Outputs...)
+	writers := make([]io.Writer, 0, len(outputs))
+	var closers []io.Closer
+	for _, output := range outputs {
+		writer, closer, err := newLogOutput(output, logConfig.Async, telemetry, profiles)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to configure log output '%s': %v\n", output.Type, err)
+			continue
+		}
+		writers = append(writers, writer)
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	switch len(writers) {
+	case 0:
+		return os.Stderr, closers // default stderr to make sure it's not missed
+	case 1:
+		return writers[0], closers
+	default:
+		return zerolog.MultiLevelWriter(writers...), closers
+	}
+}
+
+// newLogOutput builds the writer of an output, and its closer if it is a network output. Network outputs are always
+// buffered, to never block logging on the network.
+func newLogOutput(output configs.OutputLogConfig, async configs.AsyncLogConfig, telemetry configs.TelemetryConfig,
+	profiles *Profiles) (io.Writer, io.Closer, error) {
+	var (
+		writer  io.Writer
+		console bool
+		network bool
+		err     error
+	)
+	switch output.Type {
+	case "stdout":
+		writer, console = os.Stdout, true
+	case "stderr":
+		writer, console = os.Stderr, true
+	case "file":
+		writer = getFileWriter(output)
+	case "syslog":
+		writer, err = newSyslogWriter(output)
+		network = true
+	case "otlp":
+		writer, err = newOTLPLogWriter(output, telemetry)
+		network = true
+	case "tcp":
+		if output.Address == "" {
+			return nil, nil, errors.New("address is required")
+		}
+		// newline-delimited JSON, as the events are already terminated by newlines
+		writer = newNetWriter(output.Type, "tcp", strings.TrimPrefix(output.Address, "tcp://"), nil)
+		network = true
+	default:
+		writer, console = os.Stderr, true // default stderr to make sure it's not missed
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var closer io.Closer
+	if profiles.Has("local") && console {
+		// pretty logs for local use
+		writer = zerolog.ConsoleWriter{Out: writer, TimeFormat: time.RFC3339Nano}
+	} else if network || async.Enabled != nil && *async.Enabled {
+		// If async, wrap output in a diode
+		bufferSize, pollInterval := async.BufferSize, async.PollInterval
+		if async.Enabled == nil || !*async.Enabled || bufferSize <= 0 {
+			bufferSize, pollInterval = outputBufferSize, outputPollInterval
+		}
+		name := output.Type
+		buffered := diode.NewWriter(writer, bufferSize, pollInterval, func(count int) {
+			_, _ = fmt.Fprintf(os.Stderr, "Async logger buffer full, dropped %d messages\n", count)
+			countDroppedLogs(name, count)
+		})
+		writer = buffered
+		if network {
+			// closing the diode flushes it and closes the network output
+			closer = buffered
+		}
+	}
+
+	if output.Level == "" {
+		return writer, closer, nil
+	}
+	level, err := zerolog.ParseLevel(output.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	return minLevelWriter{writer: writer, level: level}, closer, nil
+}
+
+// minLevelWriter discards events below the minimum level of an output
+type minLevelWriter struct {
+	writer io.Writer
+	level  zerolog.Level
+}
+
+func (w minLevelWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+func (w minLevelWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < w.level {
+		return len(p), nil
+	}
+	return w.writer.Write(p)
+}
+
+// eventLevel returns the level of a JSON log event, which is lost when events are buffered
+func eventLevel(p []byte) zerolog.Level {
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(p, &event); err != nil {
+		return zerolog.NoLevel
+	}
+	var value string
+	if err := json.Unmarshal(event[zerolog.LevelFieldName], &value); err != nil {
+		return zerolog.NoLevel
+	}
+	level, err := zerolog.ParseLevel(value)
+	if err != nil {
+		return zerolog.NoLevel
+	}
+	return level
+}
+
+// netWriter writes events to a network connection, which is reconnected when lost. Events are dropped while
+// the connection is unavailable, instead of blocking logging.
+type netWriter struct {
+	name    string
+	network string
+	address string
+	// frame returns the bytes sent for an event
+	frame func(p []byte) []byte
+
+	mu          sync.Mutex
+	conn        net.Conn
+	unavailable bool
+	retryAt     time.Time
+}
+
+func newNetWriter(name, network, address string, frame func(p []byte) []byte) *netWriter {
+	if frame == nil {
+		frame = func(p []byte) []byte { return p }
+	}
+	return &netWriter{name: name, network: network, address: address, frame: frame}
+}
+
+func (w *netWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		if time.Now().Before(w.retryAt) {
+			countDroppedLogs(w.name, 1)
+			return len(p), nil
+		}
+		conn, err := net.DialTimeout(w.network, w.address, outputDialTimeout)
+		if err != nil {
+			w.failed(err)
+			return len(p), nil
+		}
+		w.conn = conn
+	}
+	_ = w.conn.SetWriteDeadline(time.Now().Add(outputWriteTimeout))
+	if _, err := w.conn.Write(w.frame(p)); err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		w.failed(err)
+		return len(p), nil
+	}
+	w.unavailable = false
+	return len(p), nil
+}
+
+// failed drops the event and waits before reconnecting, reporting the first failure to stderr
+func (w *netWriter) failed(err error) {
+	countDroppedLogs(w.name, 1)
+	w.retryAt = time.Now().Add(outputReconnectInterval)
+	if !w.unavailable {
+		w.unavailable = true
+		_, _ = fmt.Fprintf(os.Stderr, "Log output %s to %s unavailable, dropping logs: %v\n", w.name, w.address, err)
+	}
+}
+
+// Close closes the connection
+func (w *netWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,157 @@
+package ops
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+func TestSyslogOutput(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	writer, err := newSyslogWriter(configs.OutputLogConfig{Type: "syslog", Address: conn.LocalAddr().String(), Facility: "daemon"})
+	require.NoError(t, err)
+	defer func() { _ = writer.Close() }()
+
+	logger := zerolog.New(writer)
+	logger.Warn().Str("playerId", "p1").Msg("test")
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	message := string(buf[:n])
+	// daemon (3) * 8 + warning (4)
+	assert.True(t, strings.HasPrefix(message, "<28>1 "), message)
+	assert.Contains(t, message, " valkyrie ")
+	assert.True(t, strings.HasSuffix(message, ` - - {"level":"warn","playerId":"p1","message":"test"}`), message)
+}
+
+func TestSyslogOutput_Config(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  configs.OutputLogConfig
+		network string
+		wantErr string
+	}{
+		{"udp by default", configs.OutputLogConfig{Address: "syslog:514"}, "udp", ""},
+		{"tcp", configs.OutputLogConfig{Address: "tcp://syslog:601"}, "tcp", ""},
+		{"missing address", configs.OutputLogConfig{}, "", "address is required"},
+		{"unsupported network", configs.OutputLogConfig{Address: "unix:///dev/log"}, "", "unsupported network"},
+		{"unknown facility", configs.OutputLogConfig{Address: "syslog:514", Facility: "games"}, "", "unknown facility"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writer, err := newSyslogWriter(test.config)
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.network, writer.network)
+		})
+	}
+}
+
+func TestTCPOutput(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	writer, closer, err := newLogOutput(configs.OutputLogConfig{Type: "tcp", Address: listener.Addr().String(), Level: "info"},
+		configs.AsyncLogConfig{}, configs.TelemetryConfig{}, NewProfiles())
+	require.NoError(t, err)
+	require.NotNil(t, closer, "network outputs are buffered and closed on shutdown")
+	defer func() { _ = closer.Close() }()
+
+	logger := zerolog.New(writer)
+	logger.Debug().Msg("filtered")
+	logger.Info().Msg("first")
+	logger.Error().Msg("second")
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	for _, want := range []string{`{"level":"info","message":"first"}`, `{"level":"error","message":"second"}`} {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, want+"\n", line)
+	}
+}
+
+func TestNetWriter_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	writer := newNetWriter("tcp", "tcp", address, nil)
+	n, err := writer.Write([]byte("dropped\n"))
+	assert.NoError(t, err, "logging is not blocked by unavailable outputs")
+	assert.Equal(t, 8, n)
+	assert.True(t, writer.unavailable)
+}
+
+func TestNewLogOutputs(t *testing.T) {
+	writer, closers := newLogOutputs(configs.LogConfig{
+		Output:  configs.OutputLogConfig{Type: "stdout"},
+		Outputs: []configs.OutputLogConfig{{Type: "tcp", Address: "logstash:5000"}, {Type: "syslog"}},
+	}, configs.TelemetryConfig{}, NewProfiles())
+
+	_, multi := writer.(zerolog.LevelWriter)
+	assert.True(t, multi, "the misconfigured syslog output is left out")
+	assert.Len(t, closers, 1, "only network outputs are closed")
+	assert.NoError(t, closeAll(closers))
+}
+
+func Test_getOTLPLogOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    configs.OutputLogConfig
+		telemetry configs.TelemetryConfig
+		want      int
+	}{
+		{"default", configs.OutputLogConfig{}, configs.TelemetryConfig{}, 2},
+		{"tracing url", configs.OutputLogConfig{}, configs.TelemetryConfig{Tracing: configs.TraceConfig{URL: "http://collector:4318"}}, 4},
+		{"output url", configs.OutputLogConfig{URL: "https://collector:4318/logs"}, configs.TelemetryConfig{}, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Len(t, getOTLPLogOptions(test.output, test.telemetry), test.want)
+		})
+	}
+}
+
+func Test_otlpLogRecord(t *testing.T) {
+	ctx, record := otlpLogRecord(map[string]any{
+		"level":   "error",
+		"message": "failed",
+		"time":    "2017-11-15T13:16:30.646Z",
+		"traceId": "0102030405060708090a0b0c0d0e0f10",
+		"spanId":  "0102030405060708",
+		"status":  float64(500),
+	})
+
+	assert.Equal(t, "failed", record.Body().AsString())
+	assert.Equal(t, "error", record.SeverityText())
+	assert.Equal(t, time.Date(2017, 11, 15, 13, 16, 30, 646000000, time.UTC), record.Timestamp().UTC())
+	assert.Equal(t, 1, record.AttributesLen())
+	spanContext := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", spanContext.TraceID().String())
+	assert.Equal(t, "0102030405060708", spanContext.SpanID().String())
+}
@@ -0,0 +1,98 @@
+package ops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+const (
+	syslogAppName = "valkyrie"
+	// syslogTimeFormat RFC 5424 timestamp, which allows at most microseconds
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// newSyslogWriter returns a writer sending events as RFC 5424 messages over UDP, or over TCP using octet
+// counting framing (RFC 6587)
+func newSyslogWriter(output configs.OutputLogConfig) (*netWriter, error) {
+	if output.Address == "" {
+		return nil, errors.New("address is required")
+	}
+	network, address, found := strings.Cut(output.Address, "://")
+	if !found {
+		network, address = "udp", output.Address
+	}
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("unsupported network '%s'", network)
+	}
+
+	facility := syslogFacilities["local0"]
+	if output.Facility != "" {
+		f, ok := syslogFacilities[strings.ToLower(output.Facility)]
+		if !ok {
+			return nil, fmt.Errorf("unknown facility '%s'", output.Facility)
+		}
+		facility = f
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	format := syslogFormatter{facility: facility, hostname: hostname, pid: os.Getpid()}
+
+	frame := format.message
+	if network == "tcp" {
+		frame = func(p []byte) []byte {
+			msg := format.message(p)
+			return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+	}
+	return newNetWriter(output.Type, network, address, frame), nil
+}
+
+type syslogFormatter struct {
+	facility int
+	hostname string
+	pid      int
+}
+
+// message formats a JSON event as "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID - - MSG"
+func (f syslogFormatter) message(p []byte) []byte {
+	priority := f.facility*8 + syslogSeverity(eventLevel(p))
+	header := fmt.Sprintf("<%d>1 %s %s %s %d - - ", priority, time.Now().UTC().Format(syslogTimeFormat),
+		f.hostname, syslogAppName, f.pid)
+	return append([]byte(header), bytes.TrimRight(p, "\n")...)
+}
+
+// syslogSeverity returns the RFC 5424 severity of a level
+func syslogSeverity(level zerolog.Level) int {
+	switch level {
+	case zerolog.PanicLevel:
+		return 0 // emergency
+	case zerolog.FatalLevel:
+		return 2 // critical
+	case zerolog.ErrorLevel:
+		return 3 // error
+	case zerolog.WarnLevel:
+		return 4 // warning
+	case zerolog.DebugLevel, zerolog.TraceLevel:
+		return 7 // debug
+	default:
+		return 6 // informational
+	}
+}
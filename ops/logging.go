@@ -10,6 +10,8 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog"
+
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 )
 
 type userContextProvider interface {
@@ -27,7 +29,7 @@ func AddLoggingContext(c userContextProvider, key, value string) {
 // logHTTPRequest return a function that adds request data to the zerolog Event
 func logHTTPRequest(req *fasthttp.Request) func(event *zerolog.Event) {
 	return func(event *zerolog.Event) {
-		event.Bytes("requestUrl", req.URI().FullURI()).
+		event.Str("requestUrl", redact.Logging().URL(string(req.URI().FullURI()))).
 			Bytes("requestMethod", req.Header.Method()).
 			Bytes("protocol", req.Header.Protocol()).
 			Bytes("userAgent", req.Header.UserAgent())
@@ -63,7 +65,7 @@ func logRequestHeaders(req *fasthttp.Request, requestDict *zerolog.Event) {
 	requestHeaders := zerolog.Dict()
 	req.Header.VisitAll(func(key, value []byte) {
 		if isHeaderLogged(key) {
-			requestHeaders.Bytes(string(key), value)
+			requestHeaders.Bytes(string(key), redactHeader(key, value))
 		}
 	})
 	requestDict.Dict("requestHeaders", requestHeaders)
@@ -75,27 +77,24 @@ func logRequestBody(req *fasthttp.Request, requestDict *zerolog.Event) {
 		if encoding := req.Header.ContentEncoding(); len(encoding) > 0 {
 			requestDict.Bytes("request", encoding)
 		} else if contentType := req.Header.ContentType(); isContentTypeLogged(contentType) {
-			if isContentTypeJSON(contentType) {
-				requestDict.RawJSON("request", body)
-			} else {
-				requestDict.Bytes("request", body)
-			}
+			logBody(requestDict, "request", contentType, body)
 		}
 		requestDict.Int("requestSize", req.Header.ContentLength())
 	}
 }
 
 func logResponseBody(resp *fasthttp.Response, requestDict *zerolog.Event) {
+	if resp.BodyStream() != nil {
+		// streamed body is not read here, it can only be read once
+		requestDict.Int("responseSize", resp.Header.ContentLength())
+		return
+	}
 	if body := resp.Body(); body != nil {
 		// content is encoded, don't bother decompressing
 		if encoding := resp.Header.ContentEncoding(); len(encoding) > 0 {
 			requestDict.Bytes("response", encoding)
 		} else if contentType := resp.Header.ContentType(); isContentTypeLogged(contentType) {
-			if isContentTypeJSON(contentType) {
-				requestDict.RawJSON("response", body)
-			} else {
-				requestDict.Bytes("response", body)
-			}
+			logBody(requestDict, "response", contentType, body)
 		}
 		requestDict.Int("responseSize", resp.Header.ContentLength())
 	}
@@ -105,12 +104,31 @@ func logResponseHeaders(resp *fasthttp.Response, requestDict *zerolog.Event) {
 	responseHeaders := zerolog.Dict()
 	resp.Header.VisitAll(func(key, value []byte) {
 		if isHeaderLogged(key) {
-			responseHeaders.Bytes(string(key), value)
+			responseHeaders.Bytes(string(key), redactHeader(key, value))
 		}
 	})
 	requestDict.Dict("responseHeaders", responseHeaders)
 }
 
+// logBody logs body with the values of redacted fields replaced
+func logBody(requestDict *zerolog.Event, key string, contentType, body []byte) {
+	switch {
+	case isContentTypeJSON(contentType):
+		requestDict.RawJSON(key, redact.Logging().JSON(body))
+	case isContentTypeForm(contentType):
+		requestDict.Str(key, redact.Logging().Query(string(body)))
+	default:
+		requestDict.Bytes(key, body)
+	}
+}
+
+func redactHeader(key, value []byte) []byte {
+	if redact.Logging().Name(string(key)) {
+		return []byte(redact.Value)
+	}
+	return value
+}
+
 func isHeaderLogged(header []byte) bool {
 	header = bytes.ToLower(header)
 	for _, re := range loggedHeaders {
@@ -144,6 +162,11 @@ func isContentTypeJSON(contentType []byte) bool {
 	return bytes.Contains(contentType, []byte("/json")) || bytes.Contains(contentType, []byte("+json"))
 }
 
+// isContentTypeForm returns true for URL encoded form data
+func isContentTypeForm(contentType []byte) bool {
+	return bytes.HasPrefix(bytes.ToLower(contentType), []byte(fiber.MIMEApplicationForm))
+}
+
 // Set default values for Header and Content-Type logging filters.
 func init() {
 	SetHeaderWhitelist([]string{
@@ -1,10 +1,12 @@
 package ops
 
 import (
+	stderrors "errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -12,20 +14,26 @@ import (
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/diode"
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 )
 
-// ConfigureLogging configures the logging framework
-func ConfigureLogging(logConfig configs.LogConfig, profiles *Profiles) {
+var (
+	logOutputsMu sync.Mutex
+	// logOutputClosers closers of the network log outputs
+	logOutputClosers []io.Closer
+)
+
+// ConfigureLogging configures the logging framework, exporting logs to the telemetry collector when an output is "otlp"
+func ConfigureLogging(logConfig configs.LogConfig, telemetry configs.TelemetryConfig, profiles *Profiles) {
 	level, err := zerolog.ParseLevel(logConfig.Level)
 	if err != nil {
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+		SetLogLevel(zerolog.InfoLevel)
 	} else {
-		zerolog.SetGlobalLevel(level)
+		SetLogLevel(level)
 	}
 	// Profile to prevent changing of logging. Test will set up logging itself
 	if profiles.Has("testlog") {
@@ -43,32 +51,22 @@ func ConfigureLogging(logConfig configs.LogConfig, profiles *Profiles) {
 	// use RFC3339 with nano precision for timestamp field
 	zerolog.TimeFieldFormat = time.RFC3339Nano
 
-	// configure output
-	var writer io.Writer
-	switch logConfig.Output.Type {
-	case "stdout":
-		writer = os.Stdout
-	case "stderr":
-		writer = os.Stderr
-	case "file":
-		writer = getFileWriter(logConfig.Output)
-	default:
-		writer = os.Stderr // default stderr to make sure it's not missed
+	// configure outputs, closing the network outputs of any previous configuration
+	output, closers := newLogOutputs(logConfig, telemetry, profiles)
+	logOutputsMu.Lock()
+	previous := logOutputClosers
+	logOutputClosers = closers
+	logOutputsMu.Unlock()
+	defer closeAll(previous)
+
+	logger := log.Logger
+	if profiles.Has("local") {
+		logger = logger.With().Caller().Logger()
 	}
-	log.Logger = log.Logger.Output(writer)
 
-	// If async, wrap logger in a diode
-	if logConfig.Async.Enabled != nil && *logConfig.Async.Enabled {
-		log.Logger = log.Output(diode.NewWriter(writer, logConfig.Async.BufferSize, logConfig.Async.PollInterval, func(count int) {
-			_, _ = fmt.Fprintf(os.Stderr, "Async logger buffer full, dropped %d messages\n", count)
-		}))
-	}
-
-	// pretty logs for local use
-	if profiles.Has("local") && logConfig.Output.Type != "file" {
-		log.Logger = log.With().Caller().Logger().
-			Output(zerolog.ConsoleWriter{Out: writer, TimeFormat: time.RFC3339Nano})
-	}
+	// debug windows log to the output directly, everything else is filtered by the log level
+	debugOutput = output
+	log.Logger = logger.Output(levelFilterWriter{Writer: output})
 
 	// use global logger as default context logger (used when context is missing a logger: "zerolog.Ctx(ctx).Info()")
 	zerolog.DefaultContextLogger = &log.Logger
@@ -79,6 +77,24 @@ func ConfigureLogging(logConfig configs.LogConfig, profiles *Profiles) {
 	log.Info().Strs("profiles", profiles.List()).Msg("Configured logging")
 }
 
+// CloseLogOutputs flushes and closes the network log outputs, dropping anything logged to them afterwards.
+// It is registered as shutdown hook of the server.
+func CloseLogOutputs() error {
+	logOutputsMu.Lock()
+	closers := logOutputClosers
+	logOutputClosers = nil
+	logOutputsMu.Unlock()
+	return closeAll(closers)
+}
+
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, c := range closers {
+		errs = append(errs, c.Close())
+	}
+	return stderrors.Join(errs...)
+}
+
 func configureHTTPLogging(cfg configs.HTTPLogConfig) {
 	if cfg.HeaderWhitelist != nil {
 		SetHeaderWhitelist(*cfg.HeaderWhitelist)
@@ -86,6 +102,7 @@ func configureHTTPLogging(cfg configs.HTTPLogConfig) {
 	if cfg.ContentTypeWhitelist != nil {
 		SetContentTypeWhitelist(*cfg.ContentTypeWhitelist)
 	}
+	redact.Configure(cfg.Redact)
 }
 
 func getFileWriter(config configs.OutputLogConfig) io.Writer {
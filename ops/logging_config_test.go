@@ -105,7 +105,7 @@ func TestConfigureLogging(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ConfigureLogging(tt.config, NewProfiles())
+			ConfigureLogging(tt.config, configs.TelemetryConfig{}, NewProfiles())
 			_ = file.Truncate(0) // clear file
 
 			tt.eventFn(log.Log()).Send()
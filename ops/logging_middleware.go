@@ -4,7 +4,6 @@ import (
 	"bytes"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
@@ -12,27 +11,31 @@ import (
 //
 // # Adds tracing information to logging
 //
-// # Adds debug logging for request and response
+// # Adds debug logging for request and response, when the log level is debug or within debug windows
 func LoggingMiddleware(apps ...*fiber.App) {
 	for _, app := range apps {
 		app.Use(logContextInjector)
 
 		app.Use(propagateTraceLogging)
 
-		log.Debug().Func(func(e *zerolog.Event) {
-			e.Msg("Setting up request/response logging for debug")
-			app.Use(requestResponseLogging)
-		})
+		app.Use(requestResponseLogging)
 	}
 }
 
-// logContextInjector makes sure zerolog.Logger is injected into the request context
+// logContextInjector makes sure zerolog.Logger is injected into the request context, logging at debug level
+// for requests to providers with a debug window
 func logContextInjector(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	// log.Ctx(ctx) will return the logger already associated with the context (if already configured),
 	// and if not configured the `DefaultContextLogger`, which is configured as the global logger in ConfigureLogging.
 	l := log.Ctx(ctx).With().Logger()
-	c.SetUserContext(l.WithContext(ctx))
+	if debugWindows.provider(c.Path()) {
+		l = debugLogger(l)
+	} else {
+		// not building debug events discarded while debug windows of others are enabled
+		l = l.Level(LogLevel())
+	}
+	c.SetUserContext(withDebugMark(l.WithContext(ctx)))
 	return c.Next()
 }
 
@@ -41,12 +44,23 @@ var pathPing = []byte("/ping")
 // Adds request and response to log
 func requestResponseLogging(c *fiber.Ctx) error {
 	path := c.Request().URI().Path()
+	requestLogged := false
 	if !bytes.HasSuffix(path, pathPing) {
-		log.Ctx(c.UserContext()).Debug().Func(logHTTPRequest(c.Request())).Msg("http server request")
+		if e := log.Ctx(c.UserContext()).Debug(); e.Enabled() {
+			e.Func(logHTTPRequest(c.Request())).Msg("http server request")
+			requestLogged = true
+		}
 	}
 
 	err := c.Next()
 
+	// requests of players with a debug window are only known once handled
+	if !requestLogged && isPlayerDebugMarked(c.UserContext()) {
+		l := debugLogger(*log.Ctx(c.UserContext()))
+		c.SetUserContext(l.WithContext(c.UserContext()))
+		l.Debug().Func(logHTTPRequest(c.Request())).Msg("http server request")
+	}
+
 	if !bytes.HasSuffix(path, pathPing) {
 		if err != nil {
 			log.Ctx(c.UserContext()).Error().Func(logHTTPResponse(c.Request(), c.Response(), err)).Msg("http server response")
@@ -13,6 +13,8 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 )
 
 type stubUserContextProvider struct {
@@ -67,6 +69,36 @@ func TestLogHTTPRequest(t *testing.T) {
 	assert.NotContains(t, str, "\"x-forwarded-for\"", "X-Forwarded-For header is never set")
 }
 
+func TestLogHTTPResponse_Redacted(t *testing.T) {
+	redact.Configure([]string{"sid", "$.player.id"})
+	t.Cleanup(func() { redact.Configure(nil) })
+	captor := strings.Builder{}
+	logger := zerolog.New(&captor)
+
+	request := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(request)
+	request.SetRequestURI("http://localhost/balance?sid=secret-query&casino=c1")
+	request.Header.SetContentType(fiber.MIMEApplicationJSON)
+	request.Header.Set(fiber.HeaderAuthorization, "Bearer secret-header")
+	request.SetBodyString(`{"sid":"secret-body","player":{"id":"secret-player"}}`)
+
+	response := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(response)
+	response.Header.SetContentType(fiber.MIMEApplicationForm)
+	response.SetBodyString("sid=secret-form&status=OK")
+
+	previousHeaders := loggedHeaders
+	t.Cleanup(func() { loggedHeaders = previousHeaders })
+	SetHeaderWhitelist([]string{"*"})
+	logger.Debug().Func(logHTTPResponse(request, response, nil)).Send()
+
+	logged := captor.String()
+	assert.NotContains(t, logged, "secret")
+	assert.Contains(t, logged, "casino=c1")
+	assert.Contains(t, logged, "status=OK")
+	assert.Contains(t, logged, `"Authorization":"[REDACTED]"`)
+}
+
 func TestHTTPRequestContentLengthNegative(t *testing.T) {
 	captor := strings.Builder{}
 	logger := zerolog.New(&captor)
@@ -0,0 +1,366 @@
+package ops
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	// RecordKindProvider records of requests made by providers to Valkyrie
+	RecordKindProvider = "provider"
+	// RecordKindPAM records of calls made by Valkyrie to the PAM
+	RecordKindPAM = "pam"
+
+	defaultRecordingFilename = "valkyrie-traffic.jsonl"
+	redactedValue            = redact.Value
+)
+
+// values of these headers, query parameters and JSON fields are never recorded, in addition to those redacted
+// from logs
+var alwaysRedacted = []string{fiber.HeaderAuthorization, fiber.HeaderCookie, fiber.HeaderSetCookie, "authToken"}
+
+// TrafficRecord a recorded request and response. Provider requests and the PAM calls they caused share
+// the same TraceID.
+type TrafficRecord struct {
+	Time      time.Time       `json:"time"`
+	TraceID   string          `json:"traceId"`
+	Kind      string          `json:"kind"`
+	Operation string          `json:"operation,omitempty"` // PAM client operation, for PAM records
+	Duration  time.Duration   `json:"duration"`
+	Request   RecordedMessage `json:"request"`
+	Response  RecordedMessage `json:"response"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// RecordedMessage recorded request or response. JSON bodies are kept in Body, any other in Text.
+type RecordedMessage struct {
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Status  int               `json:"status,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Text    string            `json:"text,omitempty"`
+}
+
+// TrafficRecorder writes sanitised traffic records as JSON lines to a rotating file
+type TrafficRecorder struct {
+	mu       sync.Mutex
+	writer   io.WriteCloser
+	redactor *redact.Redactor
+}
+
+// NewTrafficRecorder creates a TrafficRecorder from config, or returns nil if recording is not enabled
+func NewTrafficRecorder(config configs.RecordingConfig) *TrafficRecorder {
+	if !config.Enabled {
+		return nil
+	}
+	if config.Filename == "" {
+		config.Filename = defaultRecordingFilename
+	}
+	return newTrafficRecorder(&lumberjack.Logger{
+		Filename:   config.Filename,
+		MaxSize:    config.MaxSize,
+		MaxAge:     config.MaxAge,
+		MaxBackups: config.MaxBackups,
+		Compress:   config.Compress,
+	}, config.Redact)
+}
+
+func newTrafficRecorder(writer io.WriteCloser, rules []string) *TrafficRecorder {
+	return &TrafficRecorder{
+		writer:   writer,
+		redactor: redact.New(append(append(append([]string{}, alwaysRedacted...), redact.LoggingRules()...), rules...)...),
+	}
+}
+
+// Record writes the record, failures are logged but not returned
+func (r *TrafficRecorder) Record(record TrafficRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to marshal traffic record")
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err = r.writer.Write(append(line, '\n')); err != nil {
+		log.Warn().Err(err).Msg("Failed to write traffic record")
+	}
+}
+
+// Close closes the underlying file
+func (r *TrafficRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.writer.Close()
+}
+
+func (r *TrafficRecorder) isRedacted(name string) bool {
+	return r.redactor.Name(name)
+}
+
+func (r *TrafficRecorder) headers(visitAll func(func(key, value []byte))) map[string]string {
+	headers := map[string]string{}
+	visitAll(func(key, value []byte) {
+		if r.isRedacted(string(key)) {
+			headers[string(key)] = redactedValue
+		} else {
+			headers[string(key)] = string(value)
+		}
+	})
+	return headers
+}
+
+func (r *TrafficRecorder) uri(uri *fasthttp.URI) string {
+	redacted := false
+	uri.QueryArgs().VisitAll(func(key, _ []byte) {
+		redacted = redacted || r.isRedacted(string(key))
+	})
+	if !redacted {
+		return string(uri.RequestURI())
+	}
+	sanitised := &fasthttp.URI{}
+	uri.CopyTo(sanitised)
+	uri.QueryArgs().VisitAll(func(key, _ []byte) {
+		if r.isRedacted(string(key)) {
+			sanitised.QueryArgs().SetBytesK(key, redactedValue)
+		}
+	})
+	return string(sanitised.RequestURI())
+}
+
+// message creates a recorded message with body, redacting JSON bodies
+func (r *TrafficRecorder) message(body []byte) RecordedMessage {
+	if len(body) == 0 {
+		return RecordedMessage{}
+	}
+	value, err := decodeJSON(body)
+	if err != nil {
+		return RecordedMessage{Text: string(body)}
+	}
+	redacted, err := json.Marshal(r.redactor.Decoded(value))
+	if err != nil {
+		return RecordedMessage{Text: string(body)}
+	}
+	return RecordedMessage{Body: redacted}
+}
+
+// decodeJSON unmarshals body keeping numbers as they are, so that amounts do not lose precision as float64
+func decodeJSON(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+type recordingTraceIDKey struct{}
+
+// recordingTraceID returns the id correlating records, being the trace id if available
+func recordingTraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(recordingTraceIDKey{}).(string); ok {
+		return id
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
+
+// newRecordingTraceID returns the trace id if available, otherwise a random id of the same format
+func newRecordingTraceID(ctx context.Context) string {
+	if id := recordingTraceID(ctx); id != "" {
+		return id
+	}
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// TrafficRecordingMiddleware records all requests and responses, except for ping
+func TrafficRecordingMiddleware(recorder *TrafficRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if bytes.HasSuffix(c.Request().URI().Path(), pathPing) {
+			return c.Next()
+		}
+		traceID := newRecordingTraceID(c.UserContext())
+		c.SetUserContext(context.WithValue(c.UserContext(), recordingTraceIDKey{}, traceID))
+		start := time.Now()
+
+		err := c.Next()
+
+		record := TrafficRecord{
+			Time:     start,
+			TraceID:  traceID,
+			Kind:     RecordKindProvider,
+			Duration: time.Since(start),
+			Request:  recorder.message(c.Request().Body()),
+		}
+		record.Request.Method = c.Method()
+		record.Request.URL = recorder.uri(c.Request().URI())
+		record.Request.Headers = recorder.headers(c.Request().Header.VisitAll)
+
+		if c.Response().IsBodyStream() {
+			record.Response.Text = "[stream]"
+		} else {
+			record.Response = recorder.message(c.Response().Body())
+		}
+		record.Response.Status = c.Response().StatusCode()
+		record.Response.Headers = recorder.headers(c.Response().Header.VisitAll)
+		if err != nil {
+			record.Error = err.Error()
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				record.Response.Status = fiberErr.Code
+			}
+		}
+		recorder.Record(record)
+		return err
+	}
+}
+
+// recordingPamClient records all calls to the PAM
+type recordingPamClient struct {
+	pam.PamClient
+	recorder *TrafficRecorder
+}
+
+// RecordPAMTraffic wraps client, recording all PAM calls with the trace id of the provider request causing them
+func RecordPAMTraffic(client pam.PamClient, recorder *TrafficRecorder) pam.PamClient {
+	return &recordingPamClient{PamClient: client, recorder: recorder}
+}
+
+func (c *recordingPamClient) record(ctx context.Context, operation string, start time.Time, req, res any, err error) {
+	record := TrafficRecord{
+		Time:      start,
+		TraceID:   recordingTraceID(ctx),
+		Kind:      RecordKindPAM,
+		Operation: operation,
+		Duration:  time.Since(start),
+		Request:   c.recorder.marshalMessage(req),
+		Response:  c.recorder.marshalMessage(res),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	c.recorder.Record(record)
+}
+
+func (r *TrafficRecorder) marshalMessage(value any) RecordedMessage {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return RecordedMessage{}
+	}
+	return r.message(body)
+}
+
+func (c *recordingPamClient) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, error) {
+	var (
+		ctx context.Context
+		req pam.GetSessionRequest
+	)
+	start := time.Now()
+	res, err := c.PamClient.GetSession(func() (context.Context, pam.GetSessionRequest, error) {
+		var mErr error
+		ctx, req, mErr = rm()
+		return ctx, req, mErr
+	})
+	c.record(ctx, "GetSession", start, req, res, err)
+	return res, err
+}
+
+func (c *recordingPamClient) RefreshSession(rm pam.RefreshSessionRequestMapper) (*pam.Session, error) {
+	var (
+		ctx context.Context
+		req pam.RefreshSessionRequest
+	)
+	start := time.Now()
+	res, err := c.PamClient.RefreshSession(func() (context.Context, pam.RefreshSessionRequest, error) {
+		var mErr error
+		ctx, req, mErr = rm()
+		return ctx, req, mErr
+	})
+	c.record(ctx, "RefreshSession", start, req, res, err)
+	return res, err
+}
+
+func (c *recordingPamClient) GetBalance(rm pam.GetBalanceRequestMapper) (*pam.Balance, error) {
+	var (
+		ctx context.Context
+		req pam.GetBalanceRequest
+	)
+	start := time.Now()
+	res, err := c.PamClient.GetBalance(func() (context.Context, pam.GetBalanceRequest, error) {
+		var mErr error
+		ctx, req, mErr = rm()
+		return ctx, req, mErr
+	})
+	c.record(ctx, "GetBalance", start, req, res, err)
+	return res, err
+}
+
+func (c *recordingPamClient) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam.Transaction, error) {
+	var (
+		ctx context.Context
+		req pam.GetTransactionsRequest
+	)
+	start := time.Now()
+	res, err := c.PamClient.GetTransactions(func() (context.Context, pam.GetTransactionsRequest, error) {
+		var mErr error
+		ctx, req, mErr = rm()
+		return ctx, req, mErr
+	})
+	c.record(ctx, "GetTransactions", start, req, res, err)
+	return res, err
+}
+
+func (c *recordingPamClient) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	var (
+		ctx context.Context
+		req *pam.AddTransactionRequest
+	)
+	start := time.Now()
+	res, err := c.PamClient.AddTransaction(func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		var mErr error
+		ctx, req, mErr = rm(r)
+		return ctx, req, mErr
+	})
+	c.record(ctx, "AddTransaction", start, req, res, err)
+	return res, err
+}
+
+func (c *recordingPamClient) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRound, error) {
+	var (
+		ctx context.Context
+		req pam.GetGameRoundRequest
+	)
+	start := time.Now()
+	res, err := c.PamClient.GetGameRound(func() (context.Context, pam.GetGameRoundRequest, error) {
+		var mErr error
+		ctx, req, mErr = rm()
+		return ctx, req, mErr
+	})
+	c.record(ctx, "GetGameRound", start, req, res, err)
+	return res, err
+}
@@ -0,0 +1,94 @@
+package ops
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error {
+	return nil
+}
+
+func TestTrafficRecording(t *testing.T) {
+	buffer := &bufferCloser{}
+	recorder := newTrafficRecorder(buffer, []string{"sid"})
+	client := RecordPAMTraffic(transactionPamStub{}, recorder)
+
+	app := fiber.New()
+	app.Use(TrafficRecordingMiddleware(recorder))
+	app.Post("/evolution/debit", func(c *fiber.Ctx) error {
+		_, err := client.AddTransaction(func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+			return c.UserContext(), &pam.AddTransactionRequest{PlayerID: "player"}, nil
+		})
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"status": "OK", "sid": "new-session"})
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/evolution/debit?authToken=secret&casino=1",
+		strings.NewReader(`{"sid":"session","userId":"player","transaction":{"amount":1}}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer secret")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/ping", nil))
+	require.NoError(t, err)
+
+	records, err := ReadTrafficRecords(&buffer.Buffer)
+	require.NoError(t, err)
+	require.Len(t, records, 2, "ping is not recorded")
+	pamRecord, providerRecord := records[0], records[1]
+
+	assert.Equal(t, RecordKindPAM, pamRecord.Kind)
+	assert.Equal(t, "AddTransaction", pamRecord.Operation)
+	assert.Contains(t, string(pamRecord.Request.Body), `"PlayerID":"player"`)
+	assert.NotEmpty(t, providerRecord.TraceID)
+	assert.Equal(t, providerRecord.TraceID, pamRecord.TraceID, "PAM calls are correlated with provider requests")
+
+	assert.Equal(t, RecordKindProvider, providerRecord.Kind)
+	assert.Equal(t, fiber.MethodPost, providerRecord.Request.Method)
+	assert.Equal(t, "/evolution/debit?authToken=%5BREDACTED%5D&casino=1", providerRecord.Request.URL)
+	assert.Equal(t, redactedValue, providerRecord.Request.Headers[fiber.HeaderAuthorization])
+	assert.JSONEq(t, `{"sid":"[REDACTED]","userId":"player","transaction":{"amount":1}}`, string(providerRecord.Request.Body))
+	assert.Equal(t, fiber.StatusOK, providerRecord.Response.Status)
+	assert.JSONEq(t, `{"status":"OK","sid":"[REDACTED]"}`, string(providerRecord.Response.Body))
+}
+
+func TestTrafficRecorder_message(t *testing.T) {
+	recorder := newTrafficRecorder(&bufferCloser{}, nil)
+
+	assert.Equal(t, RecordedMessage{}, recorder.message(nil))
+	assert.Equal(t, RecordedMessage{Text: "<xml/>"}, recorder.message([]byte("<xml/>")))
+	assert.JSONEq(t, `[{"authToken":"[REDACTED]"}]`, string(recorder.message([]byte(`[{"authToken":"x"}]`)).Body))
+	assert.Equal(t, `{"amount":0.12345678901234567890}`, string(recorder.message([]byte(`{"amount":0.12345678901234567890}`)).Body),
+		"amounts keep their precision")
+}
+
+func TestTrafficRecorder_loggingRules(t *testing.T) {
+	redact.Register("playerSecret")
+	recorder := newTrafficRecorder(&bufferCloser{}, nil)
+
+	message := recorder.marshalMessage(pam.GetSessionRequest{Params: pam.GetSessionParams{XPlayerToken: "token"}})
+	assert.Contains(t, string(message.Body), `"X-Player-Token":"[REDACTED]"`)
+	assert.JSONEq(t, `{"playerSecret":"[REDACTED]"}`, string(recorder.message([]byte(`{"playerSecret":"x"}`)).Body))
+}
+
+func TestNewTrafficRecorder_disabled(t *testing.T) {
+	assert.Nil(t, NewTrafficRecorder(configs.RecordingConfig{}))
+}
@@ -0,0 +1,11 @@
+// Package redact replaces the values of sensitive fields, such as session tokens and api keys, before requests
+// and responses are logged or recorded.
+//
+// Rules are either field names or JSON paths. A name, such as "token", redacts JSON fields, query parameters and
+// headers with that name at any depth, compared case-insensitively. A path, such as "$.player.session.id", redacts
+// the JSON field at that path from the root of a body, with "*" matching any field name and arrays traversed
+// transparently.
+//
+// Logging uses the rules registered by providers for their token fields using Register, together with the rules
+// configured using Configure.
+package redact
@@ -0,0 +1,240 @@
+package redact
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+)
+
+// Value replacing redacted values
+const Value = "[REDACTED]"
+
+// Redactor redacts values matching its rules
+type Redactor struct {
+	names map[string]struct{}
+	paths [][]string
+	// needles are the lower case names any redacted JSON field has, used to skip bodies without them
+	needles [][]byte
+}
+
+// New creates a Redactor from name and path rules
+func New(rules ...string) *Redactor {
+	r := &Redactor{names: map[string]struct{}{}}
+	needles := map[string]struct{}{}
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+		if strings.HasPrefix(rule, "$.") || strings.Contains(rule, ".") {
+			path := strings.Split(strings.TrimPrefix(rule, "$."), ".")
+			r.paths = append(r.paths, path)
+			if last := path[len(path)-1]; last != "*" {
+				needles[`"`+last+`"`] = struct{}{}
+			} else {
+				needles[`"`] = struct{}{}
+			}
+		} else {
+			r.names[rule] = struct{}{}
+			needles[`"`+rule+`"`] = struct{}{}
+		}
+	}
+	for needle := range needles {
+		r.needles = append(r.needles, []byte(needle))
+	}
+	return r
+}
+
+// Name returns true if values named name, such as query parameters and headers, are redacted
+func (r *Redactor) Name(name string) bool {
+	if r == nil {
+		return false
+	}
+	_, found := r.names[strings.ToLower(name)]
+	return found
+}
+
+// JSON returns body with redacted fields replaced. Bodies without redacted fields, or that are not valid JSON,
+// are returned as is.
+func (r *Redactor) JSON(body []byte) []byte {
+	if r == nil || !r.mayContain(body) {
+		return body
+	}
+	value, err := decode(body)
+	if err != nil {
+		return body
+	}
+	redacted, err := json.Marshal(r.redact(value, nil))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// Value returns v marshalled to JSON with redacted fields replaced
+func (r *Redactor) Value(v any) ([]byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return r.JSON(body), nil
+}
+
+// Decoded redacts an already decoded JSON value, as unmarshalled into any, in place
+func (r *Redactor) Decoded(value any) any {
+	if r == nil {
+		return value
+	}
+	return r.redact(value, nil)
+}
+
+// URL returns rawURL with the values of redacted query parameters replaced, keeping everything else as is
+func (r *Redactor) URL(rawURL string) string {
+	base, query, found := strings.Cut(rawURL, "?")
+	if !found {
+		return rawURL
+	}
+	query, fragment, hasFragment := strings.Cut(query, "#")
+	result := base + "?" + r.Query(query)
+	if hasFragment {
+		result += "#" + fragment
+	}
+	return result
+}
+
+// Query returns the URL encoded query, such as a form body, with the values of redacted parameters replaced
+func (r *Redactor) Query(query string) string {
+	if r == nil || len(r.names) == 0 {
+		return query
+	}
+	params := strings.Split(query, "&")
+	redacted := false
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && r.Name(name) {
+			params[i] = key + "=" + url.QueryEscape(Value)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return query
+	}
+	return strings.Join(params, "&")
+}
+
+// mayContain returns false if body can not contain any redacted field, avoiding decoding it
+func (r *Redactor) mayContain(body []byte) bool {
+	if len(r.needles) == 0 {
+		return false
+	}
+	lower := bytes.ToLower(body)
+	for _, needle := range r.needles {
+		if bytes.Contains(lower, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) redact(value any, path []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			fieldPath := append(path[:len(path):len(path)], strings.ToLower(key))
+			if r.matches(fieldPath) {
+				v[key] = Value
+			} else {
+				v[key] = r.redact(field, fieldPath)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = r.redact(v[i], path)
+		}
+	}
+	return value
+}
+
+// matches returns true if the field at path is redacted, by its name or its path
+func (r *Redactor) matches(path []string) bool {
+	if _, found := r.names[path[len(path)-1]]; found {
+		return true
+	}
+	for _, rule := range r.paths {
+		if matchPath(rule, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPath(rule, path []string) bool {
+	if len(rule) != len(path) {
+		return false
+	}
+	for i := range rule {
+		if rule[i] != "*" && rule[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// decode unmarshals body keeping numbers as they are, instead of converting them to float64
+func decode(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+var (
+	mu         sync.Mutex
+	registered []string
+	configured []string
+	logging    atomic.Pointer[Redactor]
+)
+
+func init() {
+	Register(
+		"Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Player-Token",
+	)
+}
+
+// Register adds rules used by Logging, intended for providers and PAMs to redact their token fields
+func Register(rules ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered = append(registered, rules...)
+	logging.Store(New(append(append([]string{}, registered...), configured...)...))
+}
+
+// Configure sets the configured rules used by Logging, in addition to the registered rules
+func Configure(rules []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	configured = append([]string{}, rules...)
+	logging.Store(New(append(append([]string{}, registered...), configured...)...))
+}
+
+// LoggingRules returns the registered and configured rules of the Redactor used for logging
+func LoggingRules() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	return append(append([]string{}, registered...), configured...)
+}
+
+// Logging returns the Redactor used for logging, with both registered and configured rules
+func Logging() *Redactor {
+	return logging.Load()
+}
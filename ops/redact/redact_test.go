@@ -0,0 +1,149 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		body  string
+		want  string
+	}{
+		{
+			name:  "field name at any depth",
+			rules: []string{"sid"},
+			body:  `{"sid":"abc","user":{"sid":"def","name":"joe"}}`,
+			want:  `{"sid":"[REDACTED]","user":{"name":"joe","sid":"[REDACTED]"}}`,
+		},
+		{
+			name:  "field names are case insensitive",
+			rules: []string{"Token"},
+			body:  `{"TOKEN":"abc","token":"def"}`,
+			want:  `{"TOKEN":"[REDACTED]","token":"[REDACTED]"}`,
+		},
+		{
+			name:  "path from root",
+			rules: []string{"$.player.id"},
+			body:  `{"id":"keep","player":{"id":"abc","session":{"id":"keep"}}}`,
+			want:  `{"id":"keep","player":{"id":"[REDACTED]","session":{"id":"keep"}}}`,
+		},
+		{
+			name:  "path without root",
+			rules: []string{"player.id"},
+			body:  `{"player":{"id":"abc"}}`,
+			want:  `{"player":{"id":"[REDACTED]"}}`,
+		},
+		{
+			name:  "path with wildcard",
+			rules: []string{"$.*.token"},
+			body:  `{"token":"keep","a":{"token":"abc"},"b":{"token":"def"}}`,
+			want:  `{"a":{"token":"[REDACTED]"},"b":{"token":"[REDACTED]"},"token":"keep"}`,
+		},
+		{
+			name:  "arrays are traversed",
+			rules: []string{"$.players.id"},
+			body:  `{"players":[{"id":"abc"},{"id":"def"}]}`,
+			want:  `{"players":[{"id":"[REDACTED]"},{"id":"[REDACTED]"}]}`,
+		},
+		{
+			name:  "objects are redacted as a whole",
+			rules: []string{"session"},
+			body:  `{"session":{"id":"abc"}}`,
+			want:  `{"session":"[REDACTED]"}`,
+		},
+		{
+			name:  "numbers are kept",
+			rules: []string{"sid"},
+			body:  `{"sid":"abc","amount":12345678901234567.123456}`,
+			want:  `{"amount":12345678901234567.123456,"sid":"[REDACTED]"}`,
+		},
+		{
+			name:  "body without redacted fields is kept as is",
+			rules: []string{"sid"},
+			body:  `{"b":1, "a":2}`,
+			want:  `{"b":1, "a":2}`,
+		},
+		{
+			name:  "value with redacted name is kept",
+			rules: []string{"sid"},
+			body:  `{"name":"sid"}`,
+			want:  `{"name":"sid"}`,
+		},
+		{
+			name:  "invalid json is kept as is",
+			rules: []string{"sid"},
+			body:  `{"sid":`,
+			want:  `{"sid":`,
+		},
+		{
+			name: "without rules",
+			body: `{"sid":"abc"}`,
+			want: `{"sid":"abc"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(New(tt.rules...).JSON([]byte(tt.body))))
+		})
+	}
+}
+
+func TestRedactor_URL(t *testing.T) {
+	r := New("authToken", "sid", "$.player.id")
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "/balance?authToken=secret", want: "/balance?authToken=%5BREDACTED%5D"},
+		{url: "http://host/balance?a=1&AUTHTOKEN=secret&b=2#top", want: "http://host/balance?a=1&AUTHTOKEN=%5BREDACTED%5D&b=2#top"},
+		{url: "/balance?auth%54oken=secret", want: "/balance?auth%54oken=%5BREDACTED%5D"},
+		{url: "/balance?sid", want: "/balance?sid=%5BREDACTED%5D"},
+		{url: "/balance?player.id=1", want: "/balance?player.id=1"},
+		{url: "/balance?a=1", want: "/balance?a=1"},
+		{url: "/balance", want: "/balance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.URL(tt.url))
+		})
+	}
+	assert.Equal(t, "token=%5BREDACTED%5D&a=1", New("token").Query("token=abc&a=1"))
+}
+
+func TestRedactor_Value(t *testing.T) {
+	body, err := New("X-Player-Token").Value(struct {
+		Token  string `json:"X-Player-Token"`
+		Amount int    `json:"amount"`
+	}{Token: "abc", Amount: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"X-Player-Token":"[REDACTED]","amount":1}`, string(body))
+
+	_, err = New().Value(func() {})
+	assert.Error(t, err)
+}
+
+func TestNilRedactor(t *testing.T) {
+	var r *Redactor
+	assert.False(t, r.Name("token"))
+	assert.Equal(t, `{"token":"abc"}`, string(r.JSON([]byte(`{"token":"abc"}`))))
+	assert.Equal(t, "/a?token=abc", r.URL("/a?token=abc"))
+}
+
+func TestLogging(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+
+	assert.True(t, Logging().Name("authorization"), "default rules are registered")
+	assert.False(t, Logging().Name("userId"))
+
+	Configure([]string{"userId"})
+	assert.True(t, Logging().Name("userId"), "configured rules are added")
+	assert.True(t, Logging().Name("authorization"), "configured rules are added to registered rules")
+
+	Configure(nil)
+	assert.False(t, Logging().Name("userId"), "configured rules are replaced")
+}
@@ -0,0 +1,233 @@
+package ops
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"github.com/valyala/fasthttp"
+)
+
+// headers set by the client when sending replayed requests
+var replaySkippedHeaders = map[string]struct{}{
+	strings.ToLower(fiber.HeaderContentLength): {},
+	strings.ToLower(fiber.HeaderHost):          {},
+	strings.ToLower(fiber.HeaderConnection):    {},
+}
+
+// ReplayOptions options for replaying recorded provider requests
+type ReplayOptions struct {
+	// TargetURL base url of the Valkyrie provider server receiving replayed requests
+	TargetURL string
+	// TraceIDs replays only requests of these traces, if set
+	TraceIDs []string
+	// Headers replacing recorded request headers, required for redacted ones
+	Headers map[string]string
+	// Query parameters replacing recorded ones, required for redacted ones
+	Query map[string]string
+	// Ignore names of JSON fields not compared, such as timestamps and generated ids
+	Ignore []string
+	// Timeout of each replayed request
+	Timeout time.Duration
+}
+
+// ReplayResult outcome of a replayed request, with any differences to the recorded response
+type ReplayResult struct {
+	Record TrafficRecord
+	Status int
+	Diffs  []string
+	Err    error
+}
+
+// ReadTrafficRecords reads JSON lines written by TrafficRecorder
+func ReadTrafficRecords(r io.Reader) ([]TrafficRecord, error) {
+	var records []TrafficRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var record TrafficRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid traffic record on line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// Replay re-sends the recorded provider requests in the order they were received and compares
+// the responses with the recorded ones. PAM records are not replayed, they are the result of
+// the provider requests.
+func Replay(ctx context.Context, records []TrafficRecord, opts ReplayOptions) []ReplayResult {
+	traces := map[string]struct{}{}
+	for _, id := range opts.TraceIDs {
+		traces[id] = struct{}{}
+	}
+	var requests []TrafficRecord
+	for _, record := range records {
+		if _, found := traces[record.TraceID]; record.Kind == RecordKindProvider && (len(traces) == 0 || found) {
+			requests = append(requests, record)
+		}
+	}
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].Time.Before(requests[j].Time) })
+
+	ignore := map[string]struct{}{}
+	for _, name := range opts.Ignore {
+		ignore[strings.ToLower(name)] = struct{}{}
+	}
+
+	client := &fasthttp.Client{NoDefaultUserAgentHeader: true}
+	results := make([]ReplayResult, 0, len(requests))
+	for _, record := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, replayRecord(client, record, opts, ignore))
+	}
+	return results
+}
+
+func replayRecord(client *fasthttp.Client, record TrafficRecord, opts ReplayOptions, ignore map[string]struct{}) ReplayResult {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(strings.TrimSuffix(opts.TargetURL, "/") + record.Request.URL)
+	req.Header.SetMethod(record.Request.Method)
+	for key, value := range record.Request.Headers {
+		if _, skipped := replaySkippedHeaders[strings.ToLower(key)]; !skipped {
+			req.Header.Set(key, value)
+		}
+	}
+	for key, value := range opts.Headers {
+		req.Header.Set(key, value)
+	}
+	for key, value := range opts.Query {
+		req.URI().QueryArgs().Set(key, value)
+	}
+	if len(record.Request.Body) > 0 {
+		req.SetBody(record.Request.Body)
+	} else {
+		req.SetBodyString(record.Request.Text)
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	result := ReplayResult{Record: record}
+	if result.Err = client.DoTimeout(req, resp, timeout); result.Err != nil {
+		return result
+	}
+	result.Status = resp.StatusCode()
+
+	if result.Status != record.Response.Status {
+		result.Diffs = append(result.Diffs, fmt.Sprintf("status: recorded %d, replayed %d", record.Response.Status, result.Status))
+	}
+	result.Diffs = append(result.Diffs, diffBodies(record.Response, resp.Body(), ignore)...)
+	return result
+}
+
+// diffBodies compares a recorded body with a replayed one, comparing JSON bodies field by field
+func diffBodies(recorded RecordedMessage, replayed []byte, ignore map[string]struct{}) []string {
+	if len(recorded.Body) == 0 {
+		if recorded.Text != string(replayed) {
+			return []string{fmt.Sprintf("body: recorded %q, replayed %q", recorded.Text, replayed)}
+		}
+		return nil
+	}
+
+	expected, err := decodeJSON(recorded.Body)
+	if err != nil {
+		return []string{fmt.Sprintf("body: invalid recorded json: %v", err)}
+	}
+	actual, err := decodeJSON(replayed)
+	if err != nil {
+		return []string{fmt.Sprintf("body: recorded json, replayed %q", replayed)}
+	}
+	var diffs []string
+	diffJSON("body", expected, actual, ignore, &diffs)
+	return diffs
+}
+
+func diffJSON(path string, expected, actual any, ignore map[string]struct{}, diffs *[]string) {
+	if expected == redactedValue {
+		return
+	}
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e)+len(a))
+		for key := range e {
+			keys = append(keys, key)
+		}
+		for key := range a {
+			if _, found := e[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if _, ignored := ignore[strings.ToLower(key)]; ignored {
+				continue
+			}
+			ev, inExpected := e[key]
+			av, inActual := a[key]
+			switch {
+			case !inActual:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: recorded %s, replayed missing", path, key, jsonString(ev)))
+			case !inExpected:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: recorded missing, replayed %s", path, key, jsonString(av)))
+			default:
+				diffJSON(path+"."+key, ev, av, ignore, diffs)
+			}
+		}
+		return
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(e) {
+			break
+		}
+		for i := range e {
+			diffJSON(fmt.Sprintf("%s[%d]", path, i), e[i], a[i], ignore, diffs)
+		}
+		return
+	}
+	if e, ok := expected.(json.Number); ok {
+		if a, ok := actual.(json.Number); ok && numbersEqual(e, a) {
+			return
+		}
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: recorded %s, replayed %s", path, jsonString(expected), jsonString(actual)))
+	}
+}
+
+// numbersEqual compares numbers by value, such as 1.50 and 1.5
+func numbersEqual(a, b json.Number) bool {
+	da, aErr := decimal.NewFromString(a.String())
+	db, bErr := decimal.NewFromString(b.String())
+	return aErr == nil && bErr == nil && da.Equal(db)
+}
+
+func jsonString(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
@@ -0,0 +1,124 @@
+package ops
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.URL.RequestURI()+" "+r.Header.Get("Authorization")+" "+string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"OK","balance":10,"uuid":"new"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	records := []TrafficRecord{
+		{
+			Time: now.Add(time.Second), TraceID: "2", Kind: RecordKindProvider,
+			Request:  RecordedMessage{Method: http.MethodPost, URL: "/debit?authToken=%5BREDACTED%5D", Body: []byte(`{"amount":1}`)},
+			Response: RecordedMessage{Status: http.StatusOK, Body: []byte(`{"status":"OK","balance":9,"uuid":"old"}`)},
+		},
+		{
+			Time: now, TraceID: "1", Kind: RecordKindProvider,
+			Request:  RecordedMessage{Method: http.MethodPost, URL: "/check", Headers: map[string]string{"Authorization": redactedValue}},
+			Response: RecordedMessage{Status: http.StatusOK, Body: []byte(`{"status":"OK","balance":10,"uuid":"old"}`)},
+		},
+		{
+			Time: now, TraceID: "1", Kind: RecordKindPAM, Operation: "GetSession",
+		},
+	}
+
+	results := Replay(context.Background(), records, ReplayOptions{
+		TargetURL: server.URL,
+		Headers:   map[string]string{"Authorization": "Bearer test"},
+		Query:     map[string]string{"authToken": "token"},
+		Ignore:    []string{"uuid"},
+	})
+
+	require.Len(t, results, 2, "only provider requests are replayed")
+	assert.Equal(t, []string{
+		"/check?authToken=token Bearer test ",
+		"/debit?authToken=token Bearer test {\"amount\":1}",
+	}, received, "replayed in recorded order")
+	assert.Empty(t, results[0].Diffs)
+	assert.Equal(t, []string{"body.balance: recorded 9, replayed 10"}, results[1].Diffs)
+
+	results = Replay(context.Background(), records, ReplayOptions{TargetURL: server.URL, TraceIDs: []string{"2"}})
+	require.Len(t, results, 1)
+	assert.Equal(t, "2", results[0].Record.TraceID)
+}
+
+func TestDiffBodies(t *testing.T) {
+	tests := []struct {
+		name     string
+		recorded RecordedMessage
+		replayed string
+		want     []string
+	}{
+		{
+			name:     "equal json with different formatting",
+			recorded: RecordedMessage{Body: []byte(`{"a":1,"b":[1,2]}`)},
+			replayed: `{ "b": [1, 2], "a": 1 }`,
+		},
+		{
+			name:     "differing fields",
+			recorded: RecordedMessage{Body: []byte(`{"a":1,"b":[1,2],"c":"x"}`)},
+			replayed: `{"a":2,"b":[1,3],"d":true}`,
+			want: []string{
+				"body.a: recorded 1, replayed 2",
+				"body.b[1]: recorded 2, replayed 3",
+				`body.c: recorded "x", replayed missing`,
+				"body.d: recorded missing, replayed true",
+			},
+		},
+		{
+			name:     "amounts compared exactly",
+			recorded: RecordedMessage{Body: []byte(`{"a":1.50,"b":0.100000000000000001}`)},
+			replayed: `{"a":1.5,"b":0.1}`,
+			want:     []string{"body.b: recorded 0.100000000000000001, replayed 0.1"},
+		},
+		{
+			name:     "redacted values are not compared",
+			recorded: RecordedMessage{Body: []byte(`{"sid":"[REDACTED]"}`)},
+			replayed: `{"sid":"abc"}`,
+		},
+		{
+			name:     "text bodies",
+			recorded: RecordedMessage{Text: "<ok/>"},
+			replayed: "<error/>",
+			want:     []string{`body: recorded "<ok/>", replayed "<error/>"`},
+		},
+		{
+			name:     "json replaced by text",
+			recorded: RecordedMessage{Body: []byte(`{}`)},
+			replayed: "error",
+			want:     []string{`body: recorded json, replayed "error"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.want, diffBodies(test.recorded, []byte(test.replayed), map[string]struct{}{}))
+		})
+	}
+}
+
+func TestReadTrafficRecords(t *testing.T) {
+	records, err := ReadTrafficRecords(strings.NewReader(`{"traceId":"1","kind":"provider"}` + "\n\n" + `{"traceId":"2","kind":"pam"}`))
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	_, err = ReadTrafficRecords(strings.NewReader("{}\nnot json"))
+	assert.ErrorContains(t, err, "line 2")
+}
@@ -0,0 +1,227 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+const (
+	// deferredKey marks spans in the trace state whose sampling is decided when the trace has ended
+	deferredKey   = "valkyrie"
+	deferredValue = "deferred"
+
+	// maxDeferredTraces is the maximum number of traces with buffered spans
+	maxDeferredTraces = 10000
+	// maxDeferredSpans is the maximum number of buffered spans of a trace
+	maxDeferredSpans = 256
+	// deferredTraceTTL is how long spans are buffered for a local root span that has not ended
+	deferredTraceTTL = time.Minute
+)
+
+// routeRule samples root spans of requests to paths starting with route
+type routeRule struct {
+	route   string
+	sampler sdktrace.Sampler
+}
+
+// samplingRules resolves the routes of the sampling rules, provider rules matching both the provider
+// and operator routes of the provider
+func samplingRules(vConf *configs.ValkyrieConfig) []routeRule {
+	sampling := vConf.Telemetry.Tracing.Sampling
+	if sampling == nil {
+		return nil
+	}
+	var rules []routeRule
+	for _, rule := range sampling.Rules {
+		sampler := sdktrace.TraceIDRatioBased(rule.Ratio)
+		if rule.Route != "" {
+			rules = append(rules, routeRule{route: rule.Route, sampler: sampler})
+		}
+		if rule.Provider == "" {
+			continue
+		}
+		found := false
+		for _, p := range vConf.Providers {
+			if strings.EqualFold(p.Name, rule.Provider) {
+				found = true
+				rules = append(rules,
+					routeRule{route: vConf.ProviderBasePath + p.BasePath, sampler: sampler},
+					routeRule{route: vConf.OperatorBasePath + p.BasePath, sampler: sampler})
+			}
+		}
+		if !found {
+			log.Warn().Str("provider", rule.Provider).Msg("Sampling rule of provider not configured is ignored")
+		}
+	}
+	return rules
+}
+
+// routeSampler samples root spans by the ratio of the first rule matching the route, which is the span name
+// of server requests, and spans with a parent by the decision of their parent.
+//
+// Root spans not sampled by ratio are recorded with their sampling deferred, so that traces ending with an error
+// or taking too long can be sampled by the deferredSpanProcessor.
+type routeSampler struct {
+	rules    []routeRule
+	fallback sdktrace.Sampler
+}
+
+func newRouteSampler(rules []routeRule, ratio float64) *routeSampler {
+	return &routeSampler{rules: rules, fallback: sdktrace.TraceIDRatioBased(ratio)}
+}
+
+func (s *routeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanContextFromContext(p.ParentContext)
+	if parent.IsValid() {
+		if parent.IsSampled() {
+			return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample, Tracestate: parent.TraceState()}
+		}
+		if parent.IsRemote() || isDeferred(parent.TraceState()) {
+			return deferred(parent.TraceState())
+		}
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: parent.TraceState()}
+	}
+
+	result := s.sampler(p.Name).ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		return deferred(result.Tracestate)
+	}
+	return result
+}
+
+func (s *routeSampler) sampler(route string) sdktrace.Sampler {
+	for _, rule := range s.rules {
+		if strings.HasPrefix(route, rule.route) {
+			return rule.sampler
+		}
+	}
+	return s.fallback
+}
+
+func (s *routeSampler) Description() string {
+	return fmt.Sprintf("RouteSampler{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+func deferred(state trace.TraceState) sdktrace.SamplingResult {
+	if !isDeferred(state) {
+		if marked, err := state.Insert(deferredKey, deferredValue); err == nil {
+			state = marked
+		}
+	}
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordOnly, Tracestate: state}
+}
+
+func isDeferred(state trace.TraceState) bool {
+	return state.Get(deferredKey) == deferredValue
+}
+
+// deferredTrace spans of a trace buffered until its local root span ends
+type deferredTrace struct {
+	created time.Time
+	spans   []sdktrace.ReadOnlySpan
+	keep    bool
+}
+
+// deferredSpanProcessor passes sampled spans on, and buffers spans with deferred sampling until the local root
+// span of their trace has ended. They are passed on as sampled if any span of the trace failed, or if the root
+// span took at least slowThreshold.
+type deferredSpanProcessor struct {
+	next          sdktrace.SpanProcessor
+	slowThreshold time.Duration
+
+	mu     sync.Mutex
+	traces map[trace.TraceID]*deferredTrace
+}
+
+func newDeferredSpanProcessor(next sdktrace.SpanProcessor, slowThreshold time.Duration) *deferredSpanProcessor {
+	return &deferredSpanProcessor{
+		next:          next,
+		slowThreshold: slowThreshold,
+		traces:        map[trace.TraceID]*deferredTrace{},
+	}
+}
+
+func (p *deferredSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *deferredSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	sc := s.SpanContext()
+	if sc.IsSampled() {
+		p.next.OnEnd(s)
+		return
+	}
+	if !isDeferred(sc.TraceState()) {
+		return
+	}
+
+	keep := s.Status().Code == codes.Error
+	root := !s.Parent().IsValid() || s.Parent().IsRemote()
+	if root && p.slowThreshold > 0 && s.EndTime().Sub(s.StartTime()) >= p.slowThreshold {
+		keep = true
+	}
+
+	p.mu.Lock()
+	t, found := p.traces[sc.TraceID()]
+	if !found {
+		t = &deferredTrace{created: time.Now()}
+		if len(p.traces) >= maxDeferredTraces {
+			p.expire(t.created)
+		}
+		// beyond the limit spans are only kept if the root decides so, without the rest of the trace
+		if len(p.traces) < maxDeferredTraces && !root {
+			p.traces[sc.TraceID()] = t
+		}
+	}
+	if len(t.spans) < maxDeferredSpans {
+		t.spans = append(t.spans, s)
+	}
+	t.keep = t.keep || keep
+	if root {
+		delete(p.traces, sc.TraceID())
+	}
+	p.mu.Unlock()
+
+	if root && t.keep {
+		for _, span := range t.spans {
+			p.next.OnEnd(sampledSpan{ReadOnlySpan: span})
+		}
+	}
+}
+
+// expire removes traces whose local root span has not ended within deferredTraceTTL
+func (p *deferredSpanProcessor) expire(now time.Time) {
+	for id, t := range p.traces {
+		if now.Sub(t.created) > deferredTraceTTL {
+			delete(p.traces, id)
+		}
+	}
+}
+
+func (p *deferredSpanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *deferredSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// sampledSpan a span with sampling deferred, marked as sampled to be exported
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,104 @@
+package ops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+func Test_samplingRules(t *testing.T) {
+	rules := samplingRules(&configs.ValkyrieConfig{
+		ProviderBasePath: "/providers",
+		OperatorBasePath: "/operator",
+		Providers:        []configs.ProviderConf{{Name: "Evolution", BasePath: "/evolution"}},
+		Telemetry: configs.TelemetryConfig{Tracing: configs.TraceConfig{Sampling: &configs.SamplingConfig{
+			Rules: []configs.SamplingRule{
+				{Route: "/providers/redtiger", Ratio: 1},
+				{Provider: "evolution", Ratio: 0.5},
+				{Provider: "unknown", Ratio: 1},
+			},
+		}}},
+	})
+
+	routes := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		routes = append(routes, rule.route)
+	}
+	assert.Equal(t, []string{"/providers/redtiger", "/providers/evolution", "/operator/evolution"}, routes)
+}
+
+func Test_routeSampler(t *testing.T) {
+	sampler := newRouteSampler([]routeRule{
+		{route: "/providers/evolution", sampler: sdktrace.AlwaysSample()},
+	}, 0)
+
+	sampled := sampler.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: context.Background(), TraceID: trace.TraceID{1}, Name: "/providers/evolution/debit"})
+	assert.Equal(t, sdktrace.RecordAndSample, sampled.Decision)
+
+	notSampled := sampler.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: context.Background(), TraceID: trace.TraceID{1}, Name: "/providers/redtiger/stake"})
+	assert.Equal(t, sdktrace.RecordOnly, notSampled.Decision, "sampling is deferred")
+	assert.True(t, isDeferred(notSampled.Tracestate))
+
+	child := func(flags trace.TraceFlags, state trace.TraceState) sdktrace.SamplingDecision {
+		parent := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: flags, TraceState: state})
+		return sampler.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: trace.ContextWithSpanContext(context.Background(), parent), TraceID: trace.TraceID{1}}).Decision
+	}
+	assert.Equal(t, sdktrace.RecordAndSample, child(trace.FlagsSampled, trace.TraceState{}))
+	assert.Equal(t, sdktrace.RecordOnly, child(0, notSampled.Tracestate))
+	assert.Equal(t, sdktrace.Drop, child(0, trace.TraceState{}))
+}
+
+func Test_deferredSpanProcessor(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    string
+		fail     bool
+		duration time.Duration
+		exported int
+	}{
+		{name: "sampled by ratio", route: "/providers/evolution/debit", exported: 2},
+		{name: "not sampled", route: "/providers/redtiger/stake"},
+		{name: "failed", route: "/providers/redtiger/stake", fail: true, exported: 2},
+		{name: "slow", route: "/providers/redtiger/stake", duration: time.Second, exported: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithSampler(newRouteSampler([]routeRule{
+					{route: "/providers/evolution", sampler: sdktrace.AlwaysSample()},
+				}, 0)),
+				sdktrace.WithSpanProcessor(newDeferredSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter), time.Second)),
+			)
+			tracer := tp.Tracer("test")
+			start := time.Now()
+
+			ctx, root := tracer.Start(context.Background(), test.route, trace.WithTimestamp(start))
+			_, child := tracer.Start(ctx, "AddTransaction", trace.WithTimestamp(start))
+			if test.fail {
+				child.SetStatus(codes.Error, "failed")
+			}
+			child.End(trace.WithTimestamp(start))
+			root.End(trace.WithTimestamp(start.Add(test.duration)))
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, test.exported)
+			for _, span := range spans {
+				assert.True(t, span.SpanContext.IsSampled())
+			}
+		})
+	}
+}
@@ -34,6 +34,8 @@ type TracingConfig struct {
 	ServiceName string
 	Namespace   string
 	configs.TraceConfig
+	// samplingRules resolved from the routes and providers of the sampling rules
+	samplingRules []routeRule
 }
 
 // Tracing returns a TracingConfig based on the provided Valkyrie config
@@ -43,6 +45,7 @@ func Tracing(vConf *configs.ValkyrieConfig) *TracingConfig {
 	cfg.Version = vConf.Version
 	cfg.ServiceName = vConf.Telemetry.ServiceName
 	cfg.Namespace = vConf.Telemetry.Namespace
+	cfg.samplingRules = samplingRules(vConf)
 
 	switch ExporterType(cfg.TraceType) {
 	case StdOut:
@@ -65,7 +68,7 @@ func Tracing(vConf *configs.ValkyrieConfig) *TracingConfig {
 // ConfigureTracing configures the tracing framework based on TracingConfig.
 func ConfigureTracing(cfg *TracingConfig) error {
 	// No config - no setup
-	if *cfg == noTracingConfig {
+	if cfg.Exporter == None {
 		return errors.New("no tracing config")
 	}
 
@@ -76,7 +79,14 @@ func ConfigureTracing(cfg *TracingConfig) error {
 	}
 
 	// Always be sure to batch in production.
-	bsp := trace.NewBatchSpanProcessor(exp)
+	var bsp trace.SpanProcessor = trace.NewBatchSpanProcessor(exp)
+	// Set sampling based on upstream
+	sampler := trace.ParentBased(trace.TraceIDRatioBased(cfg.SampleRatio))
+	if cfg.Sampling != nil {
+		// Sampling by route, with errors and slow requests sampled when their traces end
+		sampler = newRouteSampler(cfg.samplingRules, cfg.SampleRatio)
+		bsp = newDeferredSpanProcessor(bsp, cfg.Sampling.SlowThreshold)
+	}
 
 	tp := trace.NewTracerProvider(
 		trace.WithSpanProcessor(bsp),
@@ -87,8 +97,7 @@ func ConfigureTracing(cfg *TracingConfig) error {
 			semconv.ServiceNamespace(cfg.Namespace),
 			semconv.ServiceVersion(cfg.Version),
 		)),
-		// Set sampling based on upstream
-		trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(cfg.SampleRatio))),
+		trace.WithSampler(sampler),
 	)
 
 	otel.SetTracerProvider(tp)
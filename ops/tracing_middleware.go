@@ -20,6 +20,7 @@ func TracingMiddleware(cfg *TracingConfig, apps ...*fiber.App) {
 
 	for _, app := range apps {
 		app.Use(filterPath("/ping", otelfiber.Middleware(otelfiber.WithServerName(cfg.ServiceName))))
+		app.Use(traceLogging())
 	}
 
 	if cfg.GoogleProjectID != "" {
@@ -29,6 +30,22 @@ func TracingMiddleware(cfg *TracingConfig, apps ...*fiber.App) {
 	}
 }
 
+// traceLogging adds the trace and span ids to the logs of requests, correlating them with their traces
+func traceLogging() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		spanContext := trace.SpanFromContext(ctx.UserContext()).SpanContext()
+		if spanContext.IsValid() {
+			logger := log.Ctx(ctx.UserContext()).With().
+				Stringer(traceIDFieldName, spanContext.TraceID()).
+				Stringer(spanIDFieldName, spanContext.SpanID()).
+				Logger()
+			ctx.SetUserContext(logger.WithContext(ctx.UserContext()))
+		}
+
+		return ctx.Next()
+	}
+}
+
 func googleTraceLogging(projectID string) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		// Logger with googleTracingHook registered
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// auditingClient appends every transaction forwarded to the PAM and its outcome to a Log. When the log fails closed,
+// transactions are rejected without being forwarded while records cannot be written.
+type auditingClient struct {
+	pam.PamClient
+	log *Log
+}
+
+// NewAuditingClient wraps client, appending all transactions and their outcome to l
+func NewAuditingClient(client pam.PamClient, l *Log) pam.PamClient {
+	return &auditingClient{PamClient: client, log: l}
+}
+
+func (c *auditingClient) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	if err := c.log.Ready(); err != nil {
+		log.Error().Err(err).Msg("Rejecting transaction")
+		return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrUndefined, ErrMsg: "audit log unavailable", OrigError: err}
+	}
+
+	var (
+		ctx context.Context
+		req *pam.AddTransactionRequest
+	)
+	res, err := c.PamClient.AddTransaction(func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		var mErr error
+		ctx, req, mErr = rm(r)
+		return ctx, req, mErr
+	})
+	// requests failing to map are never forwarded
+	if req == nil {
+		return res, err
+	}
+
+	entry := Entry{
+		Provider:      req.Params.Provider,
+		PlayerID:      req.PlayerID,
+		CorrelationID: req.Params.XCorrelationID,
+		Transaction:   req.Body,
+	}
+	if ctx != nil {
+		entry.ProviderRequestID = correlation.ProviderRequestID(ctx)
+	}
+	if err != nil {
+		code := pam.ValkErrUndefined
+		var valkErr pam.ValkyrieError
+		if errors.As(err, &valkErr) {
+			code = valkErr.ValkErrorCode
+		}
+		entry.ValkErrorCode = &code
+		entry.Error = err.Error()
+	} else if res != nil {
+		if res.TransactionId != nil {
+			entry.TransactionID = *res.TransactionId
+		}
+		entry.Balance = res.Balance
+	}
+	if aErr := c.log.Append(entry); aErr != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		log.Ctx(ctx).Error().Err(aErr).
+			Str("provider", entry.Provider).
+			Str("providerTransactionId", req.Body.ProviderTransactionId).
+			Msg("Failed to audit transaction")
+	}
+	return res, err
+}
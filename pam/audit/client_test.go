@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+type transactionPamStub struct {
+	pam.PamClient
+	err error
+}
+
+func (s transactionPamStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	if _, _, err := rm(pam.SixDecimalRounder); err != nil {
+		return nil, err
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	id := "pam-1"
+	return &pam.TransactionResult{TransactionId: &id, Balance: &pam.Balance{CashAmount: pam.ZeroAmount}}, nil
+}
+
+func TestAuditingClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		pamErr    error
+		mapperErr error
+		expected  func(t *testing.T, records []Record)
+	}{
+		{
+			name: "accepted transaction",
+			expected: func(t *testing.T, records []Record) {
+				require.Len(t, records, 1)
+				assert.Equal(t, "pam-1", records[0].TransactionID)
+				assert.NotNil(t, records[0].Balance)
+				assert.Nil(t, records[0].ValkErrorCode)
+			},
+		},
+		{
+			name:   "failed transaction",
+			pamErr: pam.ValkyrieError{ValkErrorCode: pam.ValkErrOpCashOverdraft, ErrMsg: "overdraft"},
+			expected: func(t *testing.T, records []Record) {
+				require.Len(t, records, 1)
+				assert.Empty(t, records[0].TransactionID)
+				require.NotNil(t, records[0].ValkErrorCode)
+				assert.Equal(t, pam.ValkErrOpCashOverdraft, *records[0].ValkErrorCode)
+				assert.Contains(t, records[0].Error, "overdraft")
+			},
+		},
+		{
+			name:   "failed transaction without code",
+			pamErr: errors.New("connection refused"),
+			expected: func(t *testing.T, records []Record) {
+				require.Len(t, records, 1)
+				require.NotNil(t, records[0].ValkErrorCode)
+				assert.Equal(t, pam.ValkErrUndefined, *records[0].ValkErrorCode)
+			},
+		},
+		{
+			name:      "request not forwarded",
+			mapperErr: errors.New("invalid request"),
+			expected: func(t *testing.T, records []Record) {
+				assert.Empty(t, records)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := &memorySink{}
+			l, err := NewLog(sink)
+			require.NoError(t, err)
+			client := NewAuditingClient(transactionPamStub{err: test.pamErr}, l)
+
+			_, _ = client.AddTransaction(func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+				if test.mapperErr != nil {
+					return context.Background(), nil, test.mapperErr
+				}
+				ctx := correlation.WithProviderRequestID(context.Background(), "uuid-1")
+				return ctx, &pam.AddTransactionRequest{
+					PlayerID: "player",
+					Params:   pam.AddTransactionParams{Provider: "evolution", XCorrelationID: "correlation"},
+					Body:     pam.Transaction{ProviderTransactionId: "tx-1", CashAmount: pam.ZeroAmount},
+				}, nil
+			})
+
+			records, err := ReadRecords(strings.NewReader(sink.content()))
+			require.NoError(t, err)
+			for _, record := range records {
+				assert.Equal(t, "evolution", record.Provider)
+				assert.Equal(t, "player", record.PlayerID)
+				assert.Equal(t, "correlation", record.CorrelationID)
+				assert.Equal(t, "uuid-1", record.ProviderRequestID)
+				assert.Equal(t, "tx-1", record.Transaction.ProviderTransactionId)
+			}
+			test.expected(t, records)
+		})
+	}
+}
+
+func TestAuditingClient_FailClosed(t *testing.T) {
+	sink := &failingSink{failing: true}
+	l, err := NewLogWithConfig(sink, LogConfig{FailClosed: true})
+	require.NoError(t, err)
+	client := NewAuditingClient(transactionPamStub{}, l)
+	forwarded := 0
+	addTransaction := func(id string) error {
+		_, err := client.AddTransaction(func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+			forwarded++
+			return context.Background(), &pam.AddTransactionRequest{
+				PlayerID: "player",
+				Body:     pam.Transaction{ProviderTransactionId: id, CashAmount: pam.ZeroAmount},
+			}, nil
+		})
+		return err
+	}
+
+	// the transaction failing to be audited has been booked, and its record is pending
+	require.NoError(t, addTransaction("tx-1"))
+	assert.Equal(t, 1, forwarded)
+
+	err = addTransaction("tx-2")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	var valkErr pam.ValkyrieError
+	require.ErrorAs(t, err, &valkErr)
+	assert.Equal(t, pam.ValkErrUndefined, valkErr.ValkErrorCode)
+	assert.Equal(t, 1, forwarded, "rejected without being forwarded")
+
+	sink.failing = false
+	require.NoError(t, addTransaction("tx-3"))
+	assert.Equal(t, 2, forwarded)
+	records, err := ReadRecords(strings.NewReader(sink.content()))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "tx-1", records[0].Transaction.ProviderTransactionId)
+	assert.Equal(t, "tx-3", records[1].Transaction.ProviderTransactionId)
+}
@@ -0,0 +1,22 @@
+// Package audit keeps a hash chained record of every wallet transaction forwarded to the PAM, independent of
+// logging which may be sampled or disabled.
+//
+// Each AddTransaction request is appended together with its outcome (transaction id, balance or ValkErrorCode)
+// as a JSON line to a Sink. Lines are hash chained: every record carries its sequence number and the hash of the
+// previous record, and the line holds the hash of the exact record bytes,
+//
+//	{"hash":"<hash of record>","record":{"seq":2,"prevHash":"<hash of record 1>",...}}
+//
+// so that altering, removing or reordering records breaks the chain. The hash is an HMAC-SHA256 when a "key" is
+// configured, so that only holders of the key can rewrite the chain, and a plain SHA-256 otherwise, which only
+// detects accidental changes. A Verifier checks the chain from seq 1, or from a head published by the log when the
+// oldest records have been removed, which is what "valkyrie audit verify" does. The head is logged when the log is
+// closed, and removal of the latest records is detected by comparing it to the last verified hash.
+//
+// Records failing to be written are kept in memory and retried before the next record, and counted by the
+// "audit.write.failed" metric. With "fail_closed", transactions are rejected without being forwarded to the PAM
+// while pending records cannot be written.
+//
+// Sinks are built from configuration by SinkFactory using the configured "sink". The built in "file" sink writes
+// to a file rotated by size, and continues the chain of the previous run on startup.
+package audit
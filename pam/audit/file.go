@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	defaultAuditFilename = "valkyrie-audit.jsonl"
+	// tailChunkSize is how much is read at a time when looking for the last line of a file
+	tailChunkSize = 64 * 1024
+	// rotationTimeFormat is the time format lumberjack adds to the names of rotated files
+	rotationTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+func init() {
+	SinkFactory().Register("file", func(args SinkArgs) (Sink, error) {
+		var config FileConfig
+		if err := mapstructure.Decode(args.Config, &config); err != nil {
+			return nil, fmt.Errorf("invalid audit file config: %w", err)
+		}
+		return NewFileSink(config), nil
+	})
+}
+
+// FileConfig configuration of the file sink
+type FileConfig struct {
+	// Filename is the file to write to, defaults to "valkyrie-audit.jsonl"
+	Filename string `mapstructure:"filename"`
+	// MaxSize is the maximum size in megabytes of the file before it gets rotated, defaults to 100 megabytes
+	MaxSize int `mapstructure:"max_size"`
+	// MaxAge is the maximum number of days to retain rotated files, the default is to retain all
+	MaxAge int `mapstructure:"max_age"`
+	// MaxBackups is the maximum number of rotated files to retain, the default is to retain all
+	MaxBackups int `mapstructure:"max_backups"`
+	// Compress determines if rotated files are compressed using gzip
+	Compress bool `mapstructure:"compress"`
+}
+
+// FileSink appends lines to a file rotated by size. Rotated files are named after the file with the time of
+// rotation added, such as "valkyrie-audit-2024-01-31T10-00-00.000.jsonl", so that they sort in chain order.
+type FileSink struct {
+	mu       sync.Mutex
+	filename string
+	writer   *lumberjack.Logger
+}
+
+// NewFileSink creates a FileSink from config
+func NewFileSink(config FileConfig) *FileSink {
+	if config.Filename == "" {
+		config.Filename = defaultAuditFilename
+	}
+	return &FileSink{
+		filename: config.Filename,
+		writer: &lumberjack.Logger{
+			Filename:   config.Filename,
+			MaxSize:    config.MaxSize,
+			MaxAge:     config.MaxAge,
+			MaxBackups: config.MaxBackups,
+			Compress:   config.Compress,
+		},
+	}
+}
+
+// Write appends line to the file
+func (s *FileSink) Write(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Last returns the last line of the file, or of the most recently rotated file if the file is empty
+func (s *FileSink) Last() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, err := lastLineOfFile(s.filename)
+	if err != nil || last != nil {
+		return last, err
+	}
+	backups, err := RotatedFiles(s.filename)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(backups) - 1; i >= 0; i-- {
+		if last, err = lastLineOfFile(backups[i]); err != nil || last != nil {
+			return last, err
+		}
+	}
+	return nil, nil
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writer.Close()
+}
+
+// RotatedFiles returns the rotated files of filename, oldest first
+func RotatedFiles(filename string) ([]string, error) {
+	ext := filepath.Ext(filename)
+	prefix := strings.TrimSuffix(filename, ext) + "-"
+	matches, err := filepath.Glob(escapeGlob(prefix) + "*" + escapeGlob(ext) + "*")
+	if err != nil {
+		return nil, err
+	}
+	backups := matches[:0]
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSuffix(match, ".gz"), prefix), ext)
+		if _, err = time.Parse(rotationTimeFormat, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func escapeGlob(s string) string {
+	replacer := strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`, `\`, `\\`)
+	return replacer.Replace(s)
+}
+
+// OpenFile opens a file for reading, decompressing gzip compressed rotated files
+func OpenFile(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".gz") {
+		return f, nil
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: gz, file: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	return errors.Join(g.Reader.Close(), g.file.Close())
+}
+
+// lastLineOfFile returns the last non-empty line of a file, or nil if there is none
+func lastLineOfFile(name string) ([]byte, error) {
+	if strings.HasSuffix(name, ".gz") {
+		return lastLineOfCompressed(name)
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return lastLine(f, info.Size())
+}
+
+// lastLine reads backwards from the end until a complete last non-empty line has been read
+func lastLine(r io.ReaderAt, size int64) ([]byte, error) {
+	var tail []byte
+	for offset := size; offset > 0; {
+		n := int64(tailChunkSize)
+		if n > offset {
+			n = offset
+		}
+		offset -= n
+		chunk := make([]byte, n, n+int64(len(tail)))
+		if _, err := r.ReadAt(chunk, offset); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		if offset == 0 && len(trimmed) > 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+func lastLineOfCompressed(name string) ([]byte, error) {
+	f, err := OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var last []byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+	return last, scanner.Err()
+}
@@ -0,0 +1,198 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+// defaultMaxPending is the default number of records kept for retrying while the sink fails
+const defaultMaxPending = 10000
+
+// ErrUnavailable is returned when records cannot be written to the sink
+var ErrUnavailable = errors.New("audit log unavailable")
+
+// LogConfig configuration of the log, independent of the sink
+type LogConfig struct {
+	// Key of the HMAC-SHA256 hashes chaining the records, which then cannot be forged without it. Plain SHA-256
+	// hashes are used if empty.
+	Key string `mapstructure:"key"`
+	// FailClosed rejects transactions without forwarding them to the PAM while records cannot be written
+	FailClosed bool `mapstructure:"fail_closed"`
+	// MaxPending is the maximum number of records kept in memory and retried while the sink fails, defaults to
+	// 10000. Records are dropped when exceeded, breaking the chain.
+	MaxPending int `mapstructure:"max_pending"`
+}
+
+var (
+	metricsOnce    sync.Once
+	failedCounter  metric.Int64Counter
+	droppedCounter metric.Int64Counter
+)
+
+// countFailure reports records failing to be written, and records dropped since too many were pending
+func countFailure(dropped bool) {
+	metricsOnce.Do(func() {
+		meter := otel.Meter("valkyrie-audit")
+		if counter, err := meter.Int64Counter("audit.write.failed",
+			metric.WithUnit("1"),
+			metric.WithDescription("measures the number of audit records failing to be written")); err == nil {
+			failedCounter = counter
+		}
+		if counter, err := meter.Int64Counter("audit.dropped",
+			metric.WithUnit("1"),
+			metric.WithDescription("measures the number of audit records dropped since too many were pending")); err == nil {
+			droppedCounter = counter
+		}
+	})
+	if failedCounter != nil {
+		failedCounter.Add(context.Background(), 1)
+	}
+	if dropped && droppedCounter != nil {
+		droppedCounter.Add(context.Background(), 1)
+	}
+}
+
+// Log appends entries as hash chained records to a Sink. Records failing to be written are kept and retried
+// before the next record, so that the chain stays intact while the sink recovers.
+type Log struct {
+	mu         sync.Mutex
+	sink       Sink
+	key        []byte
+	failClosed bool
+	maxPending int
+	// pending records not yet written, in chain order
+	pending [][]byte
+	seq     uint64
+	hash    string
+	now     func() time.Time
+}
+
+// NewLogFromConfig creates a Log writing to the configured sink, or returns nil if auditing is not configured
+func NewLogFromConfig(config configs.AuditConf) (*Log, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+	var logConfig LogConfig
+	if err := mapstructure.Decode(config, &logConfig); err != nil {
+		return nil, fmt.Errorf("invalid audit config: %w", err)
+	}
+	sink, err := GetSink(SinkArgs{Config: config})
+	if err != nil {
+		return nil, err
+	}
+	return NewLogWithConfig(sink, logConfig)
+}
+
+// NewLog creates a Log continuing the chain of the last line of sink, using plain SHA-256 hashes
+func NewLog(sink Sink) (*Log, error) {
+	return NewLogWithConfig(sink, LogConfig{})
+}
+
+// NewLogWithConfig creates a Log continuing the chain of the last line of sink
+func NewLogWithConfig(sink Sink, config LogConfig) (*Log, error) {
+	l := &Log{
+		sink:       sink,
+		key:        []byte(config.Key),
+		failClosed: config.FailClosed,
+		maxPending: config.MaxPending,
+		hash:       Genesis,
+		now:        time.Now,
+	}
+	if l.maxPending <= 0 {
+		l.maxPending = defaultMaxPending
+	}
+	last, err := sink.Last()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read last audit record: %w", err)
+	}
+	if len(last) > 0 {
+		pos, h, err := parseLine(l.key, last)
+		if err != nil {
+			return nil, fmt.Errorf("unable to continue audit chain: %w", err)
+		}
+		l.seq, l.hash = pos.Seq, h
+	}
+	return l, nil
+}
+
+// Append adds entry as the next record of the chain. If the record cannot be written it is kept and retried,
+// unless too many records are pending, and an error is returned.
+func (l *Log) Append(entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	record := Record{
+		Seq:      l.seq + 1,
+		PrevHash: l.hash,
+		Time:     l.now().UTC(),
+		Entry:    entry,
+	}
+	data, h, err := marshalLine(l.key, record)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit record: %w", err)
+	}
+	if err = l.flush(); err == nil {
+		if err = l.sink.Write(data); err == nil {
+			l.seq, l.hash = record.Seq, h
+			return nil
+		}
+	}
+	if len(l.pending) >= l.maxPending {
+		countFailure(true)
+		return fmt.Errorf("audit record dropped, %d records pending: %w", len(l.pending), err)
+	}
+	countFailure(false)
+	l.pending = append(l.pending, data)
+	l.seq, l.hash = record.Seq, h
+	return fmt.Errorf("unable to write audit record, %d records pending: %w", len(l.pending), err)
+}
+
+// flush writes pending records, in order, until one fails
+func (l *Log) flush() error {
+	for len(l.pending) > 0 {
+		if err := l.sink.Write(l.pending[0]); err != nil {
+			return err
+		}
+		l.pending = l.pending[1:]
+	}
+	l.pending = nil
+	return nil
+}
+
+// Ready writes pending records, returning ErrUnavailable if they still cannot be written and the log fails closed
+func (l *Log) Ready() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.flush(); err != nil && l.failClosed {
+		return fmt.Errorf("%w: %d records pending: %v", ErrUnavailable, len(l.pending), err)
+	}
+	return nil
+}
+
+// Head returns the position of the last record of the chain, to be published for anchoring verifications
+func (l *Log) Head() Head {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Head{Seq: l.seq, Hash: l.hash}
+}
+
+// Close writes pending records, logs the head of the chain for publishing and closes the sink
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	err := l.flush()
+	if err != nil {
+		err = fmt.Errorf("%d audit records not written: %w", len(l.pending), err)
+	}
+	log.Info().Uint64("seq", l.seq).Str("hash", l.hash).Msg("Audit log head")
+	return errors.Join(err, l.sink.Close())
+}
@@ -0,0 +1,303 @@
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// memorySink keeps lines in memory
+type memorySink struct {
+	lines [][]byte
+}
+
+func (s *memorySink) Write(line []byte) error {
+	s.lines = append(s.lines, append([]byte{}, line...))
+	return nil
+}
+
+func (s *memorySink) Last() ([]byte, error) {
+	if len(s.lines) == 0 {
+		return nil, nil
+	}
+	return s.lines[len(s.lines)-1], nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) content() string {
+	return string(bytes.Join(s.lines, []byte("\n")))
+}
+
+func appendEntries(t *testing.T, l *Log, providerTransactionIDs ...string) {
+	for _, id := range providerTransactionIDs {
+		require.NoError(t, l.Append(Entry{
+			Provider:    "evolution",
+			PlayerID:    "player",
+			Transaction: pam.Transaction{ProviderTransactionId: id, CashAmount: pam.ZeroAmount},
+		}))
+	}
+}
+
+func TestLog(t *testing.T) {
+	sink := &memorySink{}
+	l, err := NewLog(sink)
+	require.NoError(t, err)
+	appendEntries(t, l, "1", "2", "3")
+
+	records, err := ReadRecords(strings.NewReader(sink.content()))
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, uint64(1), records[0].Seq)
+	assert.Equal(t, Genesis, records[0].PrevHash)
+	assert.Equal(t, "3", records[2].Transaction.ProviderTransactionId)
+
+	verifier := &Verifier{}
+	require.NoError(t, verifier.Verify(strings.NewReader(sink.content())))
+	assert.Equal(t, 3, verifier.Records)
+	assert.Equal(t, uint64(1), verifier.First)
+	assert.Equal(t, uint64(3), verifier.Last)
+
+	// a new log continues the chain
+	l, err = NewLog(sink)
+	require.NoError(t, err)
+	appendEntries(t, l, "4")
+	verifier = &Verifier{}
+	require.NoError(t, verifier.Verify(strings.NewReader(sink.content())))
+	assert.Equal(t, uint64(4), verifier.Last)
+}
+
+func TestVerifier_Tampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		reason string
+	}{
+		{
+			name: "altered record",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"providerTransactionId":"2"`, `"providerTransactionId":"9"`, 1)
+				return lines
+			},
+			reason: "hash mismatch",
+		},
+		{
+			name: "removed record",
+			tamper: func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			reason: "expected seq 2",
+		},
+		{
+			name: "reordered records",
+			tamper: func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			reason: "expected seq 2",
+		},
+		{
+			name: "removed first record",
+			tamper: func(lines []string) []string {
+				return lines[1:]
+			},
+			reason: "does not start at seq 1",
+		},
+		{
+			name: "removed last record",
+			tamper: func(lines []string) []string {
+				return lines[:2]
+			},
+			reason: "head",
+		},
+		{
+			name: "invalid line",
+			tamper: func(lines []string) []string {
+				return append(lines, "{")
+			},
+			reason: "invalid line",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := &memorySink{}
+			l, err := NewLog(sink)
+			require.NoError(t, err)
+			appendEntries(t, l, "1", "2", "3")
+
+			lines := test.tamper(strings.Split(sink.content(), "\n"))
+			verifier := &Verifier{}
+			err = verifier.Verify(strings.NewReader(strings.Join(lines, "\n")))
+			if test.reason == "head" {
+				// removing the latest records is detected against the published head
+				require.NoError(t, err)
+				assert.NotEqual(t, l.Head(), Head{Seq: verifier.Last, Hash: verifier.Hash})
+				return
+			}
+			var chainErr ChainError
+			require.ErrorAs(t, err, &chainErr)
+			assert.Contains(t, chainErr.Reason, test.reason)
+		})
+	}
+}
+
+func TestVerifier_Anchor(t *testing.T) {
+	sink := &memorySink{}
+	l, err := NewLog(sink)
+	require.NoError(t, err)
+	appendEntries(t, l, "1")
+	head := l.Head()
+	appendEntries(t, l, "2", "3")
+
+	// oldest records removed by rotation
+	rotated := strings.Join(strings.Split(sink.content(), "\n")[1:], "\n")
+	verifier := &Verifier{Anchor: &head}
+	require.NoError(t, verifier.Verify(strings.NewReader(rotated)))
+	assert.Equal(t, uint64(2), verifier.First)
+	assert.Equal(t, l.Head(), Head{Seq: verifier.Last, Hash: verifier.Hash})
+
+	err = (&Verifier{Anchor: &Head{Seq: 1, Hash: Genesis}}).Verify(strings.NewReader(rotated))
+	assert.ErrorContains(t, err, "does not match anchor")
+	err = (&Verifier{Anchor: &Head{Seq: 2, Hash: head.Hash}}).Verify(strings.NewReader(rotated))
+	assert.ErrorContains(t, err, "does not continue from anchor")
+}
+
+func TestLog_Key(t *testing.T) {
+	sink := &memorySink{}
+	l, err := NewLogWithConfig(sink, LogConfig{Key: "secret"})
+	require.NoError(t, err)
+	appendEntries(t, l, "1", "2")
+
+	require.NoError(t, (&Verifier{Key: []byte("secret")}).Verify(strings.NewReader(sink.content())))
+	err = (&Verifier{}).Verify(strings.NewReader(sink.content()))
+	assert.ErrorContains(t, err, "hash mismatch")
+
+	// records altered and rehashed without the key
+	forged := &memorySink{}
+	l, err = NewLog(forged)
+	require.NoError(t, err)
+	appendEntries(t, l, "1", "9")
+	err = (&Verifier{Key: []byte("secret")}).Verify(strings.NewReader(forged.content()))
+	assert.ErrorContains(t, err, "hash mismatch")
+
+	// continuing the chain requires the key
+	_, err = NewLog(sink)
+	assert.ErrorContains(t, err, "unable to continue audit chain")
+	l, err = NewLogWithConfig(sink, LogConfig{Key: "secret"})
+	require.NoError(t, err)
+	appendEntries(t, l, "3")
+	require.NoError(t, (&Verifier{Key: []byte("secret")}).Verify(strings.NewReader(sink.content())))
+}
+
+// failingSink fails writes while failing is set
+type failingSink struct {
+	memorySink
+	failing bool
+}
+
+func (s *failingSink) Write(line []byte) error {
+	if s.failing {
+		return errors.New("disk full")
+	}
+	return s.memorySink.Write(line)
+}
+
+func TestLog_Pending(t *testing.T) {
+	sink := &failingSink{}
+	l, err := NewLogWithConfig(sink, LogConfig{MaxPending: 2})
+	require.NoError(t, err)
+	appendEntries(t, l, "1")
+
+	sink.failing = true
+	assert.ErrorContains(t, l.Append(Entry{Transaction: pam.Transaction{ProviderTransactionId: "2"}}), "1 records pending")
+	assert.ErrorContains(t, l.Append(Entry{Transaction: pam.Transaction{ProviderTransactionId: "3"}}), "2 records pending")
+	assert.ErrorContains(t, l.Append(Entry{Transaction: pam.Transaction{ProviderTransactionId: "4"}}), "dropped")
+	assert.NoError(t, l.Ready(), "only fails when failing closed")
+
+	// pending records are written first once the sink recovers
+	sink.failing = false
+	appendEntries(t, l, "5")
+	records, err := ReadRecords(strings.NewReader(sink.content()))
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, "3", records[2].Transaction.ProviderTransactionId)
+	verifier := &Verifier{}
+	require.NoError(t, verifier.Verify(strings.NewReader(sink.content())))
+	assert.Equal(t, l.Head(), Head{Seq: verifier.Last, Hash: verifier.Hash})
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "audit.jsonl")
+	l, err := NewLogFromConfig(configs.AuditConf{"sink": "file", "filename": filename})
+	require.NoError(t, err)
+	appendEntries(t, l, "1", "2")
+	require.NoError(t, l.Close())
+
+	// chain continues from the last line of the file after a restart
+	l, err = NewLogFromConfig(configs.AuditConf{"sink": "file", "filename": filename})
+	require.NoError(t, err)
+	appendEntries(t, l, "3")
+	require.NoError(t, l.Close())
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	verifier := &Verifier{}
+	require.NoError(t, verifier.Verify(bytes.NewReader(content)))
+	assert.Equal(t, 3, verifier.Records)
+
+	// simulate a rotation to a compressed file, leaving the file empty
+	backup := filepath.Join(dir, "audit-2024-01-31T10-00-00.000.jsonl.gz")
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(backup, compressed.Bytes(), 0o600))
+	require.NoError(t, os.Remove(filename))
+
+	rotated, err := RotatedFiles(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []string{backup}, rotated)
+
+	l, err = NewLogFromConfig(configs.AuditConf{"sink": "file", "filename": filename})
+	require.NoError(t, err)
+	appendEntries(t, l, "4")
+	require.NoError(t, l.Close())
+
+	verifier = &Verifier{}
+	for _, name := range []string{backup, filename} {
+		f, err := OpenFile(name)
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(f))
+		require.NoError(t, f.Close())
+	}
+	assert.Equal(t, 4, verifier.Records)
+}
+
+func TestNewLogFromConfig(t *testing.T) {
+	l, err := NewLogFromConfig(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	_, err = NewLogFromConfig(configs.AuditConf{"filename": "audit.jsonl"})
+	assert.ErrorContains(t, err, "\"sink\" not found")
+
+	_, err = NewLogFromConfig(configs.AuditConf{"sink": "file", "fail_closed": "yes"})
+	assert.ErrorContains(t, err, "invalid audit config")
+
+	_, err = NewLogFromConfig(configs.AuditConf{"sink": "unknown"})
+	assert.ErrorContains(t, err, "'unknown' not found")
+}
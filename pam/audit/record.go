@@ -0,0 +1,214 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// Genesis is the previous hash of the first record of a chain
+var Genesis = strings.Repeat("0", sha256.Size*2)
+
+// maxLineSize is the largest audit line read
+const maxLineSize = 1024 * 1024
+
+// Entry a wallet transaction forwarded to the PAM and its outcome
+type Entry struct {
+	Provider      string `json:"provider"`
+	PlayerID      string `json:"playerId"`
+	CorrelationID string `json:"correlationId,omitempty"`
+	// ProviderRequestID native request ID of the provider, if other than the correlation ID
+	ProviderRequestID string          `json:"providerRequestId,omitempty"`
+	Transaction       pam.Transaction `json:"transaction"`
+	// TransactionID and Balance are set when the PAM accepted the transaction
+	TransactionID string       `json:"transactionId,omitempty"`
+	Balance       *pam.Balance `json:"balance,omitempty"`
+	// ValkErrorCode and Error are set when the transaction failed
+	ValkErrorCode *pam.ValkErrorCode `json:"valkErrorCode,omitempty"`
+	Error         string             `json:"error,omitempty"`
+}
+
+// Record an entry in the chain
+type Record struct {
+	Seq      uint64    `json:"seq"`
+	PrevHash string    `json:"prevHash"`
+	Time     time.Time `json:"time"`
+	Entry
+}
+
+// line is the stored form of a record, with the hash of the exact bytes of the record
+type line struct {
+	Hash   string          `json:"hash"`
+	Record json.RawMessage `json:"record"`
+}
+
+// link the position of a record in the chain
+type link struct {
+	Seq      uint64 `json:"seq"`
+	PrevHash string `json:"prevHash"`
+}
+
+// Head the position of the last record of a chain, published to anchor verifications of the chain to
+type Head struct {
+	Seq  uint64
+	Hash string
+}
+
+// hash returns the HMAC-SHA256 of record using key, or its plain SHA-256 if there is no key
+func hash(key, record []byte) string {
+	if len(key) == 0 {
+		sum := sha256.Sum256(record)
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(record)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// marshalLine returns the stored line of a record and its hash
+func marshalLine(key []byte, record Record) ([]byte, string, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, "", err
+	}
+	h := hash(key, raw)
+	buf := bytes.NewBuffer(make([]byte, 0, len(raw)+len(h)+22))
+	buf.WriteString(`{"hash":"`)
+	buf.WriteString(h)
+	buf.WriteString(`","record":`)
+	buf.Write(raw)
+	buf.WriteString(`}`)
+	return buf.Bytes(), h, nil
+}
+
+// parseLine checks the hash of a stored line and returns the position of its record
+func parseLine(key, data []byte) (link, string, error) {
+	var l line
+	if err := json.Unmarshal(data, &l); err != nil {
+		return link{}, "", fmt.Errorf("invalid line: %w", err)
+	}
+	if l.Hash == "" || len(l.Record) == 0 {
+		return link{}, "", errors.New("invalid line: missing hash or record")
+	}
+	if h := hash(key, l.Record); !hmac.Equal([]byte(h), []byte(l.Hash)) {
+		return link{}, "", fmt.Errorf("hash mismatch, record hashes to %s", h)
+	}
+	var pos link
+	if err := json.Unmarshal(l.Record, &pos); err != nil {
+		return link{}, "", fmt.Errorf("invalid record: %w", err)
+	}
+	return pos, l.Hash, nil
+}
+
+// ReadRecords reads the records of stored lines, without verifying them
+func ReadRecords(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("invalid audit line: %w", err)
+		}
+		var record Record
+		if err := json.Unmarshal(l.Record, &record); err != nil {
+			return nil, fmt.Errorf("invalid audit record: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// ChainError a break in the chain
+type ChainError struct {
+	// Line number in the verified input, starting at 1
+	Line   int
+	Seq    uint64
+	Reason string
+}
+
+func (e ChainError) Error() string {
+	return fmt.Sprintf("audit chain broken at line %d (seq %d): %s", e.Line, e.Seq, e.Reason)
+}
+
+// Verifier checks the integrity of a chain, which may be split over several inputs such as rotated files. Records can
+// only be forged by someone holding the Key, or by anyone if the chain is written without one. Removing the latest
+// records is detected by comparing the last verified Hash to a head published by the log.
+type Verifier struct {
+	// Key of the HMAC-SHA256 hashes of the chain, if written with a key
+	Key []byte
+	// Anchor is a published head the chain continues from, when its oldest records have been removed by rotation.
+	// Without an anchor the chain must start from Genesis at seq 1.
+	Anchor *Head
+	// First is the sequence number of the first verified record
+	First uint64
+	// Last is the sequence number of the last verified record
+	Last uint64
+	// Hash is the hash of the last verified record
+	Hash string
+	// Records is the number of verified records
+	Records int
+}
+
+// Verify checks the records of r and that they continue the chain of any previously verified input
+func (v *Verifier) Verify(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		pos, h, err := parseLine(v.Key, scanner.Bytes())
+		if err != nil {
+			return ChainError{Line: lineNo, Seq: v.Last + 1, Reason: err.Error()}
+		}
+		if err = v.link(pos); err != nil {
+			return ChainError{Line: lineNo, Seq: pos.Seq, Reason: err.Error()}
+		}
+		if v.Records == 0 {
+			v.First = pos.Seq
+		}
+		v.Last, v.Hash = pos.Seq, h
+		v.Records++
+	}
+	return scanner.Err()
+}
+
+func (v *Verifier) link(pos link) error {
+	if v.Records == 0 {
+		switch {
+		case v.Anchor != nil && pos.Seq != v.Anchor.Seq+1:
+			return fmt.Errorf("chain does not continue from anchor at seq %d", v.Anchor.Seq)
+		case v.Anchor != nil && pos.PrevHash != v.Anchor.Hash:
+			return fmt.Errorf("previous hash %s does not match anchor %s", pos.PrevHash, v.Anchor.Hash)
+		case v.Anchor == nil && pos.Seq != 1:
+			return errors.New("chain does not start at seq 1, anchor it to a published head")
+		case v.Anchor == nil && pos.PrevHash != Genesis:
+			return errors.New("first record does not start from genesis")
+		}
+		return nil
+	}
+	if pos.Seq != v.Last+1 {
+		return fmt.Errorf("expected seq %d", v.Last+1)
+	}
+	if pos.PrevHash != v.Hash {
+		return fmt.Errorf("previous hash %s does not match %s", pos.PrevHash, v.Hash)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/internal"
+)
+
+// Sink appends audit lines to durable storage
+type Sink interface {
+	// Write appends a single line, without trailing newline
+	Write(line []byte) error
+	// Last returns the last line written, for the chain to continue from, or nil if nothing has been written
+	Last() ([]byte, error)
+	// Close flushes and releases the sink
+	Close() error
+}
+
+// SinkArgs composes all arguments required to build a sink
+type SinkArgs struct {
+	Config configs.AuditConf
+}
+
+type sinkFactory = internal.AbstractFactory[SinkArgs, Sink]
+
+var (
+	once    sync.Once
+	factory *sinkFactory
+)
+
+// SinkFactory returns a single instance to the sink factory
+func SinkFactory() *sinkFactory {
+	// Make the factory a singleton
+	once.Do(func() {
+		factory = internal.NewAbstractFactory[SinkArgs, Sink]()
+	})
+
+	return factory
+}
+
+// GetSink builds the sink selected by the "sink" of the configuration
+func GetSink(args SinkArgs) (Sink, error) {
+	val, found := args.Config["sink"]
+	if !found {
+		return nil, fmt.Errorf("required audit field \"sink\" not found")
+	}
+	name, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("audit field \"sink\" has unknown type %v", val)
+	}
+	sink, err := SinkFactory().Build(name, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build audit sink: %w", err)
+	}
+	return sink, nil
+}
@@ -28,3 +28,9 @@ func GetName(c configs.PamConf) (string, error) {
 	}
 	return name, nil
 }
+
+// GetHTTPClientProfile returns the configured "http_client_profile", or empty string for the default profile
+func GetHTTPClientProfile(c configs.PamConf) string {
+	profile, _ := c["http_client_profile"].(string)
+	return profile
+}
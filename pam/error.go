@@ -87,10 +87,11 @@ var pamToValkError = map[ErrorCode]ValkErrorCode{
 	PAMERRGAMENOTFOUND:          ValkErrOpGameNotFound,
 	PAMERRMISSINGPROVIDER:       ValkErrOpMissingProvider,
 	PAMERRNEGATIVESTAKE:         ValkErrOpNegativeStake,
-	PAMERRPLAYERNOTFOUND:        ValkErrOpSessionNotFound,
+	PAMERRPLAYERNOTFOUND:        ValkErrOpUserNotFound,
 	PAMERRSESSIONEXPIRED:        ValkErrOpSessionExpired,
 	PAMERRSESSIONNOTFOUND:       ValkErrOpSessionNotFound,
 	PAMERRTRANSALREADYCANCELLED: ValkErrOpCancelExists,
+	PAMERRTRANSALREADYSETTLED:   ValkErrAlreadySettled,
 	PAMERRTRANSCURRENCY:         ValkErrOpTransCurrency,
 	PAMERRTRANSNOTFOUND:         ValkErrOpTransNotFound,
 	PAMERRACCNOTFOUND:           ValkErrOpAccountNotFound,
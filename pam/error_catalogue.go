@@ -0,0 +1,95 @@
+package pam
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ErrorInfo describes a ValkErrorCode
+type ErrorInfo struct {
+	Code        ValkErrorCode `json:"code"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	// Retryable if the same request may succeed when retried
+	Retryable bool `json:"retryable"`
+	// HTTPStatus closest to the meaning of the error
+	HTTPStatus int `json:"httpStatus"`
+}
+
+// errorCatalogue every ValkErrorCode, in order of code
+var errorCatalogue = []ErrorInfo{
+	{ValkErrUndefined, "ValkErrUndefined", "Undefined error", false, http.StatusInternalServerError},
+	{ValkErrAPISession, "ValkErrAPISession", "Invalid API session of the provider", false, http.StatusUnauthorized},
+	{ValkErrAuth, "ValkErrAuth", "Authentication failed", false, http.StatusUnauthorized},
+	{ValkErrGetBalance, "ValkErrGetBalance", "Failed to get the balance", true, http.StatusInternalServerError},
+	{ValkErrStakeValue, "ValkErrStakeValue", "Invalid stake amount", false, http.StatusBadRequest},
+	{ValkErrPromoValue, "ValkErrPromoValue", "Invalid promo amount", false, http.StatusBadRequest},
+	{ValkErrWithdraw, "ValkErrWithdraw", "Failed to withdraw", false, http.StatusInternalServerError},
+	{ValkErrWithdrawCurrency, "ValkErrWithdrawCurrency", "Currency of the withdrawal not supported", false, http.StatusBadRequest},
+	{ValkErrInterpretBalance, "ValkErrInterpretBalance", "Failed to interpret the balance", false, http.StatusInternalServerError},
+	{ValkErrPayoutValue, "ValkErrPayoutValue", "Invalid payout amount", false, http.StatusBadRequest},
+	{ValkErrPayoutPromoValue, "ValkErrPayoutPromoValue", "Invalid promo payout amount", false, http.StatusBadRequest},
+	{ValkErrDeposit, "ValkErrDeposit", "Failed to deposit", false, http.StatusInternalServerError},
+	{ValkErrRefundValue, "ValkErrRefundValue", "Invalid refund amount", false, http.StatusBadRequest},
+	{ValkErrRefundPromoValue, "ValkErrRefundPromoValue", "Invalid promo refund amount", false, http.StatusBadRequest},
+	{ValkErrCancel, "ValkErrCancel", "Failed to cancel", false, http.StatusInternalServerError},
+	{ValkErrPayoutNegativeStake, "ValkErrPayoutNegativeStake", "Payout of a negative stake", false, http.StatusBadRequest},
+	{ValkErrPayoutZero, "ValkErrPayoutZero", "Payout of zero", false, http.StatusBadRequest},
+	{ValkErrOpUserNotFound, "ValkErrOpUserNotFound", "Player not found by the PAM", false, http.StatusNotFound},
+	{ValkErrOpGameNotFound, "ValkErrOpGameNotFound", "Game not found by the PAM", false, http.StatusNotFound},
+	{ValkErrOpTransNotFound, "ValkErrOpTransNotFound", "Transaction not found by the PAM", false, http.StatusNotFound},
+	{ValkErrOpCashOverdraft, "ValkErrOpCashOverdraft", "Insufficient cash funds", false, http.StatusPaymentRequired},
+	{ValkErrOpBonusOverdraft, "ValkErrOpBonusOverdraft", "Insufficient bonus funds", false, http.StatusPaymentRequired},
+	{ValkErrOpSessionNotFound, "ValkErrOpSessionNotFound", "Session not found by the PAM", false, http.StatusUnauthorized},
+	{ValkErrOpSessionExpired, "ValkErrOpSessionExpired", "Session expired", false, http.StatusUnauthorized},
+	{ValkErrOpMissingProvider, "ValkErrOpMissingProvider", "Provider not known by the PAM", false, http.StatusBadRequest},
+	{ValkErrOpTransCurrency, "ValkErrOpTransCurrency", "Currency of the transaction not supported by the PAM", false, http.StatusBadRequest},
+	{ValkErrOpNegativeStake, "ValkErrOpNegativeStake", "Negative stake", false, http.StatusBadRequest},
+	{ValkErrOpZeroStake, "ValkErrOpZeroStake", "Zero stake", false, http.StatusBadRequest},
+	{ValkErrOpRoundExists, "ValkErrOpRoundExists", "Game round already exists", false, http.StatusConflict},
+	{ValkErrOpCancelNotFound, "ValkErrOpCancelNotFound", "Transaction to cancel not found", false, http.StatusNotFound},
+	{ValkErrOpCancelExists, "ValkErrOpCancelExists", "Transaction already cancelled", false, http.StatusConflict},
+	{ValkErrOpCancelNonWithdraw, "ValkErrOpCancelNonWithdraw", "Cancelled transaction is not a withdrawal", false, http.StatusBadRequest},
+	{ValkErrOpBetNotAllowed, "ValkErrOpBetNotAllowed", "Player not allowed to bet", false, http.StatusForbidden},
+	{ValkErrAlreadySettled, "ValkErrAlreadySettled", "Transaction or game round already settled", false, http.StatusConflict},
+	{ValkErrBetNotFound, "ValkErrBetNotFound", "Bet not found", false, http.StatusNotFound},
+	{ValkErrOpAccountNotFound, "ValkErrOpAccountNotFound", "Account not found by the PAM", false, http.StatusNotFound},
+	{ValkErrOpAPIToken, "ValkErrOpAPIToken", "Invalid API token of the PAM", false, http.StatusUnauthorized},
+	{ValkErrReqInput, "ValkErrReqInput", "Invalid request", false, http.StatusBadRequest},
+	{ValkErrOpRoundNotFound, "ValkErrOpRoundNotFound", "Game round not found by the PAM", false, http.StatusNotFound},
+	{ValkErrDuplicateTrans, "ValkErrDuplicateTrans", "Duplicate transaction", false, http.StatusConflict},
+	{ValkErrOpPromoOverdraft, "ValkErrOpPromoOverdraft", "Insufficient promo funds", false, http.StatusPaymentRequired},
+	{ValkErrTimeout, "ValkErrTimeout", "Timeout calling the PAM", true, http.StatusGatewayTimeout},
+}
+
+// ErrorCatalogue returns the description of every ValkErrorCode
+func ErrorCatalogue() []ErrorInfo {
+	return append([]ErrorInfo(nil), errorCatalogue...)
+}
+
+// LookupError returns the description of code
+func LookupError(code ValkErrorCode) (ErrorInfo, bool) {
+	if code < 0 || int(code) >= len(errorCatalogue) {
+		return ErrorInfo{}, false
+	}
+	return errorCatalogue[code], true
+}
+
+func (c ValkErrorCode) String() string {
+	if info, found := LookupError(c); found {
+		return info.Name
+	}
+	return fmt.Sprintf("ValkErrorCode(%d)", int(c))
+}
+
+// MissingErrorCodes returns the codes of the catalogue that are not mapped, used to check that providers map
+// every ValkErrorCode to a native error
+func MissingErrorCodes[T any](mapping map[ValkErrorCode]T) []ValkErrorCode {
+	var missing []ValkErrorCode
+	for _, info := range errorCatalogue {
+		if _, found := mapping[info.Code]; !found {
+			missing = append(missing, info.Code)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,65 @@
+package pam
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCatalogue(t *testing.T) {
+	catalogue := ErrorCatalogue()
+	require.Len(t, catalogue, int(ValkErrTimeout)+1, "every ValkErrorCode is described")
+	for i, info := range catalogue {
+		assert.Equal(t, ValkErrorCode(i), info.Code, "catalogue is in order of code")
+		assert.NotEmpty(t, info.Name)
+		assert.NotEmpty(t, info.Description)
+		assert.NotZero(t, info.HTTPStatus)
+	}
+
+	assert.Equal(t, "ValkErrAlreadySettled", ValkErrAlreadySettled.String())
+	assert.Equal(t, "ValkErrorCode(100)", ValkErrorCode(100).String())
+}
+
+func TestMissingErrorCodes(t *testing.T) {
+	mapping := map[ValkErrorCode]string{}
+	for _, info := range ErrorCatalogue() {
+		mapping[info.Code] = info.Name
+	}
+	assert.Empty(t, MissingErrorCodes(mapping))
+
+	delete(mapping, ValkErrTimeout)
+	delete(mapping, ValkErrAuth)
+	assert.Equal(t, []ValkErrorCode{ValkErrAuth, ValkErrTimeout}, MissingErrorCodes(mapping))
+}
+
+func Test_pamToValkError(t *testing.T) {
+	codes := []ErrorCode{
+		PAMERRACCNOTFOUND, PAMERRAPITOKEN, PAMERRBETNOTALLOWED, PAMERRBONUSOVERDRAFT, PAMERRCANCELNONWITHDRAW,
+		PAMERRCANCELNOTFOUND, PAMERRCASHOVERDRAFT, PAMERRDUPLICATETRANS, PAMERRGAMENOTFOUND, PAMERRMISSINGPROVIDER,
+		PAMERRNEGATIVESTAKE, PAMERRPLAYERNOTFOUND, PAMERRPROMOOVERDRAFT, PAMERRROUNDNOTFOUND, PAMERRSESSIONEXPIRED,
+		PAMERRSESSIONNOTFOUND, PAMERRTIMEOUT, PAMERRTRANSALREADYCANCELLED, PAMERRTRANSALREADYSETTLED,
+		PAMERRTRANSCURRENCY, PAMERRTRANSNOTFOUND, PAMERRUNDEFINED,
+	}
+	for _, code := range codes {
+		_, found := pamToValkError[code]
+		assert.True(t, found, "%s is mapped", code)
+	}
+
+	tests := []struct {
+		code ErrorCode
+		want ValkErrorCode
+	}{
+		{PAMERRPLAYERNOTFOUND, ValkErrOpUserNotFound},
+		{PAMERRSESSIONNOTFOUND, ValkErrOpSessionNotFound},
+		{PAMERRTRANSALREADYSETTLED, ValkErrAlreadySettled},
+	}
+	for _, test := range tests {
+		t.Run(string(test.code), func(t *testing.T) {
+			var valkErr ValkyrieError
+			require.True(t, errors.As(ToValkyrieError(&PamError{Code: test.code}), &valkErr))
+			assert.Equal(t, test.want, valkErr.ValkErrorCode)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package genericpam
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -8,6 +9,7 @@ import (
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
 	"github.com/valkyrie-fnd/valkyrie/internal/pipeline"
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
@@ -54,10 +56,14 @@ func Create(cfg configs.PamConf, client valkhttp.HTTPClient) (*GenericPam, error
 	}, nil
 }
 
-func getHeaders(apiKey, sessionToken, correlationID string) map[string]string {
+// getHeaders returns the headers of PAM requests, with the correlation ID of the request, or of ctx if not set
+func getHeaders(ctx context.Context, apiKey, sessionToken, correlationID string) map[string]string {
 	if correlationID == "" {
-		correlationID = "-"
-		log.Trace().Msg("no correlationID set, defaulting to '-'")
+		correlationID = correlation.ID(ctx)
+	}
+	if correlationID == "" {
+		correlationID = correlation.NewID()
+		log.Trace().Str("correlationId", correlationID).Msg("no correlationID set, generated one")
 	}
 	return map[string]string{
 		"Authorization":    fmt.Sprintf("Bearer %s", apiKey),
@@ -73,22 +79,21 @@ func (c *GenericPam) RefreshSession(rm pam.RefreshSessionRequestMapper) (*pam.Se
 	}
 	url := fmt.Sprintf("%s/players/session", c.baseURL)
 	var resp pam.SessionResponse
-	headers := getHeaders(c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
+	headers := getHeaders(ctx, c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
 	req := &valkhttp.HTTPRequest{
 		URL:     url,
 		Headers: headers,
 		Query:   map[string]string{"provider": r.Params.Provider},
 	}
 
-	err = Pipeline.Execute(ctx, &r,
-		func(pc pipeline.PipelineContext[any]) error {
-			return c.rest.Put(pc.Context(), &valkhttp.JSONParser, req, &resp)
+	return pipeline.Evaluate(ctx, Pipeline, any(&r),
+		func(pc pipeline.PipelineContext[any]) (*pam.Session, error) {
+			err := c.rest.Put(pc.Context(), &valkhttp.JSONParser, req, &resp)
+			if err = handleErrors(resp.Error, err, resp.Session); err != nil {
+				return nil, err
+			}
+			return resp.Session, nil
 		})
-	if err = handleErrors(resp.Error, err, resp.Session); err != nil {
-		return nil, err
-	}
-
-	return resp.Session, nil
 }
 
 func (c *GenericPam) GetBalance(rm pam.GetBalanceRequestMapper) (*pam.Balance, error) {
@@ -99,22 +104,21 @@ func (c *GenericPam) GetBalance(rm pam.GetBalanceRequestMapper) (*pam.Balance, e
 
 	url := fmt.Sprintf("%s/players/%s/balance", c.baseURL, r.PlayerID)
 	resp := pam.BalanceResponse{}
-	headers := getHeaders(c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
+	headers := getHeaders(ctx, c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
 	req := &valkhttp.HTTPRequest{
 		URL:     url,
 		Headers: headers,
 		Query:   map[string]string{"provider": r.Params.Provider},
 	}
 
-	err = Pipeline.Execute(ctx, &r,
-		func(pc pipeline.PipelineContext[any]) error {
-			return c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+	return pipeline.Evaluate(ctx, Pipeline, any(&r),
+		func(pc pipeline.PipelineContext[any]) (*pam.Balance, error) {
+			err := c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+			if err = handleErrors(resp.Error, err, resp.Balance); err != nil {
+				return nil, err
+			}
+			return resp.Balance, nil
 		})
-	if err = handleErrors(resp.Error, err, resp.Balance); err != nil {
-		return nil, err
-	}
-
-	return resp.Balance, nil
 }
 
 func (c *GenericPam) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam.Transaction, error) {
@@ -125,7 +129,7 @@ func (c *GenericPam) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam
 
 	url := fmt.Sprintf("%s/players/%s/transactions", c.baseURL, r.PlayerID)
 	var resp pam.GetTransactionsResponse
-	headers := getHeaders(c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
+	headers := getHeaders(ctx, c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
 	query := map[string]string{"provider": r.Params.Provider}
 	if r.Params.ProviderTransactionId != nil {
 		query["providerTransactionId"] = *r.Params.ProviderTransactionId
@@ -133,24 +137,26 @@ func (c *GenericPam) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam
 	if r.Params.ProviderBetRef != nil {
 		query["providerBetRef"] = *r.Params.ProviderBetRef
 	}
+	if r.Params.ProviderRoundId != nil {
+		query["providerRoundId"] = *r.Params.ProviderRoundId
+	}
 	req := &valkhttp.HTTPRequest{
 		URL:     url,
 		Headers: headers,
 		Query:   query,
 	}
 
-	err = Pipeline.Execute(ctx, &r,
-		func(pc pipeline.PipelineContext[any]) error {
-			return c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+	return pipeline.Evaluate(ctx, Pipeline, any(&r),
+		func(pc pipeline.PipelineContext[any]) ([]pam.Transaction, error) {
+			err := c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+			if err = handleErrors(resp.Error, err, resp.Transactions); err != nil {
+				return nil, err
+			}
+			if len(*resp.Transactions) == 0 {
+				return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrOpTransNotFound, ErrMsg: "No transactions"}
+			}
+			return *resp.Transactions, nil
 		})
-	if err = handleErrors(resp.Error, err, resp.Transactions); err != nil {
-		return nil, err
-	}
-	if len(*resp.Transactions) == 0 {
-		return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrOpTransNotFound, ErrMsg: "No transactions"}
-	}
-
-	return *resp.Transactions, nil
 }
 
 func (c *GenericPam) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
@@ -161,7 +167,7 @@ func (c *GenericPam) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.Tr
 
 	url := fmt.Sprintf("%s/players/%s/transactions", c.baseURL, r.PlayerID)
 	var resp pam.AddTransactionResponse
-	headers := getHeaders(c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
+	headers := getHeaders(ctx, c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
 	req := &valkhttp.HTTPRequest{
 		URL:     url,
 		Headers: headers,
@@ -169,19 +175,12 @@ func (c *GenericPam) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.Tr
 		Body:    &r.Body,
 	}
 
-	err = Pipeline.Execute(ctx, r,
-		func(pc pipeline.PipelineContext[any]) error {
-			return c.rest.Post(pc.Context(), &valkhttp.JSONParser, req, &resp)
-		})
-	if err = handleErrors(resp.Error, err, resp.TransactionResult); err != nil {
-		if resp.TransactionResult != nil {
+	return pipeline.Evaluate(ctx, Pipeline, any(r),
+		func(pc pipeline.PipelineContext[any]) (*pam.TransactionResult, error) {
+			err := c.rest.Post(pc.Context(), &valkhttp.JSONParser, req, &resp)
 			// Special case, balance may still be included even if add transaction resulted in error.
-			return resp.TransactionResult, err
-		}
-		return nil, err
-	}
-
-	return resp.TransactionResult, nil
+			return resp.TransactionResult, handleErrors(resp.Error, err, resp.TransactionResult)
+		})
 }
 
 func (c *GenericPam) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRound, error) {
@@ -192,22 +191,21 @@ func (c *GenericPam) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRo
 
 	url := fmt.Sprintf("%s/players/%s/gamerounds/%s", c.baseURL, r.PlayerID, r.ProviderRoundID)
 	var resp pam.GameRoundResponse
-	headers := getHeaders(c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
+	headers := getHeaders(ctx, c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
 	req := &valkhttp.HTTPRequest{
 		URL:     url,
 		Headers: headers,
 		Query:   map[string]string{"provider": r.Params.Provider},
 	}
 
-	err = Pipeline.Execute(ctx, &r,
-		func(pc pipeline.PipelineContext[any]) error {
-			return c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+	return pipeline.Evaluate(ctx, Pipeline, any(&r),
+		func(pc pipeline.PipelineContext[any]) (*pam.GameRound, error) {
+			err := c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+			if err = handleErrors(resp.Error, err, resp.Gameround); err != nil {
+				return nil, err
+			}
+			return resp.Gameround, nil
 		})
-	if err = handleErrors(resp.Error, err, resp.Gameround); err != nil {
-		return nil, err
-	}
-
-	return resp.Gameround, nil
 }
 
 func (c *GenericPam) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, error) {
@@ -217,21 +215,21 @@ func (c *GenericPam) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, e
 	}
 	url := fmt.Sprintf("%s/players/session", c.baseURL)
 	var resp pam.SessionResponse
-	headers := getHeaders(c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
+	headers := getHeaders(ctx, c.apiKey, r.Params.XPlayerToken, r.Params.XCorrelationID)
 	req := &valkhttp.HTTPRequest{
 		URL:     url,
 		Headers: headers,
 		Query:   map[string]string{"provider": r.Params.Provider},
 	}
 
-	err = Pipeline.Execute(ctx, &r,
-		func(pc pipeline.PipelineContext[any]) error {
-			return c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+	return pipeline.Evaluate(ctx, Pipeline, any(&r),
+		func(pc pipeline.PipelineContext[any]) (*pam.Session, error) {
+			err := c.rest.Get(pc.Context(), &valkhttp.JSONParser, req, &resp)
+			if err = handleErrors(resp.Error, err, resp.Session); err != nil {
+				return nil, err
+			}
+			return resp.Session, nil
 		})
-	if err = handleErrors(resp.Error, err, resp.Session); err != nil {
-		return nil, err
-	}
-	return resp.Session, nil
 }
 
 func (c *GenericPam) GetTransactionSupplier() pam.TransactionSupplier {
@@ -5,7 +5,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/valkyrie-fnd/valkyrie/internal/pipeline"
 	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
+	"github.com/valkyrie-fnd/valkyrie/ops/correlation"
 
 	"github.com/valkyrie-fnd/valkyrie/pam"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
@@ -332,6 +334,30 @@ func TestGenericPam_GetBalance(t *testing.T) {
 	}
 }
 
+func TestGenericPam_GetBalance_AnsweredByPipeline(t *testing.T) {
+	defaultPipeline := Pipeline
+	t.Cleanup(func() { Pipeline = defaultPipeline })
+	Pipeline = pipeline.NewPipeline[any]()
+
+	cached := &pam.Balance{CashAmount: pam.ZeroAmount}
+	Pipeline.RegisterWith(pipeline.Options[any]{When: pipeline.PayloadIs[*pam.GetBalanceRequest]},
+		func(pc pipeline.PipelineContext[any]) error {
+			pc.SetResult(cached)
+			return nil
+		})
+	c := &GenericPam{rest: mockClient{GetJSONFunc: func(context.Context, *valkhttp.HTTPRequest, any) error {
+		assert.Fail(t, "pam should not be called")
+		return nil
+	}}}
+
+	got, err := c.GetBalance(func() (context.Context, pam.GetBalanceRequest, error) {
+		return context.TODO(), pam.GetBalanceRequest{PlayerID: "foo"}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Same(t, cached, got)
+}
+
 func TestGenericPam_GetTransactions(t *testing.T) {
 	var providerTransactionID = "123"
 	var providerBetRef = "321"
@@ -351,6 +377,15 @@ func TestGenericPam_GetTransactions(t *testing.T) {
 			ProviderTransactionId: &providerTransactionID,
 		},
 	}
+	var providerRoundID = "round"
+	var expectedRequestRoundID = pam.GetTransactionsRequest{
+		PlayerID: "1",
+		Params: pam.GetTransactionsParams{
+			Provider:        "prov",
+			XPlayerToken:    "token",
+			ProviderRoundId: &providerRoundID,
+		},
+	}
 	var expectedErrorResponse = pam.GetTransactionsResponse{
 		Error: &pam.PamError{
 			Code:    pam.PAMERRTRANSNOTFOUND,
@@ -439,6 +474,26 @@ func TestGenericPam_GetTransactions(t *testing.T) {
 			},
 			want: *expectedResponse.Transactions,
 		},
+		{
+			name: "successful get transaction by providerRoundId",
+			fields: fields{
+				"base",
+				"key",
+				mockClient{GetJSONFunc: func(ctx context.Context, req *valkhttp.HTTPRequest, resp any) error {
+					assert.Equal(t, "base/players/1/transactions", req.URL)
+					assert.Equal(t, *expectedRequestRoundID.Params.ProviderRoundId, req.Query["providerRoundId"])
+
+					reflect.ValueOf(resp).
+						Elem().
+						Set(reflect.ValueOf(expectedResponse))
+					return nil
+				}},
+			},
+			mapper: func() (context.Context, pam.GetTransactionsRequest, error) {
+				return context.Background(), expectedRequestRoundID, nil
+			},
+			want: *expectedResponse.Transactions,
+		},
 		{
 			name: "error get transaction empty",
 			fields: fields{
@@ -1026,3 +1081,12 @@ func TestGenericPam_GetSession(t *testing.T) {
 		})
 	}
 }
+
+func Test_getHeaders(t *testing.T) {
+	ctx := correlation.WithID(context.Background(), "corr")
+
+	assert.Equal(t, "native", getHeaders(ctx, "key", "token", "native")["X-Correlation-ID"])
+	assert.Equal(t, "corr", getHeaders(ctx, "key", "token", "")["X-Correlation-ID"])
+	generated := getHeaders(context.Background(), "key", "token", "")["X-Correlation-ID"]
+	assert.True(t, correlation.Valid(generated), "correlation id is generated instead of '-'")
+}
@@ -0,0 +1,31 @@
+package jackpot
+
+import (
+	"context"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// recordingClient records jackpots of successful transactions in a Ledger
+type recordingClient struct {
+	pam.PamClient
+	ledger *Ledger
+}
+
+// NewRecordingClient wraps client, recording jackpots of all successful transactions in ledger
+func NewRecordingClient(client pam.PamClient, ledger *Ledger) pam.PamClient {
+	return &recordingClient{PamClient: client, ledger: ledger}
+}
+
+func (c *recordingClient) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	var req *pam.AddTransactionRequest
+	res, err := c.PamClient.AddTransaction(func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		ctx, mapped, mErr := rm(r)
+		req = mapped
+		return ctx, mapped, mErr
+	})
+	if err == nil && req != nil {
+		c.ledger.Record(req.Body)
+	}
+	return res, err
+}
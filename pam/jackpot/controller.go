@@ -0,0 +1,46 @@
+package jackpot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContributionsResponse jackpot contributions and wins for the requested period
+type ContributionsResponse struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Summaries []Summary `json:"summaries"`
+}
+
+// ContributionsEndpoint returns jackpot contributions and wins per game and day, for the period given
+// by the "from" and "to" query parameters (YYYY-MM-DD, inclusive, defaults to today). The result can
+// be filtered using the "provider" and "gameId" query parameters.
+func ContributionsEndpoint(ledger *Ledger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		today := time.Now().UTC().Format(dateLayout)
+		from, err := time.Parse(dateLayout, c.Query("from", today))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fmt.Sprintf("invalid from: %s", err.Error()))
+		}
+		to, err := time.Parse(dateLayout, c.Query("to", today))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fmt.Sprintf("invalid to: %s", err.Error()))
+		}
+		if to.Before(from) {
+			return c.Status(fiber.StatusBadRequest).JSON("to must not be before from")
+		}
+
+		return c.JSON(ContributionsResponse{
+			From: from.Format(dateLayout),
+			To:   to.Format(dateLayout),
+			Summaries: ledger.Report(Filter{
+				From:     from,
+				To:       to,
+				Provider: c.Query("provider"),
+				GameID:   c.Query("gameId"),
+			}),
+		})
+	}
+}
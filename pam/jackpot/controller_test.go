@@ -0,0 +1,44 @@
+package jackpot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func TestContributionsEndpoint(t *testing.T) {
+	ledger := NewLedger(0)
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", day, 0.5))
+	app := fiber.New()
+	app.Get("/contributions", ContributionsEndpoint(ledger))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		{"period with contributions", "from=2023-03-01&to=2023-03-01", http.StatusOK, 1},
+		{"filtered on game", "from=2023-03-01&to=2023-03-01&gameId=game2", http.StatusOK, 0},
+		{"invalid date", "from=2023-03&to=2023-03-01", http.StatusBadRequest, 0},
+		{"to before from", "from=2023-03-02&to=2023-03-01", http.StatusBadRequest, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/contributions?"+test.query, nil))
+			assert.NoError(tt, err)
+			assert.Equal(tt, test.wantStatus, resp.StatusCode)
+			if test.wantStatus == http.StatusOK {
+				var body ContributionsResponse
+				assert.NoError(tt, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Len(tt, body.Summaries, test.wantCount)
+			}
+		})
+	}
+}
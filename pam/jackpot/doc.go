@@ -0,0 +1,5 @@
+// Package jackpot keeps track of jackpot contributions and jackpot wins booked through
+// the PAM, reported per provider, game and day, when enabled. It is a view of the transactions
+// booked through a single instance, optionally persisted to a file, and not a record to reconcile
+// jackpot liabilities from, which is what the PAM is.
+package jackpot
@@ -0,0 +1,215 @@
+package jackpot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// entry a change of the ledger, written as a JSON line to the journal of the ledger file
+type entry struct {
+	Seq             uint64              `json:"seq"`
+	Recorded        time.Time           `json:"recorded"`
+	Provider        string              `json:"provider"`
+	TransactionID   string              `json:"transactionId"`
+	TransactionType pam.TransactionType `json:"transactionType"`
+	StakeRef        string              `json:"stakeRef"`
+	GameID          string              `json:"gameId,omitempty"`
+	Currency        string              `json:"currency,omitempty"`
+	Date            string              `json:"date,omitempty"`
+	Amount          pam.Amount          `json:"amount"`
+	Count           int                 `json:"count,omitempty"`
+}
+
+// snapshot the state of the ledger, including the changes of the journal up to Seq
+type snapshot struct {
+	Seq       uint64             `json:"seq"`
+	Summaries []Summary          `json:"summaries"`
+	Stakes    []stakeSnapshot    `json:"stakes"`
+	Recorded  []recordedSnapshot `json:"recorded"`
+}
+
+type stakeSnapshot struct {
+	Provider string     `json:"provider"`
+	Ref      string     `json:"ref"`
+	GameID   string     `json:"gameId"`
+	Currency string     `json:"currency"`
+	Date     string     `json:"date"`
+	Amount   pam.Amount `json:"amount"`
+	Count    int        `json:"count"`
+	Recorded time.Time  `json:"recorded"`
+}
+
+type recordedSnapshot struct {
+	Provider        string              `json:"provider"`
+	TransactionID   string              `json:"transactionId"`
+	TransactionType pam.TransactionType `json:"transactionType"`
+	Recorded        time.Time           `json:"recorded"`
+}
+
+// ledgerFile persists a ledger as a journal of the entries recorded since the latest snapshot, which is kept next
+// to it with a ".snapshot" suffix. The journal is not synced for every entry, so the latest entries may be lost if
+// the host crashes.
+type ledgerFile struct {
+	journal      *os.File
+	snapshotPath string
+}
+
+// openLedgerFile restores l from the snapshot and journal at path, and keeps persisting it there
+func openLedgerFile(path string, l *Ledger) (*ledgerFile, error) {
+	f := &ledgerFile{snapshotPath: path + ".snapshot"}
+	if err := f.restore(path, l); err != nil {
+		return nil, err
+	}
+	journal, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	f.journal = journal
+
+	// compact the journal into a new snapshot
+	if err = f.snapshot(l); err != nil {
+		_ = journal.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *ledgerFile) restore(path string, l *Ledger) error {
+	data, err := os.ReadFile(f.snapshotPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		var s snapshot
+		if err = json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid snapshot %s: %w", f.snapshotPath, err)
+		}
+		l.restore(s)
+	}
+
+	journal, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer journal.Close()
+
+	reader := bufio.NewReader(journal)
+	for line := 1; ; line++ {
+		data, rErr := reader.ReadBytes('\n')
+		if len(data) > 0 {
+			var e entry
+			if err = json.Unmarshal(data, &e); err != nil {
+				if rErr == io.EOF {
+					// the last entry was not completely written
+					log.Warn().Err(err).Str("file", path).Msg("Ignoring incomplete last entry of jackpot ledger")
+					return nil
+				}
+				return fmt.Errorf("invalid entry on line %d of %s: %w", line, path, err)
+			}
+			// entries included in the snapshot, if the journal was not truncated after it
+			if e.Seq > l.seq {
+				l.apply(e)
+				l.seq = e.Seq
+			}
+		}
+		if rErr == io.EOF {
+			return nil
+		} else if rErr != nil {
+			return rErr
+		}
+	}
+}
+
+func (f *ledgerFile) append(e entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.journal.Write(append(data, '\n'))
+	return err
+}
+
+// snapshot writes the state of l to the snapshot file, replacing the previous one, and truncates the journal
+func (f *ledgerFile) snapshot(l *Ledger) error {
+	data, err := json.Marshal(l.snapshot())
+	if err != nil {
+		return err
+	}
+	tmp := f.snapshotPath + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o640); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, f.snapshotPath); err != nil {
+		return err
+	}
+	return f.journal.Truncate(0)
+}
+
+func (f *ledgerFile) close() error {
+	return f.journal.Close()
+}
+
+func (l *Ledger) snapshot() snapshot {
+	s := snapshot{
+		Seq:       l.seq,
+		Summaries: make([]Summary, 0, len(l.summaries)),
+		Stakes:    make([]stakeSnapshot, 0, len(l.stakes)),
+		Recorded:  make([]recordedSnapshot, 0, len(l.recorded)),
+	}
+	for _, summary := range l.summaries {
+		s.Summaries = append(s.Summaries, *summary)
+	}
+	for sk, c := range l.stakes {
+		s.Stakes = append(s.Stakes, stakeSnapshot{
+			Provider: sk.provider,
+			Ref:      sk.ref,
+			GameID:   c.summary.gameID,
+			Currency: c.summary.currency,
+			Date:     c.summary.date,
+			Amount:   c.amount,
+			Count:    c.count,
+			Recorded: c.recorded,
+		})
+	}
+	for tk, recorded := range l.recorded {
+		s.Recorded = append(s.Recorded, recordedSnapshot{
+			Provider:        tk.provider,
+			TransactionID:   tk.transactionID,
+			TransactionType: tk.transactionType,
+			Recorded:        recorded,
+		})
+	}
+	return s
+}
+
+func (l *Ledger) restore(s snapshot) {
+	l.seq = s.Seq
+	for i := range s.Summaries {
+		summary := s.Summaries[i]
+		l.summaries[summaryKey{provider: summary.Provider, gameID: summary.GameID, currency: summary.Currency, date: summary.Date}] = &summary
+	}
+	for _, c := range s.Stakes {
+		l.stakes[stakeKey{provider: c.Provider, ref: c.Ref}] = contribution{
+			summary:  summaryKey{provider: c.Provider, gameID: c.GameID, currency: c.Currency, date: c.Date},
+			amount:   c.Amount,
+			count:    c.Count,
+			recorded: c.Recorded,
+		}
+	}
+	for _, r := range s.Recorded {
+		l.recorded[transactionKey{provider: r.Provider, transactionID: r.TransactionID, transactionType: r.TransactionType}] = r.Recorded
+	}
+}
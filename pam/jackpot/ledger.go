@@ -0,0 +1,305 @@
+package jackpot
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// dedupWindow how long recorded transactions are remembered to ignore resent transactions
+	dedupWindow = 24 * time.Hour
+	// cancelWindow how long contributions of stakes are remembered to reverse them when the stake is cancelled
+	cancelWindow = 7 * 24 * time.Hour
+	// defaultRetention how long summaries are kept unless configured
+	defaultRetention = 90 * 24 * time.Hour
+)
+
+// Summary jackpot contributions and wins for a provider game, currency and day
+type Summary struct {
+	Provider          string     `json:"provider"`
+	GameID            string     `json:"gameId"`
+	Currency          string     `json:"currency"`
+	Date              string     `json:"date"`
+	Contributions     pam.Amount `json:"contributions"`
+	ContributionCount int        `json:"contributionCount"`
+	Wins              pam.Amount `json:"wins"`
+	WinCount          int        `json:"winCount"`
+}
+
+// Filter used when reporting summaries. Empty Provider or GameID matches all.
+type Filter struct {
+	From     time.Time
+	To       time.Time
+	Provider string
+	GameID   string
+}
+
+type summaryKey struct {
+	provider, gameID, currency, date string
+}
+
+type transactionKey struct {
+	provider, transactionID string
+	transactionType         pam.TransactionType
+}
+
+// stakeKey identifies a stake the way cancels refer to it, by bet reference or else by transaction id
+type stakeKey struct {
+	provider, ref string
+}
+
+// contribution of a stake, reversed if the stake is cancelled
+type contribution struct {
+	summary  summaryKey
+	amount   pam.Amount
+	count    int
+	recorded time.Time
+}
+
+// Ledger ledger of jackpot contributions and wins. Jackpots on withdraw transactions are regarded as
+// contributions and jackpots on deposit transactions as wins. Cancels reverse the contributions of the stake they
+// cancel, when cancelled within cancelWindow.
+//
+// The ledger covers the transactions booked through this instance, so with several replicas each reports its own
+// share. It is kept in memory, and persisted to a file when configured. Summaries are kept for the retention
+// period. Liabilities are reconciled from the PAM, the ledger is an operational view of it.
+type Ledger struct {
+	mu        sync.RWMutex
+	summaries map[summaryKey]*Summary
+	// recorded when transactions were recorded, forgotten after dedupWindow
+	recorded map[transactionKey]time.Time
+	// stakes contributions of stakes, forgotten after cancelWindow
+	stakes    map[stakeKey]contribution
+	retention time.Duration
+	pruned    time.Time
+	now       func() time.Time
+	// file persisting the ledger, nil if kept in memory only
+	file *ledgerFile
+	seq  uint64
+}
+
+// NewLedger creates an empty ledger kept in memory, keeping summaries for retention, or a default of 90 days
+func NewLedger(retention time.Duration) *Ledger {
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	return &Ledger{
+		summaries: map[summaryKey]*Summary{},
+		recorded:  map[transactionKey]time.Time{},
+		stakes:    map[stakeKey]contribution{},
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// NewLedgerFromConfig creates a ledger as configured, restored from its file if persisted, or returns nil if the
+// ledger is not enabled
+func NewLedgerFromConfig(config configs.JackpotsConfig) (*Ledger, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	l := NewLedger(time.Duration(config.RetentionDays) * 24 * time.Hour)
+	if config.Filename == "" {
+		return l, nil
+	}
+	file, err := openLedgerFile(config.Filename, l)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open jackpot ledger: %w", err)
+	}
+	l.file = file
+	return l, nil
+}
+
+// Close persists the ledger, if persisted to a file
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.snapshot(l)
+	if cErr := l.file.close(); err == nil {
+		err = cErr
+	}
+	l.file = nil
+	return err
+}
+
+// Record adds the jackpots of a successful transaction to the ledger, or reverses the contribution of the stake a
+// cancel cancels. Transactions already recorded within dedupWindow are ignored, since providers may resend
+// transactions.
+func (l *Ledger) Record(t pam.AddTransactionJSONRequestBody) {
+	e := entry{
+		Provider:        t.Provider,
+		TransactionID:   t.ProviderTransactionId,
+		TransactionType: t.TransactionType,
+		StakeRef:        t.ProviderTransactionId,
+		Currency:        t.Currency,
+		Date:            t.TransactionDateTime.UTC().Format(dateLayout),
+		Amount:          pam.ZeroAmount,
+	}
+	if t.ProviderBetRef != nil && *t.ProviderBetRef != "" {
+		e.StakeRef = *t.ProviderBetRef
+	}
+	if t.ProviderGameId != nil {
+		e.GameID = *t.ProviderGameId
+	}
+	switch t.TransactionType {
+	case pam.WITHDRAW, pam.PROMOWITHDRAW, pam.DEPOSIT, pam.PROMODEPOSIT:
+		if t.Jackpots == nil {
+			return
+		}
+		for _, j := range *t.Jackpots {
+			if j.JackpotAmount == nil {
+				continue
+			}
+			e.Amount = e.Amount.Add(*j.JackpotAmount)
+			e.Count++
+		}
+		if e.Count == 0 {
+			return
+		}
+	case pam.CANCEL, pam.PROMOCANCEL:
+	default:
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Recorded = l.now()
+	l.prune(e.Recorded)
+	if !l.apply(e) {
+		return
+	}
+	l.seq++
+	e.Seq = l.seq
+	if l.file != nil {
+		if err := l.file.append(e); err != nil {
+			log.Warn().Err(err).Msg("Failed to persist jackpot ledger entry")
+		}
+	}
+}
+
+// apply adds e to the ledger, returning false if it changed nothing
+func (l *Ledger) apply(e entry) bool {
+	tk := transactionKey{provider: e.Provider, transactionID: e.TransactionID, transactionType: e.TransactionType}
+	if _, found := l.recorded[tk]; found {
+		return false
+	}
+	sk := stakeKey{provider: e.Provider, ref: e.StakeRef}
+
+	switch e.TransactionType {
+	case pam.CANCEL, pam.PROMOCANCEL:
+		c, found := l.stakes[sk]
+		if !found {
+			return false
+		}
+		delete(l.stakes, sk)
+		if s, found := l.summaries[c.summary]; found {
+			s.Contributions = s.Contributions.Sub(c.amount)
+			s.ContributionCount -= c.count
+		}
+	default:
+		key := summaryKey{provider: e.Provider, gameID: e.GameID, currency: e.Currency, date: e.Date}
+		s := l.summary(key)
+		if e.TransactionType == pam.DEPOSIT || e.TransactionType == pam.PROMODEPOSIT {
+			s.Wins = s.Wins.Add(e.Amount)
+			s.WinCount += e.Count
+		} else {
+			s.Contributions = s.Contributions.Add(e.Amount)
+			s.ContributionCount += e.Count
+			l.stakes[sk] = contribution{summary: key, amount: e.Amount, count: e.Count, recorded: e.Recorded}
+		}
+	}
+	l.recorded[tk] = e.Recorded
+	return true
+}
+
+func (l *Ledger) summary(key summaryKey) *Summary {
+	s, found := l.summaries[key]
+	if !found {
+		s = &Summary{
+			Provider:      key.provider,
+			GameID:        key.gameID,
+			Currency:      key.currency,
+			Date:          key.date,
+			Contributions: pam.ZeroAmount,
+			Wins:          pam.ZeroAmount,
+		}
+		l.summaries[key] = s
+	}
+	return s
+}
+
+// prune forgets transactions recorded longer than dedupWindow ago, contributions of stakes recorded longer than
+// cancelWindow ago and summaries older than the retention, at most once per hour, persisting the ledger if
+// persisted to a file
+func (l *Ledger) prune(now time.Time) {
+	if now.Sub(l.pruned) < time.Hour {
+		return
+	}
+	l.pruned = now
+	for tk, recorded := range l.recorded {
+		if now.Sub(recorded) > dedupWindow {
+			delete(l.recorded, tk)
+		}
+	}
+	for sk, c := range l.stakes {
+		if now.Sub(c.recorded) > cancelWindow {
+			delete(l.stakes, sk)
+		}
+	}
+	oldest := now.Add(-l.retention).UTC().Format(dateLayout)
+	for key := range l.summaries {
+		if key.date < oldest {
+			delete(l.summaries, key)
+		}
+	}
+	if l.file != nil {
+		if err := l.file.snapshot(l); err != nil {
+			log.Warn().Err(err).Msg("Failed to persist jackpot ledger")
+		}
+	}
+}
+
+// Report returns summaries matching the filter, ordered by date, provider, game and currency.
+// From and To are inclusive and compared on date only.
+func (l *Ledger) Report(f Filter) []Summary {
+	from, to := f.From.UTC().Format(dateLayout), f.To.UTC().Format(dateLayout)
+
+	l.mu.RLock()
+	result := make([]Summary, 0)
+	for k, s := range l.summaries {
+		if k.date < from || k.date > to ||
+			(f.Provider != "" && k.provider != f.Provider) ||
+			(f.GameID != "" && k.gameID != f.GameID) {
+			continue
+		}
+		result = append(result, *s)
+	}
+	l.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.GameID != b.GameID {
+			return a.GameID < b.GameID
+		}
+		return a.Currency < b.Currency
+	})
+	return result
+}
@@ -0,0 +1,197 @@
+package jackpot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+var day = time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func transaction(id string, tt pam.TransactionType, game string, at time.Time, amounts ...float64) pam.AddTransactionJSONRequestBody {
+	jackpots := make([]pam.Jackpot, 0, len(amounts))
+	for _, a := range amounts {
+		jackpots = append(jackpots, pam.Jackpot{JackpotAmount: testutils.Ptr(testutils.NewFloatAmount(a))})
+	}
+	return pam.AddTransactionJSONRequestBody{
+		Provider:              "provider",
+		ProviderTransactionId: id,
+		ProviderGameId:        &game,
+		TransactionType:       tt,
+		TransactionDateTime:   at,
+		Currency:              "EUR",
+		Jackpots:              &jackpots,
+	}
+}
+
+func TestLedger(t *testing.T) {
+	ledger := NewLedger(0)
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", day, 0.1))
+	ledger.Record(transaction("2", pam.WITHDRAW, "game1", day, 0.2, 0.3))
+	// resent transaction is not counted twice
+	ledger.Record(transaction("2", pam.WITHDRAW, "game1", day, 0.2, 0.3))
+	ledger.Record(transaction("3", pam.DEPOSIT, "game1", day, 100))
+	ledger.Record(transaction("4", pam.WITHDRAW, "game2", day.AddDate(0, 0, 1), 1))
+	// cancel of a stake not recorded changes nothing
+	ledger.Record(transaction("5", pam.CANCEL, "game1", day, 1))
+	// transactions without jackpots are ignored
+	ledger.Record(pam.AddTransactionJSONRequestBody{ProviderTransactionId: "6", TransactionType: pam.WITHDRAW})
+
+	all := ledger.Report(Filter{From: day, To: day.AddDate(0, 0, 1)})
+	assert.Len(t, all, 2)
+	assert.Equal(t, "game1", all[0].GameID)
+	assert.Equal(t, "2023-03-01", all[0].Date)
+	assert.Equal(t, "0.6", all[0].Contributions.ToAmt().String())
+	assert.Equal(t, 3, all[0].ContributionCount)
+	assert.Equal(t, "100", all[0].Wins.ToAmt().String())
+	assert.Equal(t, 1, all[0].WinCount)
+	assert.Equal(t, "game2", all[1].GameID)
+	assert.Equal(t, "2023-03-02", all[1].Date)
+
+	assert.Len(t, ledger.Report(Filter{From: day, To: day}), 1)
+	assert.Len(t, ledger.Report(Filter{From: day, To: day.AddDate(0, 0, 1), GameID: "game2"}), 1)
+	assert.Len(t, ledger.Report(Filter{From: day, To: day.AddDate(0, 0, 1), Provider: "other"}), 0)
+}
+
+type pamStub struct {
+	pam.PamClient
+	err error
+}
+
+func (p pamStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	if _, _, err := rm(pam.SixDecimalRounder); err != nil {
+		return nil, err
+	}
+	return &pam.TransactionResult{}, p.err
+}
+
+func TestRecordingClient(t *testing.T) {
+	mapper := func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		return context.Background(), &pam.AddTransactionRequest{Body: transaction("1", pam.WITHDRAW, "game1", day, 1)}, nil
+	}
+
+	ledger := NewLedger(0)
+	_, err := NewRecordingClient(pamStub{err: assert.AnError}, ledger).AddTransaction(mapper)
+	assert.Error(t, err)
+	assert.Empty(t, ledger.Report(Filter{From: day, To: day}), "failed transactions are not recorded")
+
+	_, err = NewRecordingClient(pamStub{}, ledger).AddTransaction(mapper)
+	assert.NoError(t, err)
+	assert.Len(t, ledger.Report(Filter{From: day, To: day}), 1)
+}
+
+func TestLedger_DedupWindow(t *testing.T) {
+	now := day
+	ledger := NewLedger(0)
+	ledger.now = func() time.Time { return now }
+
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", day, 0.1))
+	now = now.Add(dedupWindow)
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", day, 0.1))
+	assert.Len(t, ledger.recorded, 1)
+
+	now = now.Add(2 * time.Hour)
+	ledger.Record(transaction("2", pam.WITHDRAW, "game1", day, 0.1))
+	assert.Len(t, ledger.recorded, 1, "transactions recorded before the window are forgotten")
+
+	summaries := ledger.Report(Filter{From: day, To: day})
+	assert.Equal(t, 2, summaries[0].ContributionCount)
+}
+
+func TestLedger_Cancel(t *testing.T) {
+	ledger := NewLedger(0)
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", day, 0.1))
+	stake := transaction("2", pam.PROMOWITHDRAW, "game1", day, 0.2, 0.3)
+	stake.ProviderBetRef = testutils.Ptr("bet2")
+	ledger.Record(stake)
+
+	// cancels refer to the stake by transaction id, or bet reference if set
+	ledger.Record(transaction("1", pam.CANCEL, "game1", day.Add(time.Hour)))
+	cancel := transaction("3", pam.PROMOCANCEL, "game1", day.Add(time.Hour))
+	cancel.ProviderBetRef = testutils.Ptr("bet2")
+	ledger.Record(cancel)
+	// cancelled again
+	ledger.Record(transaction("4", pam.CANCEL, "game1", day.Add(time.Hour)))
+
+	summaries := ledger.Report(Filter{From: day, To: day})
+	assert.Len(t, summaries, 1)
+	assert.True(t, summaries[0].Contributions.ToAmt().Equal(pam.ZeroAmount.ToAmt()), summaries[0].Contributions.ToAmt().String())
+	assert.Equal(t, 0, summaries[0].ContributionCount)
+}
+
+func TestLedger_Retention(t *testing.T) {
+	now := day
+	ledger := NewLedger(48 * time.Hour)
+	ledger.now = func() time.Time { return now }
+
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", day, 0.1))
+	now = now.Add(3 * 24 * time.Hour)
+	ledger.Record(transaction("2", pam.WITHDRAW, "game1", now, 0.1))
+
+	assert.Empty(t, ledger.Report(Filter{From: day, To: day}), "summaries older than the retention are pruned")
+	assert.Len(t, ledger.Report(Filter{From: now, To: now}), 1)
+}
+
+func TestLedgerFromConfig(t *testing.T) {
+	ledger, err := NewLedgerFromConfig(configs.JackpotsConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, ledger, "disabled unless enabled")
+
+	today := time.Now()
+	config := configs.JackpotsConfig{Enabled: true, Filename: filepath.Join(t.TempDir(), "jackpots.jsonl")}
+	ledger, err = NewLedgerFromConfig(config)
+	require.NoError(t, err)
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", today, 0.1))
+	ledger.Record(transaction("2", pam.DEPOSIT, "game1", today, 100))
+
+	// restored from the journal, as if the instance crashed
+	restored, err := NewLedgerFromConfig(config)
+	require.NoError(t, err)
+	summaries := restored.Report(Filter{From: today, To: today})
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "0.1", summaries[0].Contributions.ToAmt().String())
+	assert.Equal(t, 1, summaries[0].ContributionCount)
+	assert.Equal(t, "100", summaries[0].Wins.ToAmt().String())
+	require.NoError(t, restored.Close())
+
+	// restored from the snapshot written when closed, and still deduplicating and cancelling
+	restored, err = NewLedgerFromConfig(config)
+	require.NoError(t, err)
+	restored.Record(transaction("1", pam.WITHDRAW, "game1", today, 0.1))
+	restored.Record(transaction("1", pam.CANCEL, "game1", today))
+	require.NoError(t, restored.Close())
+	restored, err = NewLedgerFromConfig(config)
+	require.NoError(t, err)
+	summaries = restored.Report(Filter{From: today, To: today})
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 0, summaries[0].ContributionCount)
+	assert.Equal(t, 1, summaries[0].WinCount)
+	assert.Equal(t, "100", summaries[0].Wins.ToAmt().String())
+}
+
+func TestLedgerFromConfig_IncompleteEntry(t *testing.T) {
+	today := time.Now()
+	config := configs.JackpotsConfig{Enabled: true, Filename: filepath.Join(t.TempDir(), "jackpots.jsonl")}
+	ledger, err := NewLedgerFromConfig(config)
+	require.NoError(t, err)
+	ledger.Record(transaction("1", pam.WITHDRAW, "game1", today, 0.1))
+
+	f, err := os.OpenFile(config.Filename, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":2,"provid`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	restored, err := NewLedgerFromConfig(config)
+	require.NoError(t, err)
+	assert.Len(t, restored.Report(Filter{From: today, To: today}), 1)
+}
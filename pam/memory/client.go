@@ -0,0 +1,387 @@
+package memory
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// GetSession returns the session, also when expired
+func (p *PAM) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, error) {
+	_, r, err := rm()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	session, found := p.sessions[r.Params.XPlayerToken]
+	if !found {
+		return nil, pamError(pam.PAMERRSESSIONNOTFOUND, "session not found")
+	}
+	player, found := p.players[session.PlayerID]
+	if !found {
+		return nil, pamError(pam.PAMERRPLAYERNOTFOUND, "player not found")
+	}
+	return toPamSession(session, player), nil
+}
+
+// RefreshSession replaces a valid session token with a new one
+func (p *PAM) RefreshSession(rm pam.RefreshSessionRequestMapper) (*pam.Session, error) {
+	_, r, err := rm()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, player, err := p.validSession(r.Params.XPlayerToken)
+	if err != nil {
+		return nil, err
+	}
+	delete(p.sessions, session.Token)
+	session.Token = newToken()
+	p.sessions[session.Token] = session
+	return toPamSession(session, player), nil
+}
+
+// GetBalance returns the balance of the player of a valid session
+func (p *PAM) GetBalance(rm pam.GetBalanceRequestMapper) (*pam.Balance, error) {
+	_, r, err := rm()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, player, err := p.validSession(r.Params.XPlayerToken)
+	if err != nil {
+		return nil, err
+	}
+	balance := player.Balance
+	return &balance, nil
+}
+
+// GetTransactions returns the transactions with the bet reference if given, otherwise
+// the transactions with the transaction id or of the game round
+func (p *PAM) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam.Transaction, error) {
+	_, r, err := rm()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var transactions []pam.Transaction
+	for _, t := range p.transactions {
+		if t.Provider != r.Params.Provider || (r.PlayerID != "" && t.PlayerID != r.PlayerID) {
+			continue
+		}
+		switch {
+		case r.Params.ProviderBetRef != nil:
+			if value(t.ProviderBetRef) != *r.Params.ProviderBetRef {
+				continue
+			}
+		case r.Params.ProviderTransactionId != nil:
+			if t.ProviderTransactionId != *r.Params.ProviderTransactionId {
+				continue
+			}
+		case r.Params.ProviderRoundId != nil:
+			if value(t.ProviderRoundId) != *r.Params.ProviderRoundId {
+				continue
+			}
+		}
+		transactions = append(transactions, t.Transaction)
+	}
+	if len(transactions) == 0 {
+		return nil, pamError(pam.PAMERRTRANSNOTFOUND, "transaction not found")
+	}
+	return transactions, nil
+}
+
+// AddTransaction validates and books the transaction, returning the player balance also
+// when the transaction was rejected
+func (p *PAM) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	_, r, err := rm(pam.SixDecimalRounder)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if isBet(r.Body.TransactionType) {
+		if _, _, err = p.validSession(r.Params.XPlayerToken); err != nil {
+			return nil, err
+		}
+	}
+	player, found := p.players[r.PlayerID]
+	if !found {
+		return nil, pamError(pam.PAMERRPLAYERNOTFOUND, "player not found")
+	}
+
+	t := r.Body
+	t.Provider = r.Params.Provider
+	id, err := p.book(player, t)
+	balance := player.Balance
+	if err != nil {
+		return &pam.TransactionResult{Balance: &balance}, err
+	}
+	return &pam.TransactionResult{Balance: &balance, TransactionId: &id}, nil
+}
+
+// GetGameRound returns the game round of the player
+func (p *PAM) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRound, error) {
+	_, r, err := rm()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	round, found := p.rounds[roundKey{playerID: r.PlayerID, roundID: r.ProviderRoundID}]
+	if !found {
+		return nil, pamError(pam.PAMERRROUNDNOTFOUND, "game round not found")
+	}
+	gameRound := *round
+	return &gameRound, nil
+}
+
+// GetTransactionSupplier returns the transaction supplier of the PAM
+func (p *PAM) GetTransactionSupplier() pam.TransactionSupplier {
+	return p.supplier
+}
+
+func (p *PAM) validSession(token pam.SessionToken) (*Session, *Player, error) {
+	session, found := p.sessions[token]
+	if !found {
+		return nil, nil, pamError(pam.PAMERRSESSIONNOTFOUND, "session not found")
+	}
+	if session.Expired {
+		return nil, nil, pamError(pam.PAMERRSESSIONEXPIRED, "session expired")
+	}
+	player, found := p.players[session.PlayerID]
+	if !found {
+		return nil, nil, pamError(pam.PAMERRPLAYERNOTFOUND, "player not found")
+	}
+	return session, player, nil
+}
+
+// book validates the transaction and updates the player balance and game round. Transactions
+// already booked are not booked again, instead the id of the existing transaction is returned,
+// even if the player has since been blocked or spent the funds.
+func (p *PAM) book(player *Player, t pam.Transaction) (pam.TransactionId, error) {
+	switch t.TransactionType {
+	case pam.WITHDRAW, pam.DEPOSIT, pam.CANCEL, pam.PROMOWITHDRAW, pam.PROMODEPOSIT, pam.PROMOCANCEL:
+	default:
+		return "", pamError(pam.PAMERRUNDEFINED, "unknown transaction type")
+	}
+
+	existing, err := p.previousTransaction(player.ID, t, true)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		return existing.ID, nil
+	}
+	if t.Currency != player.Currency {
+		return "", pamError(pam.PAMERRTRANSCURRENCY, "currency does not match player currency")
+	}
+	if player.Blocked && isBet(t.TransactionType) {
+		return "", pamError(pam.PAMERRBETNOTALLOWED, "player is blocked")
+	}
+	if isNegative(t.CashAmount) || isNegative(t.BonusAmount) || isNegative(t.PromoAmount) {
+		return "", pamError(pam.PAMERRNEGATIVESTAKE, "negative amount")
+	}
+	if isBet(t.TransactionType) {
+		switch {
+		case isLess(player.Balance.CashAmount, t.CashAmount):
+			return "", pamError(pam.PAMERRCASHOVERDRAFT, "insufficient funds")
+		case isLess(player.Balance.BonusAmount, t.BonusAmount):
+			return "", pamError(pam.PAMERRBONUSOVERDRAFT, "insufficient bonus funds")
+		case isLess(player.Balance.PromoAmount, t.PromoAmount):
+			return "", pamError(pam.PAMERRPROMOOVERDRAFT, "insufficient promo funds")
+		}
+	}
+
+	switch t.TransactionType {
+	case pam.CANCEL, pam.PROMOCANCEL:
+		err = p.validateCancel(player.ID, &t)
+	case pam.DEPOSIT:
+		err = p.validateDeposit(player.ID, t)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	id := strconv.Itoa(len(p.transactions) + 1)
+	p.transactions = append(p.transactions, Transaction{Transaction: t, ID: id, PlayerID: player.ID})
+
+	if isBet(t.TransactionType) {
+		player.Balance.CashAmount = player.Balance.CashAmount.Sub(t.CashAmount)
+		player.Balance.BonusAmount = player.Balance.BonusAmount.Sub(t.BonusAmount)
+		player.Balance.PromoAmount = player.Balance.PromoAmount.Sub(t.PromoAmount)
+	} else {
+		player.Balance.CashAmount = player.Balance.CashAmount.Add(t.CashAmount)
+		player.Balance.BonusAmount = player.Balance.BonusAmount.Add(t.BonusAmount)
+		player.Balance.PromoAmount = player.Balance.PromoAmount.Add(t.PromoAmount)
+	}
+
+	p.updateGameRound(player.ID, t)
+	return id, nil
+}
+
+// previousTransaction returns the latest transaction with the same bet reference, or the same
+// transaction id if there is no reference. Use matchType to only match transactions of the same type.
+func (p *PAM) previousTransaction(playerID pam.PlayerId, t pam.Transaction, matchType bool) (*Transaction, error) {
+	var previous []Transaction
+	for _, booked := range p.transactions {
+		if booked.Provider != t.Provider {
+			continue
+		}
+		sameID := booked.ProviderTransactionId == t.ProviderTransactionId
+		if sameID && (booked.PlayerID != playerID ||
+			value(booked.ProviderGameId) != value(t.ProviderGameId) ||
+			value(booked.ProviderRoundId) != value(t.ProviderRoundId)) {
+			return nil, pamError(pam.PAMERRDUPLICATETRANS, "transaction id already used")
+		}
+		if (t.ProviderBetRef != nil && value(booked.ProviderBetRef) == *t.ProviderBetRef) ||
+			(t.ProviderBetRef == nil && sameID) {
+			previous = append(previous, booked)
+		}
+	}
+
+	if matchType {
+		for i := range previous {
+			if previous[i].TransactionType == t.TransactionType {
+				return &previous[i], nil
+			}
+		}
+		return nil, nil
+	}
+	if len(previous) > 0 {
+		return &previous[len(previous)-1], nil
+	}
+	return nil, nil
+}
+
+// validateCancel checks that the cancelled bet is neither settled nor cancelled, and sets
+// the amounts of the cancel to the amounts of the bet
+func (p *PAM) validateCancel(playerID pam.PlayerId, t *pam.Transaction) error {
+	previous, err := p.previousTransaction(playerID, *t, false)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return pamError(pam.PAMERRCANCELNOTFOUND, "no transaction to cancel")
+	}
+	switch previous.TransactionType {
+	case pam.CANCEL, pam.PROMOCANCEL:
+		return pamError(pam.PAMERRTRANSALREADYCANCELLED, "transaction already cancelled")
+	case pam.DEPOSIT, pam.PROMODEPOSIT:
+		return pamError(pam.PAMERRTRANSALREADYSETTLED, "transaction already settled")
+	}
+	if round, found := p.rounds[roundKey{playerID: playerID, roundID: value(t.ProviderRoundId)}]; found && round.EndTime != nil {
+		return pamError(pam.PAMERRTRANSALREADYSETTLED, "game round already ended")
+	}
+
+	t.CashAmount = previous.CashAmount
+	t.BonusAmount = previous.BonusAmount
+	t.PromoAmount = previous.PromoAmount
+	return nil
+}
+
+// validateDeposit checks that the deposit settles an open game round with a bet that is not cancelled.
+// With the PROVIDER transaction supplier the round transactions of the deposit are used instead.
+func (p *PAM) validateDeposit(playerID pam.PlayerId, t pam.Transaction) error {
+	if p.supplier == pam.PROVIDER {
+		return validateRoundTransactions(t)
+	}
+	round, found := p.rounds[roundKey{playerID: playerID, roundID: value(t.ProviderRoundId)}]
+	if !found {
+		return pamError(pam.PAMERRTRANSNOTFOUND, "no game round matching deposit")
+	}
+	if round.EndTime != nil {
+		return pamError(pam.PAMERRTRANSALREADYSETTLED, "game round already ended")
+	}
+
+	previous, err := p.previousTransaction(playerID, t, false)
+	if err != nil || previous == nil {
+		return err
+	}
+	switch previous.TransactionType {
+	case pam.WITHDRAW:
+		return nil
+	case pam.CANCEL:
+		return pamError(pam.PAMERRTRANSALREADYCANCELLED, "bet already cancelled")
+	default:
+		return pamError(pam.PAMERRTRANSNOTFOUND, "no bet matching deposit")
+	}
+}
+
+// validateRoundTransactions checks that the round transactions supplied with the deposit include a bet
+func validateRoundTransactions(t pam.Transaction) error {
+	if t.RoundTransactions == nil || len(*t.RoundTransactions) == 0 {
+		return pamError(pam.PAMERRTRANSNOTFOUND, "round transactions missing from deposit")
+	}
+	for _, rt := range *t.RoundTransactions {
+		if rt.TransactionType == pam.WITHDRAW || rt.TransactionType == pam.PROMOWITHDRAW {
+			return nil
+		}
+	}
+	return pamError(pam.PAMERRTRANSNOTFOUND, "no bet in round transactions")
+}
+
+// updateGameRound starts the game round of the transaction, or ends it when the game is over
+func (p *PAM) updateGameRound(playerID pam.PlayerId, t pam.Transaction) {
+	if t.ProviderRoundId == nil {
+		return
+	}
+	key := roundKey{playerID: playerID, roundID: *t.ProviderRoundId}
+	round, found := p.rounds[key]
+	if !found {
+		round = &pam.GameRound{
+			ProviderGameId:  value(t.ProviderGameId),
+			ProviderRoundId: *t.ProviderRoundId,
+			StartTime:       time.Now(),
+		}
+		p.rounds[key] = round
+	}
+	if value(t.IsGameOver) && round.EndTime == nil {
+		now := time.Now()
+		round.EndTime = &now
+	}
+}
+
+func toPamSession(session *Session, player *Player) *pam.Session {
+	return &pam.Session{
+		Country:  player.Country,
+		Currency: player.Currency,
+		Language: player.Language,
+		PlayerId: player.ID,
+		Token:    session.Token,
+		GameId:   session.GameID,
+	}
+}
+
+func isBet(t pam.TransactionType) bool {
+	return t == pam.WITHDRAW || t == pam.PROMOWITHDRAW
+}
+
+func isNegative(a pam.Amount) bool {
+	return decimal.Decimal(a).IsNegative()
+}
+
+func isLess(a, b pam.Amount) bool {
+	return decimal.Decimal(a).LessThan(decimal.Decimal(b))
+}
+
+func value[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
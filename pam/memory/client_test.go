@@ -0,0 +1,320 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	testProvider = "provider"
+	testPlayer   = "player"
+	testToken    = "token"
+)
+
+func amount(v string) pam.Amount {
+	return pam.Amount(decimal.RequireFromString(v))
+}
+
+func newTestPAM(t *testing.T) *PAM {
+	p := New()
+	p.AddPlayer(Player{ID: testPlayer, Currency: "EUR", Country: "SE", Language: "sv", Balance: pam.Balance{
+		CashAmount: amount("100"), BonusAmount: amount("0"), PromoAmount: amount("0"),
+	}})
+	_, err := p.AddSession(Session{Token: testToken, PlayerID: testPlayer})
+	require.NoError(t, err)
+	return p
+}
+
+func add(p *PAM, tt pam.TransactionType, id, ref, round, amt string, gameOver bool) (*pam.TransactionResult, error) {
+	return p.AddTransaction(func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		t := pam.Transaction{
+			TransactionType:       tt,
+			ProviderTransactionId: id,
+			ProviderRoundId:       &round,
+			Currency:              "EUR",
+			CashAmount:            amount(amt),
+			IsGameOver:            &gameOver,
+		}
+		if ref != "" {
+			t.ProviderBetRef = &ref
+		}
+		return context.Background(), &pam.AddTransactionRequest{
+			PlayerID: testPlayer,
+			Params:   pam.AddTransactionParams{Provider: testProvider, XPlayerToken: testToken},
+			Body:     t,
+		}, nil
+	})
+}
+
+func valkErrorCode(err error) pam.ValkErrorCode {
+	var vErr pam.ValkyrieError
+	if errors.As(err, &vErr) {
+		return vErr.ValkErrorCode
+	}
+	return -1
+}
+
+func TestPAM_sessions(t *testing.T) {
+	p := newTestPAM(t)
+
+	session, err := p.GetSession(func() (context.Context, pam.GetSessionRequest, error) {
+		return context.Background(), pam.GetSessionRequest{Params: pam.GetSessionParams{XPlayerToken: testToken}}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pam.Session{PlayerId: testPlayer, Currency: "EUR", Country: "SE", Language: "sv", Token: testToken}, *session)
+
+	refreshed, err := p.RefreshSession(func() (context.Context, pam.RefreshSessionRequest, error) {
+		return context.Background(), pam.RefreshSessionRequest{Params: pam.RefreshSessionParams{XPlayerToken: testToken}}, nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, refreshed.Token, 32)
+	_, found := p.Session(testToken)
+	assert.False(t, found, "refreshed token replaces the old one")
+
+	require.NoError(t, p.ExpireSession(refreshed.Token))
+	_, err = p.GetBalance(func() (context.Context, pam.GetBalanceRequest, error) {
+		return context.Background(), pam.GetBalanceRequest{Params: pam.GetBalanceParams{XPlayerToken: refreshed.Token}}, nil
+	})
+	assert.Equal(t, pam.ValkErrOpSessionExpired, valkErrorCode(err))
+
+	_, err = p.GetSession(func() (context.Context, pam.GetSessionRequest, error) {
+		return context.Background(), pam.GetSessionRequest{Params: pam.GetSessionParams{XPlayerToken: "unknown"}}, nil
+	})
+	assert.Equal(t, pam.ValkErrOpSessionNotFound, valkErrorCode(err))
+}
+
+func TestPAM_AddTransaction(t *testing.T) {
+	type step struct {
+		tt       pam.TransactionType
+		id, ref  string
+		amount   string
+		gameOver bool
+	}
+	tests := []struct {
+		name    string
+		steps   []step
+		want    pam.ValkErrorCode // of the last step, or -1 if successful
+		balance string
+		booked  []pam.TransactionType
+	}{
+		{
+			name:    "bet and win",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "10", false}, {pam.DEPOSIT, "2", "1", "25", true}},
+			want:    -1,
+			balance: "115",
+			booked:  []pam.TransactionType{pam.WITHDRAW, pam.DEPOSIT},
+		},
+		{
+			name:    "duplicate bet is booked once",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "10", false}, {pam.WITHDRAW, "1", "1", "10", false}},
+			want:    -1,
+			balance: "90",
+			booked:  []pam.TransactionType{pam.WITHDRAW},
+		},
+		{
+			name:    "duplicate bet after spending the funds",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "60", false}, {pam.WITHDRAW, "1", "1", "60", false}},
+			want:    -1,
+			balance: "40",
+			booked:  []pam.TransactionType{pam.WITHDRAW},
+		},
+		{
+			name:    "insufficient funds",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "100.01", false}},
+			want:    pam.ValkErrOpCashOverdraft,
+			balance: "100",
+		},
+		{
+			name:    "negative amount",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "-1", false}},
+			want:    pam.ValkErrOpNegativeStake,
+			balance: "100",
+		},
+		{
+			name:    "cancel bet refunds bet amount",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "10", false}, {pam.CANCEL, "2", "1", "0", false}},
+			want:    -1,
+			balance: "100",
+			booked:  []pam.TransactionType{pam.WITHDRAW, pam.CANCEL},
+		},
+		{
+			name:    "cancel unknown bet",
+			steps:   []step{{pam.CANCEL, "1", "1", "10", false}},
+			want:    pam.ValkErrOpCancelNotFound,
+			balance: "100",
+		},
+		{
+			name:    "cancel settled bet",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "10", false}, {pam.DEPOSIT, "2", "1", "25", true}, {pam.CANCEL, "3", "1", "10", false}},
+			want:    pam.ValkErrAlreadySettled,
+			balance: "115",
+			booked:  []pam.TransactionType{pam.WITHDRAW, pam.DEPOSIT},
+		},
+		{
+			name:    "win on cancelled bet",
+			steps:   []step{{pam.WITHDRAW, "1", "1", "10", false}, {pam.CANCEL, "2", "1", "0", false}, {pam.DEPOSIT, "3", "1", "25", true}},
+			want:    pam.ValkErrOpCancelExists,
+			balance: "100",
+			booked:  []pam.TransactionType{pam.WITHDRAW, pam.CANCEL},
+		},
+		{
+			name:    "win without bet",
+			steps:   []step{{pam.DEPOSIT, "1", "1", "25", true}},
+			want:    pam.ValkErrOpTransNotFound,
+			balance: "100",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			p := newTestPAM(tt)
+			var (
+				res *pam.TransactionResult
+				err error
+			)
+			for _, s := range test.steps {
+				res, err = add(p, s.tt, s.id, s.ref, "round", s.amount, s.gameOver)
+			}
+			if test.want == -1 {
+				require.NoError(tt, err)
+				assert.NotNil(tt, res.TransactionId)
+			} else {
+				assert.Equal(tt, test.want, valkErrorCode(err))
+			}
+			require.NotNil(tt, res.Balance, "balance is returned also on errors")
+			assert.True(tt, decimal.RequireFromString(test.balance).Equal(decimal.Decimal(res.Balance.CashAmount)),
+				"expected balance %s, got %s", test.balance, res.Balance.CashAmount.ToAmt())
+
+			var booked []pam.TransactionType
+			for _, t := range p.Transactions(testPlayer) {
+				booked = append(booked, t.TransactionType)
+			}
+			assert.Equal(tt, test.booked, booked)
+		})
+	}
+}
+
+func TestPAM_AddTransaction_duplicateTransactionID(t *testing.T) {
+	p := newTestPAM(t)
+	_, err := add(p, pam.WITHDRAW, "1", "1", "round", "10", false)
+	require.NoError(t, err)
+
+	_, err = add(p, pam.WITHDRAW, "1", "1", "other round", "10", false)
+	assert.Equal(t, pam.ValkErrDuplicateTrans, valkErrorCode(err))
+}
+
+func TestPAM_AddTransaction_duplicateAfterBlocked(t *testing.T) {
+	p := newTestPAM(t)
+	first, err := add(p, pam.WITHDRAW, "1", "1", "round", "10", false)
+	require.NoError(t, err)
+
+	p.players[testPlayer].Blocked = true
+	res, err := add(p, pam.WITHDRAW, "1", "1", "round", "10", false)
+	require.NoError(t, err, "a resent bet is not rejected")
+	assert.Equal(t, first.TransactionId, res.TransactionId)
+
+	_, err = add(p, pam.WITHDRAW, "2", "2", "round", "10", false)
+	assert.Equal(t, pam.ValkErrOpBetNotAllowed, valkErrorCode(err))
+}
+
+func TestPAM_GetTransactionsAndGameRound(t *testing.T) {
+	p := newTestPAM(t)
+	_, err := add(p, pam.WITHDRAW, "1", "bet", "round", "10", false)
+	require.NoError(t, err)
+
+	getRound := func() (context.Context, pam.GetGameRoundRequest, error) {
+		return context.Background(), pam.GetGameRoundRequest{PlayerID: testPlayer, ProviderRoundID: "round"}, nil
+	}
+	round, err := p.GetGameRound(getRound)
+	require.NoError(t, err)
+	assert.Nil(t, round.EndTime)
+
+	_, err = add(p, pam.DEPOSIT, "2", "bet", "round", "5", true)
+	require.NoError(t, err)
+	round, err = p.GetGameRound(getRound)
+	require.NoError(t, err)
+	assert.NotNil(t, round.EndTime, "game over ends the round")
+
+	ref := "bet"
+	transactions, err := p.GetTransactions(func() (context.Context, pam.GetTransactionsRequest, error) {
+		return context.Background(), pam.GetTransactionsRequest{
+			PlayerID: testPlayer,
+			Params:   pam.GetTransactionsParams{Provider: testProvider, ProviderBetRef: &ref},
+		}, nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 2)
+
+	_, err = add(p, pam.WITHDRAW, "3", "", "other round", "10", false)
+	require.NoError(t, err)
+	roundID := "round"
+	transactions, err = p.GetTransactions(func() (context.Context, pam.GetTransactionsRequest, error) {
+		return context.Background(), pam.GetTransactionsRequest{
+			PlayerID: testPlayer,
+			Params:   pam.GetTransactionsParams{Provider: testProvider, ProviderRoundId: &roundID},
+		}, nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 2, "transactions of the round")
+
+	id := "4"
+	_, err = p.GetTransactions(func() (context.Context, pam.GetTransactionsRequest, error) {
+		return context.Background(), pam.GetTransactionsRequest{
+			PlayerID: testPlayer,
+			Params:   pam.GetTransactionsParams{Provider: testProvider, ProviderTransactionId: &id},
+		}, nil
+	})
+	assert.Equal(t, pam.ValkErrOpTransNotFound, valkErrorCode(err))
+}
+
+func TestPAM_AddTransaction_providerSupplier(t *testing.T) {
+	p := newTestPAM(t).WithTransactionSupplier(pam.PROVIDER)
+	assert.Equal(t, pam.PROVIDER, p.GetTransactionSupplier())
+
+	deposit := func(roundTransactions *[]pam.RoundTransaction) error {
+		_, err := p.AddTransaction(func(pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+			return context.Background(), &pam.AddTransactionRequest{
+				PlayerID: testPlayer,
+				Params:   pam.AddTransactionParams{Provider: testProvider, XPlayerToken: testToken},
+				Body: pam.Transaction{
+					TransactionType:       pam.DEPOSIT,
+					ProviderTransactionId: "win",
+					Currency:              "EUR",
+					CashAmount:            amount("25"),
+					RoundTransactions:     roundTransactions,
+				},
+			}, nil
+		})
+		return err
+	}
+
+	assert.Equal(t, pam.ValkErrOpTransNotFound, valkErrorCode(deposit(nil)), "round transactions are required")
+	assert.Equal(t, pam.ValkErrOpTransNotFound, valkErrorCode(deposit(&[]pam.RoundTransaction{{TransactionType: pam.DEPOSIT}})))
+
+	// the bet is supplied by the provider, without being booked by the PAM
+	require.NoError(t, deposit(&[]pam.RoundTransaction{{TransactionType: pam.WITHDRAW}}))
+	player, _ := p.Player(testPlayer)
+	assert.Equal(t, "125", player.Balance.CashAmount.ToAmt().String())
+}
+
+func TestPAM_AdjustBalance(t *testing.T) {
+	p := newTestPAM(t)
+
+	balance, err := p.AdjustBalance(testPlayer, pam.Balance{CashAmount: amount("-40"), BonusAmount: amount("5")})
+	require.NoError(t, err)
+	assert.Equal(t, "60", balance.CashAmount.ToAmt().String())
+	assert.Equal(t, "5", balance.BonusAmount.ToAmt().String())
+
+	balance, err = p.AdjustBalance(testPlayer, pam.Balance{CashAmount: amount("-60.01")})
+	assert.Equal(t, pam.ValkErrOpCashOverdraft, valkErrorCode(err))
+	assert.Equal(t, "60", balance.CashAmount.ToAmt().String(), "balance is unchanged")
+
+	_, err = p.AdjustBalance("unknown", pam.Balance{})
+	assert.Equal(t, pam.ValkErrOpUserNotFound, valkErrorCode(err))
+}
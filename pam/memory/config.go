@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	DriverName = "memory"
+)
+
+func init() {
+	pam.ClientFactory().
+		Register(DriverName, func(args pam.ClientArgs) (pam.PamClient, error) {
+			return Create(args.Config)
+		})
+}
+
+type memoryPamConfig struct {
+	Name string `mapstructure:"name"`
+	// TransactionSupplier "OPERATOR" (default) or "PROVIDER"
+	TransactionSupplier pam.TransactionSupplier `mapstructure:"transaction_supplier"`
+	// SeedFile optional yaml file with players and sessions to start with
+	SeedFile string `mapstructure:"seed_file"`
+}
+
+// Seed players and sessions the PAM starts with
+type Seed struct {
+	Players []SeedPlayer `yaml:"players"`
+}
+
+// SeedPlayer player with balances and sessions. Amounts default to zero.
+type SeedPlayer struct {
+	ID       string          `yaml:"id"`
+	Currency string          `yaml:"currency"`
+	Country  string          `yaml:"country"`
+	Language string          `yaml:"language"`
+	Cash     decimal.Decimal `yaml:"cash"`
+	Bonus    decimal.Decimal `yaml:"bonus"`
+	Promo    decimal.Decimal `yaml:"promo"`
+	Blocked  bool            `yaml:"blocked"`
+	Sessions []SeedSession   `yaml:"sessions"`
+}
+
+// SeedSession session of a seeded player. A token is generated if missing.
+type SeedSession struct {
+	Token  string `yaml:"token"`
+	GameID string `yaml:"game_id"`
+}
+
+// Create creates a PAM with the configured transaction supplier, seeded from the seed file if configured
+func Create(cfg configs.PamConf) (*PAM, error) {
+	config, err := pam.GetConfig[memoryPamConfig](cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Msgf("Creating %s pam client", config.Name)
+
+	supplier := config.TransactionSupplier
+	switch supplier {
+	case "":
+		supplier = pam.OPERATOR
+	case pam.OPERATOR, pam.PROVIDER:
+	default:
+		return nil, fmt.Errorf("unknown transaction supplier '%s'", supplier)
+	}
+	p := New().WithTransactionSupplier(supplier)
+
+	if config.SeedFile != "" {
+		seed, err := LoadSeed(config.SeedFile)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.Seed(*seed); err != nil {
+			return nil, err
+		}
+		log.Info().Msgf("Seeded %s pam client with %d players from '%s'", config.Name, len(seed.Players), config.SeedFile)
+	}
+	return p, nil
+}
+
+// LoadSeed reads a seed from a yaml file
+func LoadSeed(path string) (*Seed, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read seed file: %w", err)
+	}
+	var seed Seed
+	if err = yaml.Unmarshal(bytes, &seed); err != nil {
+		return nil, fmt.Errorf("unable to parse seed file '%s': %w", path, err)
+	}
+	return &seed, nil
+}
+
+// Seed adds the players and sessions of the seed
+func (p *PAM) Seed(seed Seed) error {
+	for _, sp := range seed.Players {
+		if sp.ID == "" || sp.Currency == "" {
+			return fmt.Errorf("seeded player requires id and currency")
+		}
+		p.AddPlayer(Player{
+			ID:       sp.ID,
+			Currency: sp.Currency,
+			Country:  sp.Country,
+			Language: sp.Language,
+			Balance: pam.Balance{
+				CashAmount:  pam.Amount(sp.Cash),
+				BonusAmount: pam.Amount(sp.Bonus),
+				PromoAmount: pam.Amount(sp.Promo),
+			},
+			Blocked: sp.Blocked,
+		})
+		for _, ss := range sp.Sessions {
+			session := Session{Token: ss.Token, PlayerID: sp.ID}
+			if ss.GameID != "" {
+				gameID := ss.GameID
+				session.GameID = &gameID
+			}
+			if _, err := p.AddSession(session); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   configs.PamConf
+		supplier pam.TransactionSupplier
+		wantErr  string
+	}{
+		{
+			name:     "defaults to operator supplier",
+			config:   configs.PamConf{"name": DriverName},
+			supplier: pam.OPERATOR,
+		},
+		{
+			name:     "provider supplier",
+			config:   configs.PamConf{"name": DriverName, "transaction_supplier": "PROVIDER"},
+			supplier: pam.PROVIDER,
+		},
+		{
+			name:    "unknown supplier",
+			config:  configs.PamConf{"name": DriverName, "transaction_supplier": "PLAYER"},
+			wantErr: "unknown transaction supplier 'PLAYER'",
+		},
+		{
+			name:    "missing seed file",
+			config:  configs.PamConf{"name": DriverName, "seed_file": "testdata/missing.yml"},
+			wantErr: "unable to read seed file",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			p, err := Create(test.config)
+			if test.wantErr != "" {
+				assert.ErrorContains(tt, err, test.wantErr)
+				return
+			}
+			require.NoError(tt, err)
+			assert.Equal(tt, test.supplier, p.GetTransactionSupplier())
+		})
+	}
+}
+
+func TestCreate_seed(t *testing.T) {
+	client, err := pam.GetPamClient(pam.ClientArgs{Config: configs.PamConf{"name": DriverName, "seed_file": "testdata/seed.yml"}})
+	require.NoError(t, err)
+	p := client.(*PAM)
+
+	player, found := p.Player("player-1")
+	require.True(t, found)
+	assert.Equal(t, "EUR", player.Currency)
+	assert.Equal(t, "100.5", player.Balance.CashAmount.ToAmt().String())
+	assert.Equal(t, "10", player.Balance.BonusAmount.ToAmt().String())
+	assert.Equal(t, "0", player.Balance.PromoAmount.ToAmt().String())
+
+	session, found := p.Session("token-1")
+	require.True(t, found)
+	assert.Equal(t, "player-1", session.PlayerID)
+	assert.Equal(t, "game-1", *session.GameID)
+	assert.Len(t, p.sessions, 2, "token is generated for session without token")
+
+	player, found = p.Player("player-2")
+	require.True(t, found)
+	assert.True(t, player.Blocked)
+}
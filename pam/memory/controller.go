@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// AddSessionRequest session to create for a player
+type AddSessionRequest struct {
+	Token  pam.SessionToken    `json:"token,omitempty"`
+	GameID *pam.ProviderGameId `json:"gameId,omitempty"`
+}
+
+// PlayerEndpoint returns the player with the "playerId" path parameter, including balances
+func PlayerEndpoint(p *PAM) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		player, found := p.Player(c.Params("playerId"))
+		if !found {
+			return c.Status(fiber.StatusNotFound).JSON("player not found")
+		}
+		return c.JSON(player)
+	}
+}
+
+// AdjustBalanceEndpoint adds the amounts of the pam.Balance request body to the balance of the
+// player with the "playerId" path parameter, and returns the new balance. Use negative amounts
+// to withdraw funds.
+func AdjustBalanceEndpoint(p *PAM) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var adjustment pam.Balance
+		if err := c.BodyParser(&adjustment); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+		}
+		balance, err := p.AdjustBalance(c.Params("playerId"), adjustment)
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		return c.JSON(balance)
+	}
+}
+
+// AddSessionEndpoint creates a session for the player with the "playerId" path parameter, to
+// be used when launching games. A token is generated unless given in the request body.
+func AddSessionEndpoint(p *PAM) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req AddSessionRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+			}
+		}
+		session, err := p.AddSession(Session{Token: req.Token, PlayerID: c.Params("playerId"), GameID: req.GameID})
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		return c.Status(fiber.StatusCreated).JSON(session)
+	}
+}
+
+// ExpireSessionEndpoint expires the session with the "token" path parameter
+func ExpireSessionEndpoint(p *PAM) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := p.ExpireSession(c.Params("token")); err != nil {
+			return errorResponse(c, err)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func errorResponse(c *fiber.Ctx, err error) error {
+	var valkErr pam.ValkyrieError
+	if errors.As(err, &valkErr) &&
+		(valkErr.ValkErrorCode == pam.ValkErrOpUserNotFound || valkErr.ValkErrorCode == pam.ValkErrOpSessionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(valkErr.Error())
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	p := newTestPAM(t)
+	app := fiber.New()
+	app.Get("/players/:playerId", PlayerEndpoint(p))
+	app.Post("/players/:playerId/balance", AdjustBalanceEndpoint(p))
+	app.Post("/players/:playerId/sessions", AddSessionEndpoint(p))
+	app.Post("/sessions/:token/expire", ExpireSessionEndpoint(p))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"get player", http.MethodGet, "/players/" + testPlayer, "", http.StatusOK},
+		{"get unknown player", http.MethodGet, "/players/unknown", "", http.StatusNotFound},
+		{"deposit funds", http.MethodPost, "/players/" + testPlayer + "/balance", `{"cashAmount": 50}`, http.StatusOK},
+		{"overdraw funds", http.MethodPost, "/players/" + testPlayer + "/balance", `{"cashAmount": -500}`, http.StatusBadRequest},
+		{"invalid balance", http.MethodPost, "/players/" + testPlayer + "/balance", `{"cashAmount": "x"}`, http.StatusBadRequest},
+		{"adjust unknown player", http.MethodPost, "/players/unknown/balance", `{"cashAmount": 1}`, http.StatusNotFound},
+		{"add session", http.MethodPost, "/players/" + testPlayer + "/sessions", `{"token": "new-token"}`, http.StatusCreated},
+		{"add session without body", http.MethodPost, "/players/" + testPlayer + "/sessions", "", http.StatusCreated},
+		{"add session for unknown player", http.MethodPost, "/players/unknown/sessions", "", http.StatusNotFound},
+		{"expire session", http.MethodPost, "/sessions/" + testToken + "/expire", "", http.StatusNoContent},
+		{"expire unknown session", http.MethodPost, "/sessions/unknown/expire", "", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(tt, err)
+			assert.Equal(tt, test.wantStatus, resp.StatusCode)
+		})
+	}
+
+	player, _ := p.Player(testPlayer)
+	assert.Equal(t, "150", player.Balance.CashAmount.ToAmt().String())
+	session, _ := p.Session(testToken)
+	assert.True(t, session.Expired)
+	_, found := p.Session("new-token")
+	assert.True(t, found)
+}
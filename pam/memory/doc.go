@@ -0,0 +1,8 @@
+// Package memory provides a PAM keeping players, sessions, transactions and game rounds
+// in memory. It follows the same validation rules as the reference PAM implementation and
+// is intended for development and for testing provider integrations.
+//
+// The PAM is registered as the "memory" pam driver, optionally seeded with players and
+// sessions from a yaml file (see testdata/seed.yml). Balances and sessions can be managed
+// through operator endpoints, allowing Valkyrie to run without any external PAM.
+package memory
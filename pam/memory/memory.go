@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// Player account of a player, holding balances in a single currency
+type Player struct {
+	ID       pam.PlayerId `json:"id"`
+	Currency pam.Currency `json:"currency"`
+	Country  pam.Country  `json:"country"`
+	Language pam.Language `json:"language"`
+	Balance  pam.Balance  `json:"balance"`
+	// Blocked players are not allowed to place bets
+	Blocked bool `json:"blocked"`
+}
+
+// Session game session of a player
+type Session struct {
+	Token    pam.SessionToken    `json:"token"`
+	PlayerID pam.PlayerId        `json:"playerId"`
+	GameID   *pam.ProviderGameId `json:"gameId,omitempty"`
+	// Expired sessions can still be looked up, but not be used for bets
+	Expired bool `json:"expired"`
+}
+
+// Transaction booked transaction
+type Transaction struct {
+	pam.Transaction
+	ID       pam.TransactionId
+	PlayerID pam.PlayerId
+}
+
+type roundKey struct {
+	playerID pam.PlayerId
+	roundID  pam.ProviderRoundId
+}
+
+// PAM in-memory PAM implementing pam.PamClient
+type PAM struct {
+	mu           sync.RWMutex
+	supplier     pam.TransactionSupplier
+	players      map[pam.PlayerId]*Player
+	sessions     map[pam.SessionToken]*Session
+	transactions []Transaction
+	rounds       map[roundKey]*pam.GameRound
+}
+
+// New creates an empty PAM, keeping track of round transactions itself
+func New() *PAM {
+	return &PAM{
+		supplier: pam.OPERATOR,
+		players:  map[pam.PlayerId]*Player{},
+		sessions: map[pam.SessionToken]*Session{},
+		rounds:   map[roundKey]*pam.GameRound{},
+	}
+}
+
+// WithTransactionSupplier sets the transaction supplier. With PROVIDER, deposits are validated
+// using the round transactions supplied with them instead of the transactions booked by the PAM.
+func (p *PAM) WithTransactionSupplier(supplier pam.TransactionSupplier) *PAM {
+	p.supplier = supplier
+	return p
+}
+
+// AddPlayer adds the player, replacing any existing player with the same id
+func (p *PAM) AddPlayer(player Player) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.players[player.ID] = &player
+}
+
+// AddSession adds the session of an existing player. A token is generated if missing.
+func (p *PAM) AddSession(session Session) (Session, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, found := p.players[session.PlayerID]; !found {
+		return Session{}, pamError(pam.PAMERRPLAYERNOTFOUND, "player not found")
+	}
+	if session.Token == "" {
+		session.Token = newToken()
+	}
+	p.sessions[session.Token] = &session
+	return session, nil
+}
+
+// ExpireSession expires the session with token
+func (p *PAM) ExpireSession(token pam.SessionToken) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session, found := p.sessions[token]
+	if !found {
+		return pamError(pam.PAMERRSESSIONNOTFOUND, "session not found")
+	}
+	session.Expired = true
+	return nil
+}
+
+// AdjustBalance adds the amounts of adjustment to the balance of the player, negative amounts
+// withdraw funds. Adjustments resulting in a negative balance are rejected.
+func (p *PAM) AdjustBalance(playerID pam.PlayerId, adjustment pam.Balance) (pam.Balance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	player, found := p.players[playerID]
+	if !found {
+		return pam.Balance{}, pamError(pam.PAMERRPLAYERNOTFOUND, "player not found")
+	}
+	balance := pam.Balance{
+		CashAmount:  player.Balance.CashAmount.Add(adjustment.CashAmount),
+		BonusAmount: player.Balance.BonusAmount.Add(adjustment.BonusAmount),
+		PromoAmount: player.Balance.PromoAmount.Add(adjustment.PromoAmount),
+	}
+	switch {
+	case isNegative(balance.CashAmount):
+		return player.Balance, pamError(pam.PAMERRCASHOVERDRAFT, "cash balance would be negative")
+	case isNegative(balance.BonusAmount):
+		return player.Balance, pamError(pam.PAMERRBONUSOVERDRAFT, "bonus balance would be negative")
+	case isNegative(balance.PromoAmount):
+		return player.Balance, pamError(pam.PAMERRPROMOOVERDRAFT, "promo balance would be negative")
+	}
+	player.Balance = balance
+	return balance, nil
+}
+
+// Player returns the player with id
+func (p *PAM) Player(id pam.PlayerId) (Player, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	player, found := p.players[id]
+	if !found {
+		return Player{}, false
+	}
+	return *player, true
+}
+
+// Session returns the session with token
+func (p *PAM) Session(token pam.SessionToken) (Session, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	session, found := p.sessions[token]
+	if !found {
+		return Session{}, false
+	}
+	return *session, true
+}
+
+// Transactions returns the transactions of a player, in the order they were booked
+func (p *PAM) Transactions(playerID pam.PlayerId) []Transaction {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var transactions []Transaction
+	for _, t := range p.transactions {
+		if t.PlayerID == playerID {
+			transactions = append(transactions, t)
+		}
+	}
+	return transactions
+}
+
+// GameRound returns the game round of a player
+func (p *PAM) GameRound(playerID pam.PlayerId, roundID pam.ProviderRoundId) (pam.GameRound, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	round, found := p.rounds[roundKey{playerID: playerID, roundID: roundID}]
+	if !found {
+		return pam.GameRound{}, false
+	}
+	return *round, true
+}
+
+func pamError(code pam.ErrorCode, msg string) error {
+	return pam.ToValkyrieError(&pam.PamError{Code: code, Message: msg})
+}
+
+// newToken generates a session token of 32 characters
+func newToken() string {
+	token := make([]byte, 16)
+	_, _ = rand.Read(token)
+	return hex.EncodeToString(token)
+}
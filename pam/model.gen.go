@@ -120,6 +120,27 @@ type Country = string
 // Currency ISO 4217 three letter currency code
 type Currency = string
 
+// CurrencyConversion Conversion of the transaction amounts from the currency used by the provider to the currency of the player wallet
+type CurrencyConversion struct {
+	// ProviderBonusAmount Amount in some currency, rounded to 6 decimal places
+	ProviderBonusAmount Amount `json:"providerBonusAmount"`
+
+	// ProviderCashAmount Amount in some currency, rounded to 6 decimal places
+	ProviderCashAmount Amount `json:"providerCashAmount"`
+
+	// ProviderCurrency ISO 4217 three letter currency code
+	ProviderCurrency Currency `json:"providerCurrency"`
+
+	// ProviderPromoAmount Amount in some currency, rounded to 6 decimal places
+	ProviderPromoAmount Amount `json:"providerPromoAmount"`
+
+	// Rate Exchange rate applied, as amount in the wallet currency per unit of the provider currency
+	Rate string `json:"rate"`
+
+	// RateTime A date and time in IS0 8601 format
+	RateTime Timestamp `json:"rateTime"`
+}
+
 // ErrorCode - `PAM_ERR_UNDEFINED` - When you need a generic error.
 // - `PAM_ERR_ACC_NOT_FOUND` - When account of `playerId` is not found.
 // - `PAM_ERR_GAME_NOT_FOUND` - When specified `providerGameId` is not found.
@@ -396,6 +417,9 @@ type SessionResponse struct {
 // SessionToken Player game session identifier
 type SessionToken = string
 
+// SettlementToken Player game session identifier, or the reconciliation token of the provider
+type SettlementToken = string
+
 // StatusCode defines model for StatusCode.
 type StatusCode string
 
@@ -420,9 +444,12 @@ type Transaction struct {
 	CashAmount Amount `json:"cashAmount"`
 
 	// Currency ISO 4217 three letter currency code
-	Currency   Currency   `json:"currency"`
-	IsGameOver *bool      `json:"isGameOver,omitempty"`
-	Jackpots   *[]Jackpot `json:"jackpots,omitempty"`
+	Currency Currency `json:"currency"`
+
+	// CurrencyConversion Conversion of the transaction amounts from the currency used by the provider to the currency of the player wallet
+	CurrencyConversion *CurrencyConversion `json:"currencyConversion,omitempty"`
+	IsGameOver         *bool               `json:"isGameOver,omitempty"`
+	Jackpots           *[]Jackpot          `json:"jackpots,omitempty"`
 
 	// PromoAmount Amount in some currency, rounded to 6 decimal places
 	PromoAmount Amount   `json:"promoAmount"`
@@ -563,8 +590,8 @@ type GetGameRoundParams struct {
 	// Provider Name of the game provider associated with the session
 	Provider Provider `form:"provider" json:"provider"`
 
-	// XPlayerToken Player game session identifier
-	XPlayerToken SessionToken `json:"X-Player-Token"`
+	// XPlayerToken Player game session identifier, or the reconciliation token of the provider, see Reconciliation
+	XPlayerToken SettlementToken `json:"X-Player-Token"`
 
 	// XCorrelationID Header for correlating requests between the services for debugging purposes and request tracing. The value will originate from the game providers that support request identification. Otherwise Valkyrie will generate a value.
 	XCorrelationID CorrelationId `json:"X-Correlation-ID"`
@@ -582,9 +609,10 @@ type GetTransactionsParams struct {
 	Provider              Provider               `form:"provider" json:"provider"`
 	ProviderTransactionId *ProviderTransactionId `form:"providerTransactionId,omitempty" json:"providerTransactionId,omitempty"`
 	ProviderBetRef        *ProviderBetRef        `form:"providerBetRef,omitempty" json:"providerBetRef,omitempty"`
+	ProviderRoundId       *ProviderRoundId       `form:"providerRoundId,omitempty" json:"providerRoundId,omitempty"`
 
-	// XPlayerToken Player game session identifier
-	XPlayerToken SessionToken `json:"X-Player-Token"`
+	// XPlayerToken Player game session identifier, or the reconciliation token of the provider, see Reconciliation
+	XPlayerToken SettlementToken `json:"X-Player-Token"`
 
 	// XCorrelationID Header for correlating requests between the services for debugging purposes and request tracing. The value will originate from the game providers that support request identification. Otherwise Valkyrie will generate a value.
 	XCorrelationID CorrelationId `json:"X-Correlation-ID"`
@@ -601,8 +629,8 @@ type AddTransactionParams struct {
 	// Provider Name of the game provider associated with the session
 	Provider Provider `form:"provider" json:"provider"`
 
-	// XPlayerToken Player game session identifier
-	XPlayerToken SessionToken `json:"X-Player-Token"`
+	// XPlayerToken Player game session identifier, or the reconciliation token of the provider, see Reconciliation
+	XPlayerToken SettlementToken `json:"X-Player-Token"`
 
 	// XCorrelationID Header for correlating requests between the services for debugging purposes and request tracing. The value will originate from the game providers that support request identification. Otherwise Valkyrie will generate a value.
 	XCorrelationID CorrelationId `json:"X-Correlation-ID"`
@@ -0,0 +1,99 @@
+package rg
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// guardingClient checks stakes with a Guard before forwarding them to the PAM
+type guardingClient struct {
+	pam.PamClient
+	guard *Guard
+}
+
+// NewGuardingClient wraps client, rejecting stakes not allowed by guard and recording successful transactions
+func NewGuardingClient(client pam.PamClient, guard *Guard) pam.PamClient {
+	return &guardingClient{PamClient: client, guard: guard}
+}
+
+func (c *guardingClient) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	var req *pam.AddTransactionRequest
+	var reserved *Stake
+	res, err := c.PamClient.AddTransaction(func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		ctx, mapped, mErr := rm(r)
+		if mErr != nil {
+			return ctx, mapped, mErr
+		}
+		req = mapped
+		if stake, isStake := c.stakeOf(mapped); isStake {
+			ok, gErr := c.guard.Check(stake)
+			if gErr != nil {
+				if ctx == nil {
+					ctx = context.Background()
+				}
+				log.Ctx(ctx).Info().Err(gErr).
+					Str("provider", stake.Provider).
+					Str("playerId", stake.PlayerID).
+					Msg("Stake rejected by responsible gaming rules")
+				req = nil
+				return ctx, mapped, gErr
+			}
+			if ok {
+				reserved = &stake
+			}
+		}
+		return ctx, mapped, nil
+	})
+	if err != nil {
+		if reserved != nil && rejected(err) {
+			c.guard.Release(*reserved)
+		}
+		return res, err
+	}
+	if req != nil {
+		c.guard.Record(req.PlayerID, req.Body)
+	}
+	return res, err
+}
+
+// rejected reports whether err is a definite rejection of the transaction by the PAM. Timeouts, transport errors
+// and undefined errors leave it unknown whether the transaction was booked, so the reservation of a stake is kept
+// until it is resent, as is that of a duplicate, which was booked before.
+func rejected(err error) bool {
+	var valkErr pam.ValkyrieError
+	if !errors.As(err, &valkErr) {
+		return false
+	}
+	switch valkErr.ValkErrorCode {
+	case pam.ValkErrUndefined, pam.ValkErrDuplicateTrans:
+		return false
+	}
+	info, found := pam.LookupError(valkErr.ValkErrorCode)
+	return found && !info.Retryable
+}
+
+// stakeOf returns the stake of a transaction, if it is one. Promo stakes are checked without amount, since no
+// cash of the player is at stake.
+func (c *guardingClient) stakeOf(req *pam.AddTransactionRequest) (Stake, bool) {
+	stake := Stake{
+		PlayerID:      req.PlayerID,
+		Provider:      req.Params.Provider,
+		TransactionID: req.Body.ProviderTransactionId,
+		Currency:      req.Body.Currency,
+		Time:          c.guard.now(),
+	}
+	switch req.Body.TransactionType {
+	case pam.WITHDRAW:
+		stake.Amount = decimal.Decimal(req.Body.CashAmount)
+	case pam.PROMOWITHDRAW:
+		stake.Amount = decimal.Zero
+	default:
+		return Stake{}, false
+	}
+	return stake, true
+}
@@ -0,0 +1,261 @@
+package rg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+type transactionPamStub struct {
+	pam.PamClient
+	forwarded []pam.Transaction
+	// during is called while the transaction is in flight
+	during func()
+	err    error
+}
+
+func (s *transactionPamStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	_, req, err := rm(pam.SixDecimalRounder)
+	if err != nil {
+		return nil, err
+	}
+	if s.during != nil {
+		during := s.during
+		s.during = nil
+		during()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.forwarded = append(s.forwarded, req.Body)
+	return &pam.TransactionResult{Balance: &pam.Balance{CashAmount: pam.ZeroAmount}}, nil
+}
+
+func transaction(transactionType pam.TransactionType, amount int64) pam.AddTransactionRequestMapper {
+	return func(_ pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		return context.Background(), &pam.AddTransactionRequest{
+			PlayerID: "p1",
+			Params:   pam.AddTransactionParams{Provider: "Evolution"},
+			Body: pam.AddTransactionJSONRequestBody{
+				TransactionType: transactionType,
+				CashAmount:      pam.Amount(decimal.NewFromInt(amount)),
+				Currency:        "EUR",
+			},
+		}, nil
+	}
+}
+
+func withTransactionID(rm pam.AddTransactionRequestMapper, transactionID string) pam.AddTransactionRequestMapper {
+	return func(r pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
+		ctx, req, err := rm(r)
+		req.Body.ProviderTransactionId = transactionID
+		return ctx, req, err
+	}
+}
+
+func newTestGuard(t *testing.T, now *time.Time, rules ...Rule) *Guard {
+	t.Helper()
+	guard := NewGuard(NewMemoryStore(), rules, 30*time.Minute, 0)
+	guard.now = func() time.Time { return *now }
+	return guard
+}
+
+func TestGuardingClient_LossLimit(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	lossLimit, err := NewLossLimit(decimal.NewFromInt(100), "EUR", 24*time.Hour)
+	require.NoError(t, err)
+	guard := newTestGuard(t, &now, lossLimit)
+	stub := &transactionPamStub{}
+	client := NewGuardingClient(stub, guard)
+
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 80))
+	require.NoError(t, err)
+
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 30))
+	var valkErr pam.ValkyrieError
+	require.True(t, errors.As(err, &valkErr), err)
+	assert.Equal(t, pam.ValkErrOpBetNotAllowed, valkErr.ValkErrorCode)
+	assert.Len(t, stub.forwarded, 1, "rejected stakes are not forwarded to the PAM")
+
+	// payouts reduce the net loss
+	_, err = client.AddTransaction(transaction(pam.DEPOSIT, 20))
+	require.NoError(t, err)
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 30))
+	assert.NoError(t, err)
+
+	// losses older than the period no longer count
+	now = now.Add(25 * time.Hour)
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 100))
+	assert.NoError(t, err)
+
+	events := guard.Events(EventFilter{Type: StakeRejectedEvent})
+	require.Len(t, events, 1)
+	assert.Equal(t, "p1", events[0].PlayerID)
+	assert.Equal(t, "Evolution", events[0].Provider)
+	assert.Contains(t, events[0].Message, "would exceed the loss limit of 100")
+}
+
+func TestGuardingClient_Session(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	sessionTime, err := NewSessionTimeLimit(time.Hour)
+	require.NoError(t, err)
+	realityCheck, err := NewRealityCheck(20 * time.Minute)
+	require.NoError(t, err)
+	guard := newTestGuard(t, &now, sessionTime, realityCheck)
+	client := NewGuardingClient(&transactionPamStub{}, guard)
+
+	// a stake every 10 minutes of an hour long session
+	for i := 0; i < 6; i++ {
+		_, err = client.AddTransaction(transaction(pam.WITHDRAW, 1))
+		require.NoError(t, err)
+		now = now.Add(10 * time.Minute)
+	}
+	checks := guard.Events(EventFilter{Type: RealityCheckEvent})
+	require.Len(t, checks, 2)
+	assert.Equal(t, "session has lasted 20m0s", checks[0].Message)
+	assert.Equal(t, "session has lasted 40m0s", checks[1].Message)
+
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 1))
+	assert.ErrorContains(t, err, "exceeds the session time limit")
+
+	// payouts are not limited
+	_, err = client.AddTransaction(transaction(pam.DEPOSIT, 1))
+	assert.NoError(t, err)
+
+	// rejected stakes do not extend the session, so a break starts a new one
+	now = now.Add(10 * time.Minute)
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 1))
+	assert.Error(t, err)
+	now = now.Add(20 * time.Minute)
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 1))
+	assert.NoError(t, err)
+
+	state, found := guard.Player("p1")
+	require.True(t, found)
+	assert.Equal(t, now, state.SessionStart)
+}
+
+func TestGuardingClient_Reservation(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	lossLimit, err := NewLossLimit(decimal.NewFromInt(100), "EUR", 24*time.Hour)
+	require.NoError(t, err)
+	guard := newTestGuard(t, &now, lossLimit)
+	stub := &transactionPamStub{}
+	client := NewGuardingClient(stub, guard)
+
+	// a concurrent stake sees the stake in flight
+	var concurrentErr error
+	stub.during = func() {
+		_, concurrentErr = client.AddTransaction(transaction(pam.WITHDRAW, 30))
+	}
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 80))
+	require.NoError(t, err)
+	assert.ErrorContains(t, concurrentErr, "would exceed the loss limit of 100")
+
+	// stakes rejected by the PAM are released
+	stub.err = pam.ValkyrieError{ErrMsg: "insufficient funds", ValkErrorCode: pam.ValkErrOpCashOverdraft}
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 20))
+	require.Error(t, err)
+	stub.err = nil
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 20))
+	assert.NoError(t, err)
+
+	state, found := guard.Player("p1")
+	require.True(t, found)
+	require.Len(t, state.Losses, 1)
+	assert.True(t, decimal.NewFromInt(100).Equal(state.Losses[0].NetLoss), state.Losses[0].NetLoss)
+}
+
+func TestGuardingClient_ReservationKept(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"timeout", pam.ValkyrieError{ErrMsg: "timeout", ValkErrorCode: pam.ValkErrTimeout}},
+		{"undefined", pam.ValkyrieError{ErrMsg: "bad gateway", ValkErrorCode: pam.ValkErrUndefined}},
+		{"transport", errors.New("connection reset")},
+		{"duplicate", pam.ValkyrieError{ErrMsg: "duplicate", ValkErrorCode: pam.ValkErrDuplicateTrans}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+			lossLimit, err := NewLossLimit(decimal.NewFromInt(100), "EUR", 24*time.Hour)
+			require.NoError(t, err)
+			guard := newTestGuard(t, &now, lossLimit)
+			stub := &transactionPamStub{err: test.err}
+			client := NewGuardingClient(stub, guard)
+
+			_, err = client.AddTransaction(withTransactionID(transaction(pam.WITHDRAW, 80), "s1"))
+			require.Error(t, err)
+
+			// the stake may have been booked, so it still counts against the limit
+			stub.err = nil
+			_, err = client.AddTransaction(withTransactionID(transaction(pam.WITHDRAW, 30), "s2"))
+			assert.ErrorContains(t, err, "would exceed the loss limit of 100")
+
+			// and is not reserved twice when resent
+			_, err = client.AddTransaction(withTransactionID(transaction(pam.WITHDRAW, 80), "s1"))
+			require.NoError(t, err)
+			state, found := guard.Player("p1")
+			require.True(t, found)
+			require.Len(t, state.Losses, 1)
+			assert.True(t, decimal.NewFromInt(80).Equal(state.Losses[0].NetLoss), state.Losses[0].NetLoss)
+		})
+	}
+}
+
+func TestGuardingClient_Resent(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	lossLimit, err := NewLossLimit(decimal.NewFromInt(100), "EUR", 24*time.Hour)
+	require.NoError(t, err)
+	guard := newTestGuard(t, &now, lossLimit)
+	client := NewGuardingClient(&transactionPamStub{}, guard)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.AddTransaction(withTransactionID(transaction(pam.WITHDRAW, 60), "s1"))
+		require.NoError(t, err, "resent stakes are accepted")
+		_, err = client.AddTransaction(withTransactionID(transaction(pam.DEPOSIT, 20), "d1"))
+		require.NoError(t, err)
+	}
+
+	state, found := guard.Player("p1")
+	require.True(t, found)
+	require.Len(t, state.Losses, 1)
+	assert.True(t, decimal.NewFromInt(40).Equal(state.Losses[0].NetLoss), "resent transactions are counted once, got %s", state.Losses[0].NetLoss)
+}
+
+func TestGuard_Prune(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	lossLimit, err := NewLossLimit(decimal.NewFromInt(100), "EUR", 24*time.Hour)
+	require.NoError(t, err)
+	guard := newTestGuard(t, &now, lossLimit)
+	client := NewGuardingClient(&transactionPamStub{}, guard)
+
+	_, err = client.AddTransaction(withTransactionID(transaction(pam.WITHDRAW, 10), "s1"))
+	require.NoError(t, err)
+
+	// losses within retention are kept
+	now = now.Add(12 * time.Hour)
+	_, err = client.AddTransaction(withTransactionID(transaction(pam.DEPOSIT, 0), "d1"))
+	require.NoError(t, err)
+	_, found := guard.Player("p1")
+	assert.True(t, found)
+
+	now = now.Add(14 * time.Hour)
+	_, err = client.AddTransaction(withTransactionID(transaction(pam.DEPOSIT, 0), "d2"))
+	require.NoError(t, err)
+	_, found = guard.Player("p1")
+	assert.False(t, found, "players without session and losses are dropped")
+
+	// remembered transactions are forgotten after dedupWindow
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+	assert.Empty(t, guard.recorded)
+}
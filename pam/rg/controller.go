@@ -0,0 +1,60 @@
+package rg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EventsResponse responsible gaming events, oldest first
+type EventsResponse struct {
+	Events []Event `json:"events"`
+}
+
+// PlayerResponse the responsible gaming state of a player
+type PlayerResponse struct {
+	PlayerID string `json:"playerId"`
+	PlayerState
+	// SessionDuration how long the current session has lasted, in seconds
+	SessionDuration int64 `json:"sessionDuration"`
+}
+
+// EventsEndpoint returns the events of eventType, or of the "type" query parameter if empty. The events can be
+// filtered using the "playerId" and "since" (RFC 3339) query parameters.
+func EventsEndpoint(guard *Guard, eventType EventType) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := EventFilter{Type: eventType, PlayerID: c.Query("playerId")}
+		if filter.Type == "" {
+			filter.Type = EventType(c.Query("type"))
+		}
+		if since := c.Query("since"); since != "" {
+			t, err := time.Parse(time.RFC3339, since)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fmt.Sprintf("invalid since: %s", err.Error()))
+			}
+			filter.Since = t
+		}
+		return c.JSON(EventsResponse{Events: guard.Events(filter)})
+	}
+}
+
+// PlayerEndpoint returns the state of the player given by the "playerId" path parameter
+func PlayerEndpoint(guard *Guard) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		playerID := c.Params("playerId")
+		state, found := guard.Player(playerID)
+		if !found {
+			return c.Status(fiber.StatusNotFound).JSON(fmt.Sprintf("player '%s' not found", playerID))
+		}
+		var duration time.Duration
+		if guard.now().Sub(state.LastStake) < guard.sessionBreak {
+			duration = state.SessionDuration(guard.now())
+		}
+		return c.JSON(PlayerResponse{
+			PlayerID:        playerID,
+			PlayerState:     state,
+			SessionDuration: int64(duration.Seconds()),
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package rg
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func TestEventsEndpoint(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	realityCheck, err := NewRealityCheck(time.Minute)
+	require.NoError(t, err)
+	sessionTime, err := NewSessionTimeLimit(2 * time.Minute)
+	require.NoError(t, err)
+	guard := newTestGuard(t, &now, realityCheck, sessionTime)
+	client := NewGuardingClient(&transactionPamStub{}, guard)
+	for i := 0; i < 3; i++ {
+		_, _ = client.AddTransaction(transaction(pam.WITHDRAW, 1))
+		now = now.Add(time.Minute)
+	}
+
+	app := fiber.New()
+	app.Get("/events", EventsEndpoint(guard, ""))
+	app.Get("/reality-checks", EventsEndpoint(guard, RealityCheckEvent))
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantCount  int
+	}{
+		{"all events", "/events", http.StatusOK, 2},
+		{"events of type", "/events?type=stake_rejected", http.StatusOK, 1},
+		{"reality checks", "/reality-checks?playerId=p1", http.StatusOK, 1},
+		{"other player", "/reality-checks?playerId=p2", http.StatusOK, 0},
+		{"since", "/events?since=2026-10-19T12:02:00Z", http.StatusOK, 1},
+		{"invalid since", "/events?since=2026-10-19", http.StatusBadRequest, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, test.url, nil))
+			assert.NoError(tt, err)
+			assert.Equal(tt, test.wantStatus, resp.StatusCode)
+			if test.wantStatus == http.StatusOK {
+				var body EventsResponse
+				assert.NoError(tt, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Len(tt, body.Events, test.wantCount)
+			}
+		})
+	}
+}
+
+func TestPlayerEndpoint(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	lossLimit, err := NewLossLimit(decimal.NewFromInt(100), "", 24*time.Hour)
+	require.NoError(t, err)
+	guard := newTestGuard(t, &now, lossLimit)
+	client := NewGuardingClient(&transactionPamStub{}, guard)
+	_, err = client.AddTransaction(transaction(pam.WITHDRAW, 10))
+	require.NoError(t, err)
+	now = now.Add(5 * time.Minute)
+
+	app := fiber.New()
+	app.Get("/players/:playerId", PlayerEndpoint(guard))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/players/p1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var body PlayerResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "p1", body.PlayerID)
+	assert.Equal(t, int64(300), body.SessionDuration)
+	require.Len(t, body.Losses, 1)
+	assert.Equal(t, "10", body.Losses[0].NetLoss.String())
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/players/p2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,28 @@
+// Package rg checks stakes against responsible gaming rules before they are forwarded to the PAM, uniformly for
+// all providers.
+//
+// A Guard wraps the PAM client and evaluates its rules against the state of the player, kept in a local Store,
+// before every stake (WITHDRAW and PROMOWITHDRAW transactions). A stake rejected by a rule never reaches the PAM
+// and fails with ValkErrOpBetNotAllowed, which every provider maps to its native error.
+//
+// The state of a player is built from the transactions booked through Valkyrie only, independent of deposits
+// to the player account:
+//   - the net loss in each currency, the cash amount of stakes minus the cash amount of payouts and cancels,
+//     kept per hour for as long as the longest loss limit period. Stakes are counted when accepted, so that
+//     concurrent stakes cannot all pass a loss limit, and released if the PAM fails them. Transactions resent by
+//     providers with the same provider transaction ID are counted once.
+//   - the session, which starts with the first accepted stake and ends after a break of "session_break"
+//     (30m by default) without accepted stakes
+//
+// Rules are built from configuration by RuleFactory using their "rule". The built in rules are
+//   - "loss_limit", rejecting stakes that would make the net loss over "period" exceed "limit", in "currency"
+//     or in every currency if not set
+//   - "session_time", rejecting stakes once the session has lasted "limit"
+//   - "reality_check", reporting a reality check event every "interval" of a session
+//
+// Players whose session has ended and whose losses are all past the longest loss limit period are dropped.
+//
+// Reality checks and rejected stakes are kept as events. Operators get them from /responsible-gaming/events,
+// or only the reality checks from /responsible-gaming/reality-checks, and the state of a player from
+// /responsible-gaming/players/{playerId}.
+package rg
@@ -0,0 +1,301 @@
+package rg
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	defaultSessionBreak = 30 * time.Minute
+	defaultMaxEvents    = 10000
+	// dedupWindow how long recorded transactions are remembered to ignore resent transactions
+	dedupWindow = 24 * time.Hour
+)
+
+// EventType kind of Event
+type EventType string
+
+const (
+	// RealityCheckEvent a session has lasted another reality check interval
+	RealityCheckEvent EventType = "reality_check"
+	// StakeRejectedEvent a stake was rejected by a rule
+	StakeRejectedEvent EventType = "stake_rejected"
+)
+
+// Event something that happened to a player which should be brought to the attention of the operator
+type Event struct {
+	Type         EventType `json:"type"`
+	Time         time.Time `json:"time"`
+	PlayerID     string    `json:"playerId"`
+	Provider     string    `json:"provider"`
+	SessionStart time.Time `json:"sessionStart"`
+	Message      string    `json:"message"`
+}
+
+// EventFilter selects events, by every field that is set
+type EventFilter struct {
+	Type     EventType
+	PlayerID string
+	Since    time.Time
+}
+
+func (f EventFilter) matches(e Event) bool {
+	return (f.Type == "" || f.Type == e.Type) &&
+		(f.PlayerID == "" || f.PlayerID == e.PlayerID) &&
+		!e.Time.Before(f.Since)
+}
+
+type guardConfig struct {
+	// SessionBreak time without stakes after which a new session starts, default 30m
+	SessionBreak time.Duration `mapstructure:"session_break"`
+	// MaxEvents number of events kept, default 10000
+	MaxEvents int `mapstructure:"max_events"`
+	// Rules evaluated before every stake
+	Rules []map[string]any `mapstructure:"rules"`
+}
+
+type transactionKey struct {
+	playerID, provider, transactionID string
+	transactionType                   pam.TransactionType
+}
+
+// Guard evaluates rules before stakes, and keeps the state of players up to date with their transactions
+type Guard struct {
+	store        Store
+	rules        []Rule
+	sessionBreak time.Duration
+	// retention how long net losses are kept, the longest period of the rules
+	retention time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	events    []Event
+	maxEvents int
+	// recorded when transactions were reserved or recorded, forgotten after dedupWindow
+	recorded map[transactionKey]time.Time
+	pruned   time.Time
+}
+
+// NewGuardFromConfig creates a Guard with the configured rules and players kept in memory, or returns nil if
+// responsible gaming is not configured
+func NewGuardFromConfig(config configs.ResponsibleGamingConf) (*Guard, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+	var gc guardConfig
+	if err := decodeConfig(config, &gc); err != nil {
+		return nil, fmt.Errorf("invalid responsible gaming config: %w", err)
+	}
+	if len(gc.Rules) == 0 {
+		return nil, fmt.Errorf("responsible gaming requires at least one rule")
+	}
+	rules := make([]Rule, 0, len(gc.Rules))
+	for _, rc := range gc.Rules {
+		rule, err := GetRule(RuleArgs{Config: rc})
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return NewGuard(NewMemoryStore(), rules, gc.SessionBreak, gc.MaxEvents), nil
+}
+
+// NewGuard creates a Guard evaluating rules against the players of store. A new session starts after a break of
+// sessionBreak without stakes, and the latest maxEvents events are kept. Defaults are used for zero values.
+func NewGuard(store Store, rules []Rule, sessionBreak time.Duration, maxEvents int) *Guard {
+	if sessionBreak <= 0 {
+		sessionBreak = defaultSessionBreak
+	}
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxEvents
+	}
+	g := &Guard{
+		store:        store,
+		rules:        rules,
+		sessionBreak: sessionBreak,
+		maxEvents:    maxEvents,
+		recorded:     map[transactionKey]time.Time{},
+		now:          time.Now,
+	}
+	for _, rule := range rules {
+		if pr, ok := rule.(periodRule); ok && pr.Period() > g.retention {
+			g.retention = pr.Period()
+		}
+	}
+	return g
+}
+
+// Check evaluates the rules before stake, returning a ValkErrOpBetNotAllowed error if any rule rejects it. The
+// session of the player is only updated by accepted stakes.
+//
+// The amount of an accepted stake is reserved in the net loss of the player together with the evaluation, so that
+// concurrent stakes cannot all pass a loss limit, and Check reports whether it was reserved. A reserved stake that
+// is not booked by the PAM must be released using Release. Stakes already accepted within dedupWindow, resent by
+// the provider, are accepted again without being reserved.
+func (g *Guard) Check(stake Stake) (bool, error) {
+	g.prune(stake.Time)
+	key := transactionKey{playerID: stake.PlayerID, provider: stake.Provider, transactionID: stake.TransactionID, transactionType: pam.WITHDRAW}
+	if !g.markRecorded(key, stake.Time) {
+		return false, nil
+	}
+
+	var events []Event
+	var sessionStart time.Time
+	err := g.store.Update(stake.PlayerID, func(state *PlayerState) error {
+		events = nil
+		state.startStake(stake.Time, g.sessionBreak)
+		sessionStart = state.SessionStart
+		for _, rule := range g.rules {
+			event, err := rule.Evaluate(stake, *state)
+			if err != nil {
+				return err
+			}
+			if event != nil {
+				events = append(events, *event)
+			}
+		}
+		for _, event := range events {
+			if event.Type == RealityCheckEvent {
+				state.LastRealityCheck = stake.Time
+			}
+		}
+		if g.retention > 0 && stake.Amount.IsPositive() {
+			state.addLoss(stake.Time, stake.Currency, stake.Amount, g.retention)
+		}
+		return nil
+	})
+	if err != nil {
+		g.forget(key)
+		g.addEvent(stake, sessionStart, Event{Type: StakeRejectedEvent, Message: err.Error()})
+		return false, pam.ValkyrieError{
+			ErrMsg:        fmt.Sprintf("stake rejected: %s", err.Error()),
+			ValkErrorCode: pam.ValkErrOpBetNotAllowed,
+			OrigError:     err,
+		}
+	}
+	for _, event := range events {
+		g.addEvent(stake, sessionStart, event)
+	}
+	return true, nil
+}
+
+// Release removes the amount of a stake reserved by Check from the net loss of the player, for stakes not booked
+// by the PAM
+func (g *Guard) Release(stake Stake) {
+	g.forget(transactionKey{playerID: stake.PlayerID, provider: stake.Provider, transactionID: stake.TransactionID, transactionType: pam.WITHDRAW})
+	if g.retention == 0 || !stake.Amount.IsPositive() {
+		return
+	}
+	_ = g.store.Update(stake.PlayerID, func(state *PlayerState) error {
+		state.addLoss(stake.Time, stake.Currency, stake.Amount.Neg(), g.retention)
+		return nil
+	})
+}
+
+// Record subtracts a successful payout or cancel from the net loss of the player, while stakes are reserved by
+// Check. Only cash amounts are counted, and transactions already recorded within dedupWindow, resent by the
+// provider, are ignored.
+func (g *Guard) Record(playerID string, transaction pam.Transaction) {
+	t := g.now()
+	g.prune(t)
+	if g.retention == 0 {
+		return
+	}
+	switch transaction.TransactionType {
+	case pam.DEPOSIT, pam.CANCEL:
+	default:
+		return
+	}
+	amount := decimal.Decimal(transaction.CashAmount).Neg()
+	if amount.IsZero() {
+		return
+	}
+	key := transactionKey{playerID: playerID, provider: transaction.Provider, transactionID: transaction.ProviderTransactionId, transactionType: transaction.TransactionType}
+	if !g.markRecorded(key, t) {
+		return
+	}
+	_ = g.store.Update(playerID, func(state *PlayerState) error {
+		state.addLoss(t, transaction.Currency, amount, g.retention)
+		return nil
+	})
+}
+
+// markRecorded remembers a transaction, returning false if it already was. Transactions without ID are never
+// remembered.
+func (g *Guard) markRecorded(key transactionKey, t time.Time) bool {
+	if key.transactionID == "" {
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, found := g.recorded[key]; found {
+		return false
+	}
+	g.recorded[key] = t
+	return true
+}
+
+func (g *Guard) forget(key transactionKey) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.recorded, key)
+}
+
+// prune forgets transactions recorded longer than dedupWindow ago, and players whose session has ended and whose
+// losses are all older than retention, at most once per hour
+func (g *Guard) prune(now time.Time) {
+	g.mu.Lock()
+	if now.Sub(g.pruned) < time.Hour {
+		g.mu.Unlock()
+		return
+	}
+	g.pruned = now
+	for key, recorded := range g.recorded {
+		if now.Sub(recorded) > dedupWindow {
+			delete(g.recorded, key)
+		}
+	}
+	g.mu.Unlock()
+
+	g.store.Prune(func(state PlayerState) bool {
+		return state.expired(now, g.sessionBreak, g.retention)
+	})
+}
+
+// Player returns the state of a player, and whether the player is known
+func (g *Guard) Player(playerID string) (PlayerState, bool) {
+	return g.store.Get(playerID)
+}
+
+// Events returns the kept events matching filter, oldest first
+func (g *Guard) Events(filter EventFilter) []Event {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	events := []Event{}
+	for _, e := range g.events {
+		if filter.matches(e) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (g *Guard) addEvent(stake Stake, sessionStart time.Time, event Event) {
+	event.Time = stake.Time
+	event.PlayerID = stake.PlayerID
+	event.Provider = stake.Provider
+	event.SessionStart = sessionStart
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.events) >= g.maxEvents {
+		g.events = append(g.events[:0], g.events[len(g.events)-g.maxEvents+1:]...)
+	}
+	g.events = append(g.events, event)
+}
@@ -0,0 +1,88 @@
+package rg
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/internal"
+)
+
+// Stake a stake about to be forwarded to the PAM
+type Stake struct {
+	PlayerID string
+	Provider string
+	// TransactionID provider transaction ID of the stake, identifying stakes resent by the provider
+	TransactionID string
+	Currency      string
+	// Amount cash amount of the stake
+	Amount decimal.Decimal
+	Time   time.Time
+}
+
+// Rule decides whether a player may place a stake
+type Rule interface {
+	// Evaluate returns an error if the stake is rejected, or an event to report if the stake is accepted but
+	// something should be brought to the attention of the operator. state includes the stake in the session.
+	Evaluate(stake Stake, state PlayerState) (*Event, error)
+}
+
+// periodRule a rule depending on the net loss over a period
+type periodRule interface {
+	// Period how long the net loss needs to be kept
+	Period() time.Duration
+}
+
+// RuleArgs composes all arguments required to build a rule
+type RuleArgs struct {
+	Config map[string]any
+}
+
+type ruleFactory = internal.AbstractFactory[RuleArgs, Rule]
+
+var (
+	once    sync.Once
+	factory *ruleFactory
+)
+
+// RuleFactory returns a single instance to the rule factory
+func RuleFactory() *ruleFactory {
+	// Make the factory a singleton
+	once.Do(func() {
+		factory = internal.NewAbstractFactory[RuleArgs, Rule]()
+	})
+
+	return factory
+}
+
+// GetRule builds the rule selected by the "rule" of the configuration
+func GetRule(args RuleArgs) (Rule, error) {
+	val, found := args.Config["rule"]
+	if !found {
+		return nil, fmt.Errorf("required responsible gaming field \"rule\" not found")
+	}
+	name, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("responsible gaming field \"rule\" has unknown type %v", val)
+	}
+	rule, err := RuleFactory().Build(name, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build responsible gaming rule: %w", err)
+	}
+	return rule, nil
+}
+
+// decodeConfig decodes config into result, with durations given as strings such as "24h"
+func decodeConfig(config map[string]any, result any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     result,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(config)
+}
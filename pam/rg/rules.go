@@ -0,0 +1,129 @@
+package rg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	RuleFactory().Register("loss_limit", func(args RuleArgs) (Rule, error) {
+		var config lossLimitConfig
+		if err := decodeConfig(args.Config, &config); err != nil {
+			return nil, fmt.Errorf("invalid loss_limit config: %w", err)
+		}
+		return NewLossLimit(decimal.NewFromFloat(config.Limit), config.Currency, config.Period)
+	})
+	RuleFactory().Register("session_time", func(args RuleArgs) (Rule, error) {
+		var config sessionTimeConfig
+		if err := decodeConfig(args.Config, &config); err != nil {
+			return nil, fmt.Errorf("invalid session_time config: %w", err)
+		}
+		return NewSessionTimeLimit(config.Limit)
+	})
+	RuleFactory().Register("reality_check", func(args RuleArgs) (Rule, error) {
+		var config realityCheckConfig
+		if err := decodeConfig(args.Config, &config); err != nil {
+			return nil, fmt.Errorf("invalid reality_check config: %w", err)
+		}
+		return NewRealityCheck(config.Interval)
+	})
+}
+
+type lossLimitConfig struct {
+	// Limit the net loss may not exceed
+	Limit float64 `mapstructure:"limit"`
+	// Currency the limit applies to, every currency if not set
+	Currency string `mapstructure:"currency"`
+	// Period the net loss is summed over, such as 24h
+	Period time.Duration `mapstructure:"period"`
+}
+
+type sessionTimeConfig struct {
+	// Limit how long a session may last
+	Limit time.Duration `mapstructure:"limit"`
+}
+
+type realityCheckConfig struct {
+	// Interval between reality checks during a session
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// LossLimit rejects stakes that would make the net loss over a period exceed a limit
+type LossLimit struct {
+	limit    decimal.Decimal
+	currency string
+	period   time.Duration
+}
+
+// NewLossLimit creates a LossLimit of limit over period, in currency or in every currency if empty
+func NewLossLimit(limit decimal.Decimal, currency string, period time.Duration) (*LossLimit, error) {
+	if !limit.IsPositive() {
+		return nil, fmt.Errorf("loss limit must be positive")
+	}
+	if period < lossGranularity {
+		return nil, fmt.Errorf("loss limit period must be at least %s", lossGranularity)
+	}
+	return &LossLimit{limit: limit, currency: currency, period: period}, nil
+}
+
+func (r *LossLimit) Evaluate(stake Stake, state PlayerState) (*Event, error) {
+	if r.currency != "" && r.currency != stake.Currency {
+		return nil, nil
+	}
+	loss := state.NetLoss(stake.Currency, stake.Time.Add(-r.period)).Add(stake.Amount)
+	if loss.GreaterThan(r.limit) {
+		return nil, fmt.Errorf("net loss of %s %s over %s would exceed the loss limit of %s",
+			loss, stake.Currency, r.period, r.limit)
+	}
+	return nil, nil
+}
+
+func (r *LossLimit) Period() time.Duration {
+	return r.period
+}
+
+// SessionTimeLimit rejects stakes once a session has lasted a limit
+type SessionTimeLimit struct {
+	limit time.Duration
+}
+
+// NewSessionTimeLimit creates a SessionTimeLimit of limit
+func NewSessionTimeLimit(limit time.Duration) (*SessionTimeLimit, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("session time limit must be positive")
+	}
+	return &SessionTimeLimit{limit: limit}, nil
+}
+
+func (r *SessionTimeLimit) Evaluate(stake Stake, state PlayerState) (*Event, error) {
+	if duration := state.SessionDuration(stake.Time); duration >= r.limit {
+		return nil, fmt.Errorf("session of %s exceeds the session time limit of %s", duration, r.limit)
+	}
+	return nil, nil
+}
+
+// RealityCheck reports a reality check event every interval of a session
+type RealityCheck struct {
+	interval time.Duration
+}
+
+// NewRealityCheck creates a RealityCheck every interval
+func NewRealityCheck(interval time.Duration) (*RealityCheck, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("reality check interval must be positive")
+	}
+	return &RealityCheck{interval: interval}, nil
+}
+
+func (r *RealityCheck) Evaluate(stake Stake, state PlayerState) (*Event, error) {
+	if stake.Time.Sub(state.LastRealityCheck) < r.interval {
+		return nil, nil
+	}
+	duration := state.SessionDuration(stake.Time)
+	return &Event{
+		Type:    RealityCheckEvent,
+		Message: fmt.Sprintf("session has lasted %s", duration.Round(time.Second)),
+	}, nil
+}
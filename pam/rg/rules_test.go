@@ -0,0 +1,130 @@
+package rg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		want    Rule
+		wantErr string
+	}{
+		{
+			name:   "loss limit",
+			config: map[string]any{"rule": "loss_limit", "limit": 100, "currency": "EUR", "period": "24h"},
+			want:   &LossLimit{limit: decimal.NewFromFloat(100), currency: "EUR", period: 24 * time.Hour},
+		},
+		{
+			name:   "session time",
+			config: map[string]any{"rule": "session_time", "limit": "4h"},
+			want:   &SessionTimeLimit{limit: 4 * time.Hour},
+		},
+		{
+			name:   "reality check",
+			config: map[string]any{"rule": "reality_check", "interval": "1h"},
+			want:   &RealityCheck{interval: time.Hour},
+		},
+		{"missing rule", map[string]any{"limit": 100}, nil, "\"rule\" not found"},
+		{"unknown rule", map[string]any{"rule": "deposit_limit"}, nil, "'deposit_limit' not found"},
+		{"missing loss limit", map[string]any{"rule": "loss_limit", "period": "24h"}, nil, "must be positive"},
+		{"short loss limit period", map[string]any{"rule": "loss_limit", "limit": 100, "period": "1m"}, nil, "at least 1h"},
+		{"invalid duration", map[string]any{"rule": "session_time", "limit": "long"}, nil, "invalid session_time config"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := GetRule(RuleArgs{Config: test.config})
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, rule)
+		})
+	}
+}
+
+func TestLossLimit(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+	state := PlayerState{}
+	state.addLoss(now.Add(-30*time.Hour), "EUR", decimal.NewFromInt(500), 48*time.Hour)
+	state.addLoss(now.Add(-2*time.Hour), "EUR", decimal.NewFromInt(80), 48*time.Hour)
+	state.addLoss(now.Add(-time.Hour), "EUR", decimal.NewFromInt(-10), 48*time.Hour)
+	state.addLoss(now, "USD", decimal.NewFromInt(1000), 48*time.Hour)
+
+	tests := []struct {
+		name     string
+		currency string
+		stake    Stake
+		rejected bool
+	}{
+		{"within limit", "EUR", Stake{Currency: "EUR", Amount: decimal.NewFromInt(30), Time: now}, false},
+		{"exceeding limit", "EUR", Stake{Currency: "EUR", Amount: decimal.NewFromInt(31), Time: now}, true},
+		{"other currency", "EUR", Stake{Currency: "USD", Amount: decimal.NewFromInt(31), Time: now}, false},
+		{"every currency", "", Stake{Currency: "USD", Amount: decimal.NewFromInt(1), Time: now}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := NewLossLimit(decimal.NewFromInt(100), test.currency, 24*time.Hour)
+			require.NoError(t, err)
+			event, err := rule.Evaluate(test.stake, state)
+			assert.Nil(t, event)
+			assert.Equal(t, test.rejected, err != nil, err)
+		})
+	}
+}
+
+func TestSessionTimeLimit(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+	rule, err := NewSessionTimeLimit(time.Hour)
+	require.NoError(t, err)
+
+	_, err = rule.Evaluate(Stake{Time: now}, PlayerState{SessionStart: now.Add(-59 * time.Minute)})
+	assert.NoError(t, err)
+	_, err = rule.Evaluate(Stake{Time: now}, PlayerState{SessionStart: now.Add(-time.Hour)})
+	assert.ErrorContains(t, err, "exceeds the session time limit of 1h0m0s")
+}
+
+func TestRealityCheck(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+	rule, err := NewRealityCheck(time.Hour)
+	require.NoError(t, err)
+
+	event, err := rule.Evaluate(Stake{Time: now}, PlayerState{SessionStart: now.Add(-2 * time.Hour), LastRealityCheck: now.Add(-30 * time.Minute)})
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+
+	event, err = rule.Evaluate(Stake{Time: now}, PlayerState{SessionStart: now.Add(-2 * time.Hour), LastRealityCheck: now.Add(-time.Hour)})
+	assert.NoError(t, err, "reality checks do not reject stakes")
+	require.NotNil(t, event)
+	assert.Equal(t, RealityCheckEvent, event.Type)
+	assert.Equal(t, "session has lasted 2h0m0s", event.Message)
+}
+
+func TestNewGuardFromConfig(t *testing.T) {
+	guard, err := NewGuardFromConfig(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, guard, "disabled unless configured")
+
+	_, err = NewGuardFromConfig(map[string]any{"session_break": "15m"})
+	assert.ErrorContains(t, err, "at least one rule")
+
+	guard, err = NewGuardFromConfig(map[string]any{
+		"session_break": "15m",
+		"rules": []any{
+			map[string]any{"rule": "loss_limit", "limit": 100, "period": "24h"},
+			map[string]any{"rule": "loss_limit", "limit": 1000.5, "period": "720h"},
+			map[string]any{"rule": "reality_check", "interval": "1h"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, guard.rules, 3)
+	assert.Equal(t, 15*time.Minute, guard.sessionBreak)
+	assert.Equal(t, 720*time.Hour, guard.retention, "net losses are kept for the longest period")
+}
@@ -0,0 +1,163 @@
+package rg
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// lossGranularity the duration of the buckets the net loss of players is kept in
+const lossGranularity = time.Hour
+
+// PlayerState what is known about a player from the transactions booked through Valkyrie
+type PlayerState struct {
+	// SessionStart time of the first stake of the current session
+	SessionStart time.Time `json:"sessionStart"`
+	// LastStake time of the last accepted stake
+	LastStake time.Time `json:"lastStake"`
+	// LastRealityCheck time of the last reality check, or the start of the session if there has been none
+	LastRealityCheck time.Time `json:"lastRealityCheck"`
+	// Losses net loss per currency and hour, oldest first
+	Losses []Loss `json:"losses"`
+}
+
+// Loss net loss in a currency during an hour
+type Loss struct {
+	Hour     time.Time       `json:"hour"`
+	Currency string          `json:"currency"`
+	NetLoss  decimal.Decimal `json:"netLoss"`
+}
+
+// SessionDuration returns how long the current session has lasted at t
+func (s PlayerState) SessionDuration(t time.Time) time.Duration {
+	if s.SessionStart.IsZero() {
+		return 0
+	}
+	return t.Sub(s.SessionStart)
+}
+
+// NetLoss returns the net loss in currency since the hour of since
+func (s PlayerState) NetLoss(currency string, since time.Time) decimal.Decimal {
+	from := since.Truncate(lossGranularity)
+	total := decimal.Zero
+	for _, l := range s.Losses {
+		if l.Currency == currency && !l.Hour.Before(from) {
+			total = total.Add(l.NetLoss)
+		}
+	}
+	return total
+}
+
+// Currencies returns the currencies the player has lost or won in
+func (s PlayerState) Currencies() []string {
+	var currencies []string
+	seen := map[string]bool{}
+	for _, l := range s.Losses {
+		if !seen[l.Currency] {
+			seen[l.Currency] = true
+			currencies = append(currencies, l.Currency)
+		}
+	}
+	return currencies
+}
+
+// startStake starts a new session if the last stake was longer than sessionBreak ago, and marks the stake
+func (s *PlayerState) startStake(t time.Time, sessionBreak time.Duration) {
+	if s.LastStake.IsZero() || t.Sub(s.LastStake) >= sessionBreak {
+		s.SessionStart = t
+		s.LastRealityCheck = t
+	}
+	s.LastStake = t
+}
+
+// addLoss adds amount to the net loss in currency at t, dropping losses older than retention
+func (s *PlayerState) addLoss(t time.Time, currency string, amount decimal.Decimal, retention time.Duration) {
+	hour := t.Truncate(lossGranularity)
+	oldest := hour.Add(-retention)
+	losses := s.Losses[:0]
+	found := false
+	for _, l := range s.Losses {
+		if l.Hour.Before(oldest) {
+			continue
+		}
+		if l.Hour.Equal(hour) && l.Currency == currency {
+			l.NetLoss = l.NetLoss.Add(amount)
+			found = true
+		}
+		losses = append(losses, l)
+	}
+	if !found {
+		losses = append(losses, Loss{Hour: hour, Currency: currency, NetLoss: amount})
+	}
+	s.Losses = losses
+}
+
+// expired returns true if the session has ended at t, and all losses are older than retention
+func (s PlayerState) expired(t time.Time, sessionBreak, retention time.Duration) bool {
+	if t.Sub(s.LastStake) < sessionBreak {
+		return false
+	}
+	oldest := t.Truncate(lossGranularity).Add(-retention)
+	for _, l := range s.Losses {
+		if !l.Hour.Before(oldest) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s PlayerState) copy() PlayerState {
+	s.Losses = append([]Loss(nil), s.Losses...)
+	return s
+}
+
+// Store keeps the state of players
+type Store interface {
+	// Get returns the state of a player, and whether the player is known
+	Get(playerID string) (PlayerState, bool)
+	// Update applies fn to the state of a player, which is only stored if fn succeeds. Updates of a player are
+	// serialized.
+	Update(playerID string, fn func(state *PlayerState) error) error
+	// Prune drops the players for which expired returns true
+	Prune(expired func(state PlayerState) bool)
+}
+
+// MemoryStore keeps the state of players in memory, which is lost on restart
+type MemoryStore struct {
+	mu      sync.Mutex
+	players map[string]PlayerState
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{players: map[string]PlayerState{}}
+}
+
+func (m *MemoryStore) Get(playerID string) (PlayerState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, found := m.players[playerID]
+	return state.copy(), found
+}
+
+func (m *MemoryStore) Update(playerID string, fn func(state *PlayerState) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state := m.players[playerID].copy()
+	if err := fn(&state); err != nil {
+		return err
+	}
+	m.players[playerID] = state
+	return nil
+}
+
+func (m *MemoryStore) Prune(expired func(state PlayerState) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for playerID, state := range m.players {
+		if expired(state) {
+			delete(m.players, playerID)
+		}
+	}
+}
@@ -1,17 +1,16 @@
 package pam
 
 import (
-	"fmt"
-
 	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/money"
 )
 
 var SixDecimalRounder AmountRounder = func(amt Amt) (*Amount, error) {
-	orig := decimal.Decimal(amt)
-	b := orig.Round(6)
-	if !orig.Sub(b).Equal(decimal.Zero) {
-		return nil, fmt.Errorf("rounding will result in lost precision %s -> %s", amt, b)
+	rounded, err := money.Exact(decimal.Decimal(amt), decimalPlaces)
+	if err != nil {
+		return nil, err
 	}
-	res := Amount(b)
+	res := Amount(rounded)
 	return &res, nil
 }
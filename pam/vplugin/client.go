@@ -76,17 +76,14 @@ func (vp *PluginPAM) GetSession(rm pam.GetSessionRequestMapper) (*pam.Session, e
 		return nil, err
 	}
 
-	var resp *pam.SessionResponse
-	err = Pipeline.Execute(ctx, &req,
-		func(pc pipeline.PipelineContext[any]) error {
-			resp = vp.plugin.GetSession(req)
-			return handleErrors(resp.Error, err, resp.Session)
+	return pipeline.Evaluate(ctx, Pipeline, any(&req),
+		func(pc pipeline.PipelineContext[any]) (*pam.Session, error) {
+			resp := vp.plugin.GetSession(req)
+			if err := handleErrors(resp.Error, nil, resp.Session); err != nil {
+				return nil, err
+			}
+			return resp.Session, nil
 		})
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.Session, nil
 }
 
 func (vp *PluginPAM) RefreshSession(rm pam.RefreshSessionRequestMapper) (*pam.Session, error) {
@@ -95,49 +92,46 @@ func (vp *PluginPAM) RefreshSession(rm pam.RefreshSessionRequestMapper) (*pam.Se
 		return nil, err
 	}
 
-	var resp *pam.SessionResponse
-	err = Pipeline.Execute(ctx, &req,
-		func(pc pipeline.PipelineContext[any]) error {
-			resp = vp.plugin.RefreshSession(req)
-			return handleErrors(resp.Error, err, resp.Session)
+	return pipeline.Evaluate(ctx, Pipeline, any(&req),
+		func(pc pipeline.PipelineContext[any]) (*pam.Session, error) {
+			resp := vp.plugin.RefreshSession(req)
+			if err := handleErrors(resp.Error, nil, resp.Session); err != nil {
+				return nil, err
+			}
+			return resp.Session, nil
 		})
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.Session, nil
 }
 
 func (vp *PluginPAM) GetBalance(rm pam.GetBalanceRequestMapper) (*pam.Balance, error) {
 	ctx, req, err := rm()
-
-	var resp *pam.BalanceResponse
-	err = Pipeline.Execute(ctx, &req,
-		func(pc pipeline.PipelineContext[any]) error {
-			resp = vp.plugin.GetBalance(req)
-			return handleErrors(resp.Error, err, resp.Balance)
-		})
 	if err != nil {
 		return nil, err
 	}
 
-	return resp.Balance, nil
+	return pipeline.Evaluate(ctx, Pipeline, any(&req),
+		func(pc pipeline.PipelineContext[any]) (*pam.Balance, error) {
+			resp := vp.plugin.GetBalance(req)
+			if err := handleErrors(resp.Error, nil, resp.Balance); err != nil {
+				return nil, err
+			}
+			return resp.Balance, nil
+		})
 }
 
 func (vp *PluginPAM) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam.Transaction, error) {
 	ctx, req, err := rm()
-
-	var resp *pam.GetTransactionsResponse
-	err = Pipeline.Execute(ctx, &req,
-		func(pc pipeline.PipelineContext[any]) error {
-			resp = vp.plugin.GetTransactions(req)
-			return handleErrors(resp.Error, err, resp.Transactions)
-		})
 	if err != nil {
 		return nil, err
 	}
 
-	return *resp.Transactions, nil
+	return pipeline.Evaluate(ctx, Pipeline, any(&req),
+		func(pc pipeline.PipelineContext[any]) ([]pam.Transaction, error) {
+			resp := vp.plugin.GetTransactions(req)
+			if err := handleErrors(resp.Error, nil, resp.Transactions); err != nil {
+				return nil, err
+			}
+			return *resp.Transactions, nil
+		})
 }
 
 func (vp *PluginPAM) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
@@ -146,17 +140,14 @@ func (vp *PluginPAM) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.Tr
 		return nil, err
 	}
 
-	var resp *pam.AddTransactionResponse
-	err = Pipeline.Execute(ctx, req,
-		func(pc pipeline.PipelineContext[any]) error {
-			resp = vp.plugin.AddTransaction(*req)
-			return handleErrors(resp.Error, err, resp.TransactionResult)
+	return pipeline.Evaluate(ctx, Pipeline, any(req),
+		func(pc pipeline.PipelineContext[any]) (*pam.TransactionResult, error) {
+			resp := vp.plugin.AddTransaction(*req)
+			if err := handleErrors(resp.Error, nil, resp.TransactionResult); err != nil {
+				return nil, err
+			}
+			return resp.TransactionResult, nil
 		})
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.TransactionResult, nil
 }
 
 func (vp *PluginPAM) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRound, error) {
@@ -165,17 +156,14 @@ func (vp *PluginPAM) GetGameRound(rm pam.GetGameRoundRequestMapper) (*pam.GameRo
 		return nil, err
 	}
 
-	var resp *pam.GameRoundResponse
-	err = Pipeline.Execute(ctx, &req,
-		func(pc pipeline.PipelineContext[any]) error {
-			resp = vp.plugin.GetGameRound(req)
-			return handleErrors(resp.Error, err, resp.Gameround)
+	return pipeline.Evaluate(ctx, Pipeline, any(&req),
+		func(pc pipeline.PipelineContext[any]) (*pam.GameRound, error) {
+			resp := vp.plugin.GetGameRound(req)
+			if err := handleErrors(resp.Error, nil, resp.Gameround); err != nil {
+				return nil, err
+			}
+			return resp.Gameround, nil
 		})
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.Gameround, nil
 }
 
 func (vp *PluginPAM) GetTransactionSupplier() pam.TransactionSupplier {
@@ -10,6 +10,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
@@ -138,7 +139,7 @@ func callWithLogging(rpc *rpc.Client, method string, params any, response any) e
 
 	var tt time.Time
 	l.Trace().Func(func(e *zerolog.Event) {
-		e.Interface("request", params)
+		logRedacted(e, "request", params)
 		tt = time.Now()
 	})
 
@@ -149,13 +150,22 @@ func callWithLogging(rpc *rpc.Client, method string, params any, response any) e
 	})
 
 	if err != nil {
-		l.Error().Interface("response", response).Err(err)
+		l.Error().Func(func(e *zerolog.Event) { logRedacted(e, "response", response) }).Err(err).Msg("plugin call failed")
 	} else {
-		l.Trace().Interface("response", response).Msg("plugin called")
+		l.Trace().Func(func(e *zerolog.Event) { logRedacted(e, "response", response) }).Msg("plugin called")
 	}
 	return err
 }
 
+// logRedacted logs v as JSON, with the values of redacted fields replaced
+func logRedacted(e *zerolog.Event, key string, v any) {
+	if body, err := redact.Logging().Value(v); err == nil {
+		e.RawJSON(key, body)
+	} else {
+		e.Str(key, err.Error())
+	}
+}
+
 // wrapError helps comply with the error-less interface of PAM,
 // by wrapping hard errors in pamErrors
 func wrapError(err error) *pam.PamError {
@@ -3,20 +3,30 @@ package caleta
 import (
 	"github.com/shopspring/decimal"
 
+	"github.com/valkyrie-fnd/valkyrie/money"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
-const iMulti = 100000
+// amountPlaces Caleta amounts are integers of amounts multiplied by 100000
+const amountPlaces = 5
 
-var dMulti = decimal.NewFromInt(iMulti)
+// toMoneyAmount converts an amount to a Caleta amount, failing if the amount has more than five decimals or
+// is too large
+func toMoneyAmount(a pam.Amount) (MoneyAmount, error) {
+	units, err := money.ToUnits(decimal.Decimal(a), amountPlaces)
+	return MoneyAmount(units), err
+}
 
-func fromPamAmount(a pam.Amount) *int {
-	f, _ := decimal.Decimal(a).Float64()
-	i := int(f * iMulti)
-	return &i
+// fromPamAmount converts a PAM balance amount to a Caleta amount, rounded down to never report more than the
+// player has
+func fromPamAmount(a pam.Amount) (*MoneyAmount, error) {
+	amt, err := toMoneyAmount(pam.Amount(money.RoundDown.Round(decimal.Decimal(a), amountPlaces)))
+	if err != nil {
+		return nil, err
+	}
+	return &amt, nil
 }
 
-func toPamAmount(i int) pam.Amount {
-	amt := decimal.NewFromInt(int64(i)).Div(dMulti)
-	return pam.Amount(amt)
+func toPamAmount(i MoneyAmount) pam.Amount {
+	return pam.Amount(money.FromUnits(int64(i), amountPlaces))
 }
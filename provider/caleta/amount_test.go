@@ -0,0 +1,64 @@
+package caleta
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func Test_toPamAmount_roundTrip(t *testing.T) {
+	property := func(amt int64) bool {
+		converted, err := toMoneyAmount(toPamAmount(MoneyAmount(amt)))
+		return err == nil && converted == MoneyAmount(amt)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func Test_toMoneyAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   MoneyAmount
+		err    string
+	}{
+		{amount: "3.56", want: 356000},
+		{amount: "0.00001", want: 1},
+		{amount: "1.23456", want: 123456},
+		{amount: "-1", want: -100000},
+		{amount: "0.000001", err: "amount 0.000001 has more than 5 decimals"},
+		{amount: "100000000000000", err: "amount 100000000000000 is too large for units with 5 decimals"},
+	}
+	for _, test := range tests {
+		t.Run(test.amount, func(t *testing.T) {
+			amt, err := toMoneyAmount(pam.Amount(decimal.RequireFromString(test.amount)))
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, amt)
+		})
+	}
+}
+
+func Test_fromPamAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   MoneyAmount
+	}{
+		{amount: "3.56", want: 356000},
+		{amount: "0.00001", want: 1},
+		{amount: "0.123459", want: 12345},
+		{amount: "-0.123459", want: -12345},
+	}
+	for _, test := range tests {
+		t.Run(test.amount, func(t *testing.T) {
+			amt, err := fromPamAmount(pam.Amount(decimal.RequireFromString(test.amount)))
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, *amt)
+		})
+	}
+}
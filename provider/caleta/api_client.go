@@ -125,6 +125,9 @@ func (apiClient *apiClient) getGameRoundRender(ctx context.Context, gameRoundID,
 		URL:     fmt.Sprintf("%s%s", apiClient.url, "/api/game/round"),
 		Headers: map[string]string{},
 		Body:    body,
+		// Documented by Caleta to return the URL of the details of an existing round, so retrying it does not
+		// create anything
+		Idempotent: true,
 	}
 	err := apiClient.headerSigner.sign(body, req.Headers)
 	if err != nil {
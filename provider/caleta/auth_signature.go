@@ -24,6 +24,9 @@ type verifier struct {
 // NewSigner accepts a PEM private rsa key and creates a new signer
 func NewSigner(privateKey []byte) (auth.Signer, error) {
 	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
 
 	pKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
 	if err != nil {
@@ -58,6 +58,11 @@ func Test_Sign(t *testing.T) {
 	assert.Equal(t, string(signature), expectedSig)
 }
 
+func Test_NewSigner_Invalid_Key(t *testing.T) {
+	_, err := NewSigner([]byte("not a key"))
+	assert.EqualError(t, err, "private key is not PEM encoded")
+}
+
 func Test_Sign_Empty_Body(t *testing.T) {
 	sut, err := NewSigner([]byte(testingPrivateKey))
 	assert.NoError(t, err)
@@ -23,6 +23,9 @@ const (
 
 type caletaConf struct {
 	GameLaunchType GameLaunchType `mapstructure:"game_launch_type"`
+	// RoundTransactionLookup fetch round transactions from Caleta before each wallet transaction,
+	// also when the PAM is the transaction supplier. Always done for PROVIDER transaction suppliers.
+	RoundTransactionLookup bool `mapstructure:"round_transaction_lookup"`
 }
 
 // getAuthConf parse provider specific auth configuration
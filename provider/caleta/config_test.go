@@ -43,3 +43,14 @@ func Test_caletaConf_unknown_defaults_static(t *testing.T) {
 	res, _ := getCaletaConf(c)
 	assert.Equal(t, res.GameLaunchType, Static)
 }
+
+func Test_caletaConf_round_transaction_lookup(t *testing.T) {
+	c := configs.ProviderConf{
+		ProviderSpecific: map[string]any{
+			"round_transaction_lookup": true,
+		},
+	}
+	res, _ := getCaletaConf(c)
+	assert.True(t, res.RoundTransactionLookup)
+	assert.Equal(t, res.GameLaunchType, Static)
+}
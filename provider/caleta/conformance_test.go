@@ -0,0 +1,150 @@
+package caleta
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/provider/caleta/auth"
+	"github.com/valkyrie-fnd/valkyrie/provider/providertest"
+)
+
+// conformanceAdapter signs all requests, verifying the signature middleware as well
+type conformanceAdapter struct {
+	signer    auth.Signer
+	publicKey []byte
+}
+
+func (a conformanceAdapter) Config() configs.ProviderConf {
+	return configs.ProviderConf{
+		Name: ProviderName,
+		Auth: map[string]any{
+			"operator_id":      "valkyrie",
+			"verification_key": string(a.publicKey),
+		},
+	}
+}
+
+// Codes are the same for all rollbacks, since Caleta expects rollbacks to always succeed
+func (conformanceAdapter) Codes() map[providertest.Outcome]string {
+	return map[providertest.Outcome]string{
+		providertest.Success:           string(RSOK),
+		providertest.DuplicateBet:      string(RSOK),
+		providertest.InsufficientFunds: string(RSERRORNOTENOUGHMONEY),
+		providertest.ExpiredSession:    string(RSERRORTOKENEXPIRED),
+		providertest.BetNotFound:       string(RSOK),
+		providertest.AlreadySettled:    string(RSOK),
+	}
+}
+
+func (a conformanceAdapter) post(c *providertest.Client, path string, req any) (providertest.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return providertest.Response{}, err
+	}
+	signature, err := a.signer.Sign(body)
+	if err != nil {
+		return providertest.Response{}, err
+	}
+	status, resBody, err := c.Post(path, map[string]string{"X-Auth-Signature": string(signature)}, body)
+	if err != nil {
+		return providertest.Response{}, err
+	}
+	res := providertest.Response{StatusCode: status, Body: resBody}
+
+	var resp struct {
+		Status  Status       `json:"status"`
+		Balance *MoneyAmount `json:"balance"`
+		Token   string       `json:"token"`
+	}
+	if err = json.Unmarshal(resBody, &resp); err != nil {
+		return res, fmt.Errorf("unexpected response with status %d: %s: %w", status, resBody, err)
+	}
+	res.Code, res.Token = string(resp.Status), resp.Token
+	if resp.Balance != nil {
+		balance := decimal.Decimal(toPamAmount(*resp.Balance))
+		res.Balance = &balance
+	}
+	return res, nil
+}
+
+// Authenticate exchanges the token, which responds with the new token only
+func (a conformanceAdapter) Authenticate(c *providertest.Client, s providertest.Session) (providertest.Response, error) {
+	res, err := a.post(c, "/wallet/check", WalletcheckJSONRequestBody{Token: s.Token})
+	if err == nil && res.Token != "" {
+		res.Code = string(RSOK)
+	}
+	return res, err
+}
+
+func (a conformanceAdapter) Balance(c *providertest.Client, s providertest.Session) (providertest.Response, error) {
+	return a.post(c, "/wallet/balance", WalletbalanceJSONRequestBody{
+		GameCode:     s.GameID,
+		RequestUuid:  "request",
+		SupplierUser: s.PlayerID,
+		Token:        s.Token,
+	})
+}
+
+func (a conformanceAdapter) Bet(c *providertest.Client, s providertest.Session, bet providertest.Bet) (providertest.Response, error) {
+	amount, err := toMoneyAmount(pam.Amount(bet.Amount))
+	if err != nil {
+		return providertest.Response{}, err
+	}
+	return a.post(c, "/wallet/bet", WalletbetJSONRequestBody{
+		Amount:          amount,
+		Currency:        Currency(s.Currency),
+		GameCode:        s.GameID,
+		RequestUuid:     "request",
+		Round:           bet.RoundID,
+		SupplierUser:    s.PlayerID,
+		Token:           s.Token,
+		TransactionUuid: bet.TransactionID,
+	})
+}
+
+func (a conformanceAdapter) Win(c *providertest.Client, s providertest.Session, win providertest.Win) (providertest.Response, error) {
+	amount, err := toMoneyAmount(pam.Amount(win.Amount))
+	if err != nil {
+		return providertest.Response{}, err
+	}
+	return a.post(c, "/wallet/win", TransactionwinJSONRequestBody{
+		Amount:                   amount,
+		Currency:                 Currency(s.Currency),
+		GameCode:                 s.GameID,
+		ReferenceTransactionUuid: win.Bet.TransactionID,
+		RequestUuid:              "request",
+		Round:                    win.Bet.RoundID,
+		RoundClosed:              true,
+		SupplierUser:             s.PlayerID,
+		Token:                    s.Token,
+		TransactionUuid:          win.TransactionID,
+	})
+}
+
+func (a conformanceAdapter) Rollback(c *providertest.Client, s providertest.Session, rollback providertest.Rollback) (providertest.Response, error) {
+	return a.post(c, "/wallet/rollback", WalletrollbackJSONRequestBody{
+		GameCode:                 s.GameID,
+		ReferenceTransactionUuid: rollback.Bet.TransactionID,
+		RequestUuid:              "request",
+		Round:                    rollback.Bet.RoundID,
+		Token:                    s.Token,
+		TransactionUuid:          rollback.TransactionID,
+		User:                     &s.PlayerID,
+	})
+}
+
+func TestConformance(t *testing.T) {
+	privateKey, publicKey, err := testutils.GenerateRsaKey()
+	require.NoError(t, err)
+	signer, err := NewSigner(privateKey)
+	require.NoError(t, err)
+
+	providertest.Run(t, conformanceAdapter{signer: signer, publicKey: publicKey})
+}
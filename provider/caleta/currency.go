@@ -0,0 +1,90 @@
+package caleta
+
+import (
+	"sync"
+	"time"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+const (
+	// playCurrencyTTL how long the play currency of a session token is remembered after it was last seen
+	playCurrencyTTL = 24 * time.Hour
+	// maxPlayCurrencies limits the number of remembered session tokens
+	maxPlayCurrencies = 10000
+)
+
+// playCurrencies remembers the currency games were launched with per session token, since Caleta does not send the
+// currency of balance and rollback requests. The play currency is remembered at game launch and from bets and wins,
+// which carry it. Tokens are only remembered in memory, so after a restart balances are reported in the wallet
+// currency until the first bet or win of the session. A nil playCurrencies remembers nothing.
+type playCurrencies struct {
+	mu         sync.Mutex
+	currencies map[string]playCurrency
+	now        func() time.Time
+}
+
+type playCurrency struct {
+	currency string
+	expires  time.Time
+}
+
+func newPlayCurrencies() *playCurrencies {
+	return &playCurrencies{currencies: map[string]playCurrency{}, now: time.Now}
+}
+
+// set remembers currency as the play currency of token
+func (p *playCurrencies) set(token, currency string) {
+	if p == nil || token == "" || currency == "" {
+		return
+	}
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.currencies) >= maxPlayCurrencies {
+		for t, c := range p.currencies {
+			if now.After(c.expires) {
+				delete(p.currencies, t)
+			}
+		}
+		if len(p.currencies) >= maxPlayCurrencies {
+			p.currencies = map[string]playCurrency{}
+		}
+	}
+	p.currencies[token] = playCurrency{currency: currency, expires: now.Add(playCurrencyTTL)}
+}
+
+// get returns the play currency of token, or wallet if none is remembered
+func (p *playCurrencies) get(token, wallet string) string {
+	if p == nil {
+		return wallet
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, found := p.currencies[token]
+	if !found || p.now().After(c.expires) {
+		return wallet
+	}
+	return c.currency
+}
+
+// transfer remembers the play currency of token also for newToken, exchanged for it by Walletcheck
+func (p *playCurrencies) transfer(token, newToken string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	c, found := p.currencies[token]
+	p.mu.Unlock()
+	if found {
+		p.set(newToken, c.currency)
+	}
+}
+
+// stakeCurrency returns the currency stake was played in
+func stakeCurrency(stake *pam.Transaction) string {
+	if stake.CurrencyConversion != nil {
+		return stake.CurrencyConversion.ProviderCurrency
+	}
+	return stake.Currency
+}
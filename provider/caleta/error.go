@@ -8,6 +8,10 @@ import (
 )
 
 func getCErrorStatus(err error) Status {
+	if errors.Is(err, valkhttp.TimeoutError) {
+		return RSERRORTIMEOUT
+	}
+
 	var vErr pam.ValkyrieError
 	if errors.As(err, &vErr) {
 		if status, ok := errCodes[vErr.ValkErrorCode]; ok {
@@ -15,33 +19,54 @@ func getCErrorStatus(err error) Status {
 		}
 	}
 
-	if errors.Is(err, valkhttp.TimeoutError) {
-		return RSERRORTIMEOUT
-	}
-
 	return RSERRORUNKNOWN
 }
 
 var errCodes = map[pam.ValkErrorCode]Status{
-	pam.ValkErrAuth:              RSERRORINVALIDTOKEN,
-	pam.ValkErrOpSessionNotFound: RSERRORINVALIDTOKEN,
-	pam.ValkErrOpSessionExpired:  RSERRORTOKENEXPIRED,
-	pam.ValkErrOpGameNotFound:    RSERRORINVALIDGAME,
-	pam.ValkErrOpRoundNotFound:   RSERRORINVALIDGAME,
-	pam.ValkErrWithdrawCurrency:  RSERRORWRONGCURRENCY,
-	pam.ValkErrOpTransCurrency:   RSERRORWRONGCURRENCY,
-	pam.ValkErrOpCashOverdraft:   RSERRORNOTENOUGHMONEY,
-	pam.ValkErrOpBonusOverdraft:  RSERRORNOTENOUGHMONEY,
-	pam.ValkErrOpPromoOverdraft:  RSERRORNOTENOUGHMONEY,
-	pam.ValkErrOpBetNotAllowed:   RSERRORUSERDISABLED,
-	pam.ValkErrOpTransNotFound:   RSERRORTRANSACTIONDOESNOTEXIST,
-	pam.ValkErrOpCancelExists:    RSERRORTRANSACTIONROLLEDBACK,
-	pam.ValkErrDuplicateTrans:    RSERRORDUPLICATETRANSACTION,
-	pam.ValkErrOpCancelNotFound:  RSOK, // Caleta prefers that Valkyrie just returns OK in this case
-	pam.ValkErrTimeout:           RSERRORTIMEOUT,
+	pam.ValkErrUndefined:           RSERRORUNKNOWN,
+	pam.ValkErrAPISession:          RSERRORINVALIDSIGNATURE,
+	pam.ValkErrAuth:                RSERRORINVALIDTOKEN,
+	pam.ValkErrGetBalance:          RSERRORUNKNOWN,
+	pam.ValkErrStakeValue:          RSERRORWRONGSYNTAX,
+	pam.ValkErrPromoValue:          RSERRORWRONGSYNTAX,
+	pam.ValkErrWithdraw:            RSERRORUNKNOWN,
+	pam.ValkErrWithdrawCurrency:    RSERRORWRONGCURRENCY,
+	pam.ValkErrInterpretBalance:    RSERRORUNKNOWN,
+	pam.ValkErrPayoutValue:         RSERRORWRONGSYNTAX,
+	pam.ValkErrPayoutPromoValue:    RSERRORWRONGSYNTAX,
+	pam.ValkErrDeposit:             RSERRORUNKNOWN,
+	pam.ValkErrRefundValue:         RSERRORWRONGSYNTAX,
+	pam.ValkErrRefundPromoValue:    RSERRORWRONGSYNTAX,
+	pam.ValkErrCancel:              RSERRORUNKNOWN,
+	pam.ValkErrPayoutNegativeStake: RSERRORWRONGSYNTAX,
+	pam.ValkErrPayoutZero:          RSERRORWRONGSYNTAX,
+	pam.ValkErrOpUserNotFound:      RSERRORINVALIDTOKEN,
+	pam.ValkErrOpGameNotFound:      RSERRORINVALIDGAME,
+	pam.ValkErrOpTransNotFound:     RSERRORTRANSACTIONDOESNOTEXIST,
+	pam.ValkErrOpCashOverdraft:     RSERRORNOTENOUGHMONEY,
+	pam.ValkErrOpBonusOverdraft:    RSERRORNOTENOUGHMONEY,
+	pam.ValkErrOpSessionNotFound:   RSERRORINVALIDTOKEN,
+	pam.ValkErrOpSessionExpired:    RSERRORTOKENEXPIRED,
+	pam.ValkErrOpMissingProvider:   RSERRORUNKNOWN,
+	pam.ValkErrOpTransCurrency:     RSERRORWRONGCURRENCY,
+	pam.ValkErrOpNegativeStake:     RSERRORWRONGSYNTAX,
+	pam.ValkErrOpZeroStake:         RSERRORWRONGSYNTAX,
+	pam.ValkErrOpRoundExists:       RSERRORDUPLICATETRANSACTION,
+	pam.ValkErrOpCancelNotFound:    RSOK, // Caleta prefers that Valkyrie just returns OK in this case
+	pam.ValkErrOpCancelExists:      RSERRORTRANSACTIONROLLEDBACK,
+	pam.ValkErrOpCancelNonWithdraw: RSERRORWRONGSYNTAX,
+	pam.ValkErrOpBetNotAllowed:     RSERRORUSERDISABLED,
+	pam.ValkErrAlreadySettled:      RSERRORDUPLICATETRANSACTION,
+	pam.ValkErrBetNotFound:         RSERRORTRANSACTIONDOESNOTEXIST,
+	pam.ValkErrOpAccountNotFound:   RSERRORINVALIDTOKEN,
+	pam.ValkErrOpAPIToken:          RSERRORUNKNOWN,
+	pam.ValkErrReqInput:            RSERRORWRONGSYNTAX,
+	pam.ValkErrOpRoundNotFound:     RSERRORINVALIDGAME,
+	pam.ValkErrDuplicateTrans:      RSERRORDUPLICATETRANSACTION,
+	pam.ValkErrOpPromoOverdraft:    RSERRORNOTENOUGHMONEY,
+	pam.ValkErrTimeout:             RSERRORTIMEOUT,
 }
 
 // errors left:
 // RSERRORBETLIMITEXCEEDED
-// RSERRORWRONGSYNTAX
 // RSERRORWRONGTYPES
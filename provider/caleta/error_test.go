@@ -47,3 +47,7 @@ func Test_getCErrorStatus(t *testing.T) {
 		})
 	}
 }
+
+func Test_errCodes(t *testing.T) {
+	assert.Empty(t, pam.MissingErrorCodes(errCodes), "every ValkErrorCode is mapped")
+}
@@ -68,7 +68,7 @@ func betTransactionMapper(ctx context.Context, r *WalletbetRequestObject, roundT
 			Body: pam.AddTransactionJSONRequestBody{
 				CashAmount:            amt,
 				Currency:              string(r.Body.Currency),
-				IsGameOver:            &r.Body.RoundClosed,
+				IsGameOver:            isRoundClosed(r.Body.RoundClosed, roundTransactions),
 				Provider:              ProviderName,
 				ProviderGameId:        &r.Body.GameCode,
 				ProviderRoundId:       &r.Body.Round,
@@ -78,6 +78,7 @@ func betTransactionMapper(ctx context.Context, r *WalletbetRequestObject, roundT
 				TransactionType:       pam.WITHDRAW,
 				BetCode:               r.Body.Bet,
 				RoundTransactions:     roundTransactionsMapper(roundTransactions),
+				Jackpots:              jackpotContributionMapper(r.Body.TransactionUuid, string(r.Body.Currency), roundTransactions),
 			},
 		}, nil
 	}
@@ -95,7 +96,7 @@ func promoBetTransactionMapper(ctx context.Context, r *WalletbetRequestObject, r
 			Body: pam.AddTransactionJSONRequestBody{
 				PromoAmount:           amt,
 				Currency:              string(r.Body.Currency),
-				IsGameOver:            &r.Body.RoundClosed,
+				IsGameOver:            isRoundClosed(r.Body.RoundClosed, roundTransactions),
 				Provider:              ProviderName,
 				ProviderGameId:        &r.Body.GameCode,
 				ProviderRoundId:       &r.Body.Round,
@@ -105,6 +106,7 @@ func promoBetTransactionMapper(ctx context.Context, r *WalletbetRequestObject, r
 				TransactionType:       pam.PROMOWITHDRAW,
 				BetCode:               r.Body.Bet,
 				RoundTransactions:     roundTransactionsMapper(roundTransactions),
+				Jackpots:              jackpotContributionMapper(r.Body.TransactionUuid, string(r.Body.Currency), roundTransactions),
 			},
 		}, nil
 	}
@@ -123,7 +125,7 @@ func winTransactionMapper(ctx context.Context, r *TransactionwinRequestObject, r
 			Body: pam.AddTransactionJSONRequestBody{
 				CashAmount:            amt,
 				Currency:              string(r.Body.Currency),
-				IsGameOver:            &r.Body.RoundClosed,
+				IsGameOver:            isRoundClosed(r.Body.RoundClosed, roundTransactions),
 				Provider:              ProviderName,
 				ProviderGameId:        &r.Body.GameCode,
 				ProviderRoundId:       &r.Body.Round,
@@ -133,6 +135,7 @@ func winTransactionMapper(ctx context.Context, r *TransactionwinRequestObject, r
 				TransactionType:       pam.DEPOSIT,
 				BetCode:               r.Body.Bet,
 				RoundTransactions:     roundTransactionsMapper(roundTransactions),
+				Jackpots:              jackpotWinMapper(r.Body.JackpotWin, string(r.Body.Currency)),
 			},
 		}, nil
 	}
@@ -150,7 +153,7 @@ func promoWinTransactionMapper(ctx context.Context, r *TransactionwinRequestObje
 			Body: pam.AddTransactionJSONRequestBody{
 				PromoAmount:           amt,
 				Currency:              string(r.Body.Currency),
-				IsGameOver:            &r.Body.RoundClosed,
+				IsGameOver:            isRoundClosed(r.Body.RoundClosed, roundTransactions),
 				Provider:              ProviderName,
 				ProviderGameId:        &r.Body.GameCode,
 				ProviderRoundId:       &r.Body.Round,
@@ -160,12 +163,13 @@ func promoWinTransactionMapper(ctx context.Context, r *TransactionwinRequestObje
 				TransactionType:       pam.PROMODEPOSIT,
 				BetCode:               r.Body.Bet,
 				RoundTransactions:     roundTransactionsMapper(roundTransactions),
+				Jackpots:              jackpotWinMapper(r.Body.JackpotWin, string(r.Body.Currency)),
 			},
 		}, nil
 	}
 }
 
-func cancelTransactionMapper(ctx context.Context, r *WalletrollbackRequestObject, session *pam.Session, tt pam.TransactionType, roundTransactions *[]roundTransaction) pam.AddTransactionRequestMapper {
+func cancelTransactionMapper(ctx context.Context, r *WalletrollbackRequestObject, currency string, tt pam.TransactionType, roundTransactions *[]roundTransaction) pam.AddTransactionRequestMapper {
 	return func(_ pam.AmountRounder) (context.Context, *pam.AddTransactionRequest, error) {
 		return ctx, &pam.AddTransactionRequest{
 			PlayerID: utils.OrZeroValue(r.Body.User),
@@ -175,8 +179,8 @@ func cancelTransactionMapper(ctx context.Context, r *WalletrollbackRequestObject
 				XCorrelationID: r.Body.RequestUuid,
 			},
 			Body: pam.AddTransactionJSONRequestBody{
-				Currency:              session.Currency,
-				IsGameOver:            &r.Body.RoundClosed,
+				Currency:              currency,
+				IsGameOver:            isRoundClosed(r.Body.RoundClosed, roundTransactions),
 				Provider:              ProviderName,
 				ProviderGameId:        &r.Body.GameCode,
 				ProviderRoundId:       &r.Body.Round,
@@ -205,6 +209,67 @@ func getTransactionsMapper(ctx context.Context, r *WalletWinBody) pam.GetTransac
 	}
 }
 
+func rollbackStakeMapper(ctx context.Context, r *WalletRollbackBody) pam.GetTransactionsRequestMapper {
+	return func() (context.Context, pam.GetTransactionsRequest, error) {
+		return ctx, pam.GetTransactionsRequest{
+			PlayerID: utils.OrZeroValue(r.User),
+			Params: pam.GetTransactionsParams{
+				Provider:              ProviderName,
+				XPlayerToken:          r.Token,
+				ProviderTransactionId: &r.ReferenceTransactionUuid,
+				ProviderBetRef:        &r.ReferenceTransactionUuid,
+				XCorrelationID:        r.RequestUuid,
+			},
+		}, nil
+	}
+}
+
+// jackpotContributionMapper maps the jackpot contribution of a bet, which is only known from
+// the round transactions fetched from Caleta
+func jackpotContributionMapper(transactionUUID TransactionUuid, currency string, roundTransactions *[]roundTransaction) *[]pam.Jackpot {
+	if roundTransactions == nil {
+		return nil
+	}
+	for _, t := range *roundTransactions {
+		if t.Payload.TransactionUUID == transactionUUID && t.Payload.JackpotContribution != 0 {
+			return &[]pam.Jackpot{newJackpot(toPamAmount(t.Payload.JackpotContribution), currency)}
+		}
+	}
+	return nil
+}
+
+// jackpotWinMapper maps the part of a win contributed from winning a jackpot
+func jackpotWinMapper(jackpotWin *int, currency string) *[]pam.Jackpot {
+	if jackpotWin == nil || *jackpotWin == 0 {
+		return nil
+	}
+	return &[]pam.Jackpot{newJackpot(toPamAmount(*jackpotWin), currency)}
+}
+
+func newJackpot(amount pam.Amount, currency string) pam.Jackpot {
+	return pam.Jackpot{
+		JackpotAmount: &amount,
+		JackpotBuckets: &[]pam.JackpotBucket{{
+			BucketAmount: &amount,
+			Currency:     &currency,
+		}},
+	}
+}
+
+// isRoundClosed returns if the round is finished, either as signalled by the request or by
+// any of the round transactions known by Caleta
+func isRoundClosed(roundClosed RoundClosed, roundTransactions *[]roundTransaction) *bool {
+	if !roundClosed && roundTransactions != nil {
+		for _, t := range *roundTransactions {
+			if t.Payload.RoundClosed {
+				roundClosed = true
+				break
+			}
+		}
+	}
+	return &roundClosed
+}
+
 func roundTransactionsMapper(roundTransactions *[]roundTransaction) *[]pam.RoundTransaction {
 	if roundTransactions != nil {
 		var roundTx []pam.RoundTransaction
@@ -480,7 +480,7 @@ func Test_cancelTransactionMapper(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, res, err := cancelTransactionMapper(context.TODO(), tt.request, &pam.Session{Currency: "EUR"}, pam.PROMOCANCEL, tt.roundTransactions)(dummyAmtReader)
+			_, res, err := cancelTransactionMapper(context.TODO(), tt.request, "EUR", pam.PROMOCANCEL, tt.roundTransactions)(dummyAmtReader)
 
 			tt.dateCompare(t, tt.want.Body.TransactionDateTime, res.Body.TransactionDateTime)
 
@@ -786,3 +786,50 @@ func Test_roundTransactionsMapper(t *testing.T) {
 		})
 	}
 }
+
+func Test_isRoundClosed(t *testing.T) {
+	tests := []struct {
+		name              string
+		roundClosed       RoundClosed
+		roundTransactions *[]roundTransaction
+		want              bool
+	}{
+		{"open round without round transactions", false, nil, false},
+		{"closed round without round transactions", true, nil, true},
+		{"open round with open round transactions", false, &[]roundTransaction{{Payload: payload{RoundClosed: false}}}, false},
+		{"round closed by round transaction", false, &[]roundTransaction{{Payload: payload{RoundClosed: false}}, {Payload: payload{RoundClosed: true}}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.want, *isRoundClosed(test.roundClosed, test.roundTransactions))
+		})
+	}
+}
+
+func Test_jackpotContributionMapper(t *testing.T) {
+	roundTransactions := &[]roundTransaction{
+		{Payload: payload{TransactionUUID: "bet1", JackpotContribution: 1000}},
+		{Payload: payload{TransactionUUID: "bet2"}},
+	}
+	tests := []struct {
+		name            string
+		transactionUUID TransactionUuid
+		roundTx         *[]roundTransaction
+		want            *[]pam.Jackpot
+	}{
+		{"no round transactions", "bet1", nil, nil},
+		{"bet with contribution", "bet1", roundTransactions, &[]pam.Jackpot{newJackpot(toPamAmount(1000), "EUR")}},
+		{"bet without contribution", "bet2", roundTransactions, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.want, jackpotContributionMapper(test.transactionUUID, "EUR", test.roundTx))
+		})
+	}
+}
+
+func Test_jackpotWinMapper(t *testing.T) {
+	assert.Nil(t, jackpotWinMapper(nil, "EUR"))
+	assert.Nil(t, jackpotWinMapper(testutils.Ptr(0), "EUR"))
+	assert.Equal(t, &[]pam.Jackpot{newJackpot(toPamAmount(500000), "EUR")}, jackpotWinMapper(testutils.Ptr(500000), "EUR"))
+}
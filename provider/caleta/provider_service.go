@@ -17,6 +17,8 @@ type caletaService struct {
 	providerConfig configs.ProviderConf
 	caletaConfig   caletaConf
 	authConfig     AuthConf
+	// playCurrencies remembers the play currency of launched games, nil without currency conversion
+	playCurrencies *playCurrencies
 }
 
 func NewCaletaService(apiClient API, config configs.ProviderConf) (*caletaService, error) {
@@ -50,6 +52,9 @@ func (service *caletaService) GetGameRoundRender(ctx *fiber.Ctx, gameRoundRender
 
 // GameLaunch launches games
 func (service *caletaService) GameLaunch(ctx *fiber.Ctx, g *provider.GameLaunchRequest, h *provider.GameLaunchHeaders) (string, error) {
+	// Caleta does not send the currency of balance requests, so it is remembered for the session
+	service.playCurrencies.set(h.SessionKey, g.Currency)
+
 	switch service.caletaConfig.GameLaunchType {
 	case Static:
 		return service.staticGameLaunch(ctx, g, h)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
@@ -109,6 +110,19 @@ func TestStaticUrlGameLaunch(t *testing.T) {
 	}
 }
 
+func TestGameLaunch_PlayCurrency(t *testing.T) {
+	s, err := NewCaletaService(&mockAPIClient{}, configs.ProviderConf{
+		URL:              "https://staging.the-rgs.com",
+		ProviderSpecific: map[string]any{"game_launch_type": "static"},
+	})
+	require.NoError(t, err)
+	s.playCurrencies = newPlayCurrencies()
+
+	_, err = s.GameLaunch(nil, request, headers)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", s.playCurrencies.get(headers.SessionKey, "SEK"), "the currency of the game is remembered for the session")
+}
+
 func TestRequestingGameLaunch(t *testing.T) {
 	type args struct {
 		req     *provider.GameLaunchRequest
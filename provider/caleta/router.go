@@ -5,8 +5,11 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 	"github.com/valkyrie-fnd/valkyrie/provider"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
 
@@ -15,30 +18,44 @@ const (
 )
 
 func init() {
+	provider.RegisterErrorMapping(ProviderName, provider.NewErrorMapping(errCodes))
+	redact.Register("token")
+	// the play currencies of games launched by the operator router, for the wallet service
+	currencies := newPlayCurrencies()
 	provider.ProviderFactory().
 		Register(ProviderName, func(args provider.ProviderArgs) (*provider.Router, error) {
 
+			caletaConfig, err := getCaletaConf(args.Config)
+			if err != nil {
+				return nil, err
+			}
+
 			var service *WalletService
 
-			// If transaction supplier is PROVIDER, provide a transaction client
-			if args.PamClient.GetTransactionSupplier() == pam.PROVIDER {
+			// If transaction supplier is PROVIDER, or round transactions lookup is enabled, provide a transaction client
+			if args.PamClient.GetTransactionSupplier() == pam.PROVIDER || caletaConfig.RoundTransactionLookup {
 				apiClient, err := NewAPIClient(args.HTTPClient, args.Config)
 				if err != nil {
 					return nil, err
 				}
 				service = NewWalletService(args.PamClient, apiClient)
+				service.RoundTransactionLookup = caletaConfig.RoundTransactionLookup
 			} else {
 				service = NewWalletService(args.PamClient, nil)
 			}
 
+			service.exchange = fx.NewExchange(args.Converter, args.PamClient, ProviderName)
+			service.playCurrencies = currencies
+
 			log.Info().Msgf("Configured for transaction supplier '%s'", args.PamClient.GetTransactionSupplier())
 
 			return NewProviderRouter(args.Config, service)
 		})
 	provider.OperatorFactory().
 		Register(ProviderName, func(args provider.OperatorArgs) (*provider.Router, error) {
-			return NewOperatorRouter(args.Config, args.HTTPClient)
+			return NewOperatorRouter(args.Config, args.HTTPClient, args.Converter, currencies)
 		})
+	simulation.Factory().Register(ProviderName, NewSimulator)
 }
 
 func NewProviderRouter(config configs.ProviderConf, service StrictServerInterface) (*provider.Router, error) {
@@ -78,7 +95,9 @@ func getProviderMiddlewares(auth AuthConf) ([]fiber.Handler, error) {
 	return middlewares, nil
 }
 
-func NewOperatorRouter(config configs.ProviderConf, httpClient valkhttp.HTTPClient) (*provider.Router, error) {
+// NewOperatorRouter creates the operator routes of Caleta. Games launched with a play currency, allowed when
+// converter is set, are remembered in currencies.
+func NewOperatorRouter(config configs.ProviderConf, httpClient valkhttp.HTTPClient, converter *fx.Converter, currencies *playCurrencies) (*provider.Router, error) {
 	apiClient, err := NewAPIClient(httpClient, config)
 	if err != nil {
 		return nil, err
@@ -89,7 +108,11 @@ func NewOperatorRouter(config configs.ProviderConf, httpClient valkhttp.HTTPClie
 		return nil, err
 	}
 
-	controller := provider.NewGameLaunchController(caletaService)
+	if converter != nil {
+		caletaService.playCurrencies = currencies
+	}
+
+	controller := provider.NewGameLaunchController(caletaService).WithConverter(converter)
 	grCtrl := provider.NewGameRoundController(caletaService)
 
 	routes := []provider.Route{
@@ -0,0 +1,136 @@
+package caleta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/provider/caleta/auth"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+// simulatorAuthConf credentials of Caleta when calling Valkyrie
+type simulatorAuthConf struct {
+	// ProviderSigningKey private key of the "verification_key" configured in Valkyrie
+	ProviderSigningKey string `mapstructure:"provider_signing_key"`
+}
+
+// simulator sends wallet requests the way Caleta does, signing the request body
+type simulator struct {
+	client valkhttp.HTTPClient
+	url    string
+	signer auth.Signer
+}
+
+// simulatorResponse fields of the wallet responses used by the simulator
+type simulatorResponse struct {
+	Status Status `json:"status"`
+	Token  string `json:"token"`
+}
+
+// NewSimulator creates a simulator signing requests with the "provider_signing_key" auth configuration
+func NewSimulator(args simulation.Args) (simulation.Simulator, error) {
+	var conf simulatorAuthConf
+	if err := mapstructure.Decode(args.Config.Auth, &conf); err != nil {
+		return nil, err
+	}
+	if conf.ProviderSigningKey == "" {
+		return nil, fmt.Errorf("missing Caleta provider 'provider_signing_key' auth config")
+	}
+	signer, err := NewSigner([]byte(conf.ProviderSigningKey))
+	if err != nil {
+		return nil, err
+	}
+	return &simulator{client: args.HTTPClient, url: args.URL, signer: signer}, nil
+}
+
+// post signs and sends the request. The body is sent as signed, instead of being marshalled again.
+func (s *simulator) post(ctx context.Context, path string, req any) (simulation.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return simulation.Response{}, err
+	}
+	signature, err := s.signer.Sign(body)
+	if err != nil {
+		return simulation.Response{}, err
+	}
+
+	var respBody []byte
+	err = s.client.Post(ctx, &valkhttp.PlainParser, &valkhttp.HTTPRequest{
+		URL: s.url + path,
+		Headers: map[string]string{
+			"Content-Type":     "application/json",
+			"X-Auth-Signature": string(signature),
+		},
+		Body: body,
+	}, &respBody)
+
+	var resp simulatorResponse
+	if jsonErr := json.Unmarshal(respBody, &resp); jsonErr != nil || (resp.Status == "" && resp.Token == "") {
+		if err == nil {
+			err = fmt.Errorf("unexpected response: %s", respBody)
+		}
+		return simulation.Response{}, err
+	}
+	// responses to wallet checks only have a token
+	if resp.Status == "" {
+		resp.Status = RSOK
+	}
+	return simulation.Response{Code: string(resp.Status), OK: resp.Status == RSOK, Token: resp.Token}, nil
+}
+
+func (s *simulator) Authenticate(ctx context.Context, session simulation.Session) (simulation.Response, error) {
+	return s.post(ctx, "/wallet/check", WalletcheckJSONRequestBody{Token: session.Token})
+}
+
+func (s *simulator) Bet(ctx context.Context, session simulation.Session, bet simulation.Bet) (simulation.Response, error) {
+	amount, err := toMoneyAmount(pam.Amount(bet.Amount))
+	if err != nil {
+		return simulation.Response{}, err
+	}
+	return s.post(ctx, "/wallet/bet", WalletbetJSONRequestBody{
+		Amount:          amount,
+		Currency:        Currency(session.Currency),
+		GameCode:        session.GameID,
+		RequestUuid:     bet.TransactionID,
+		Round:           bet.RoundID,
+		SupplierUser:    session.PlayerID,
+		Token:           session.Token,
+		TransactionUuid: bet.TransactionID,
+	})
+}
+
+func (s *simulator) Win(ctx context.Context, session simulation.Session, win simulation.Win) (simulation.Response, error) {
+	amount, err := toMoneyAmount(pam.Amount(win.Amount))
+	if err != nil {
+		return simulation.Response{}, err
+	}
+	return s.post(ctx, "/wallet/win", TransactionwinJSONRequestBody{
+		Amount:                   amount,
+		Currency:                 Currency(session.Currency),
+		GameCode:                 session.GameID,
+		ReferenceTransactionUuid: win.Bet.TransactionID,
+		RequestUuid:              win.TransactionID,
+		Round:                    win.Bet.RoundID,
+		RoundClosed:              true,
+		SupplierUser:             session.PlayerID,
+		Token:                    session.Token,
+		TransactionUuid:          win.TransactionID,
+	})
+}
+
+func (s *simulator) Rollback(ctx context.Context, session simulation.Session, rollback simulation.Rollback) (simulation.Response, error) {
+	return s.post(ctx, "/wallet/rollback", WalletrollbackJSONRequestBody{
+		GameCode:                 session.GameID,
+		ReferenceTransactionUuid: rollback.Bet.TransactionID,
+		RequestUuid:              rollback.TransactionID,
+		Round:                    rollback.Bet.RoundID,
+		Token:                    session.Token,
+		TransactionUuid:          rollback.TransactionID,
+		User:                     &session.PlayerID,
+	})
+}
@@ -6,12 +6,18 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
 type WalletService struct {
 	PamClient pam.PamClient
 	APIClient API
+	// RoundTransactionLookup fetch round transactions also when the PAM is the transaction supplier
+	RoundTransactionLookup bool
+	// exchange converts wallet amounts to the play currency remembered in playCurrencies, nil without conversion
+	exchange       *fx.Exchange
+	playCurrencies *playCurrencies
 }
 
 // NewWalletService creates new Caleta wallet service
@@ -36,15 +42,22 @@ func (s *WalletService) Walletbalance(ctx context.Context, request Walletbalance
 		errStatus := getCErrorStatus(err)
 		return Walletbalance200JSONResponse{Status: errStatus, RequestUuid: request.Body.RequestUuid}, nil
 	}
+	currency := s.playCurrency(request.Body.Token, session.Currency)
 	balance, err := s.PamClient.GetBalance(balanceRequestMapper(ctx, request.Body))
+	if err == nil {
+		err = s.exchange.Balance(ctx, request.Body.Token, request.Body.RequestUuid, currency, balance)
+	}
 	if err != nil {
 		errStatus := getCErrorStatus(err)
 		return Walletbalance200JSONResponse{Status: errStatus, RequestUuid: request.Body.RequestUuid}, nil
 	}
-	amt := fromPamAmount(balance.CashAmount)
+	amt, err := fromPamAmount(balance.CashAmount)
+	if err != nil {
+		return Walletbalance200JSONResponse{Status: getCErrorStatus(err), RequestUuid: request.Body.RequestUuid}, nil
+	}
 	return Walletbalance200JSONResponse{
 		Balance:     amt,
-		Currency:    (*Currency)(&session.Currency),
+		Currency:    (*Currency)(&currency),
 		RequestUuid: request.Body.RequestUuid,
 		Status:      RSOK,
 		User:        &request.Body.SupplierUser,
@@ -68,6 +81,7 @@ func (s *WalletService) Walletcheck(ctx context.Context, request WalletcheckRequ
 		// Walletcheck has no error status in response. Return error instead
 		return nil, err
 	}
+	s.playCurrencies.transfer(request.Body.Token, session.Token)
 	return Walletcheck200JSONResponse{
 		Token: &session.Token,
 	}, nil
@@ -98,21 +112,27 @@ func (s *WalletService) Walletbet(ctx context.Context, request WalletbetRequestO
 	}
 
 	var tranRes *pam.TransactionResult
+	var requestMapper pam.AddTransactionRequestMapper
 	if request.Body.IsFree {
-		tranRes, err = s.PamClient.AddTransaction(promoBetTransactionMapper(ctx, &request, roundTransactions))
+		requestMapper = promoBetTransactionMapper(ctx, &request, roundTransactions)
 	} else {
-		tranRes, err = s.PamClient.AddTransaction(betTransactionMapper(ctx, &request, roundTransactions))
+		requestMapper = betTransactionMapper(ctx, &request, roundTransactions)
 	}
+	currency := s.playedCurrency(request.Body.Token, string(request.Body.Currency), session.Currency)
+	tranRes, err = s.addTransaction(ctx, request.Body.Token, request.Body.RequestUuid, currency, requestMapper)
 	if err != nil {
 		errStatus := getCErrorStatus(err)
 		return Walletbet200JSONResponse{Status: errStatus, RequestUuid: request.Body.RequestUuid}, nil
 	}
 
-	amt := fromPamAmount(tranRes.Balance.CashAmount)
+	amt, err := fromPamAmount(tranRes.Balance.CashAmount)
+	if err != nil {
+		return Walletbet200JSONResponse{Status: getCErrorStatus(err), RequestUuid: request.Body.RequestUuid}, nil
+	}
 	return Walletbet200JSONResponse{
 		Status:      RSOK,
 		Balance:     amt,
-		Currency:    (*Currency)(&session.Currency),
+		Currency:    (*Currency)(&currency),
 		RequestUuid: request.Body.RequestUuid,
 		User:        &request.Body.SupplierUser,
 	}, nil
@@ -162,18 +182,22 @@ func (s *WalletService) Transactionwin(ctx context.Context, request Transactionw
 	} else {
 		requestMapper = winTransactionMapper(ctx, &request, roundTransactions)
 	}
-	tranRes, err = s.PamClient.AddTransaction(requestMapper)
+	currency := s.playedCurrency(request.Body.Token, string(request.Body.Currency), session.Currency)
+	tranRes, err = s.addTransaction(ctx, request.Body.Token, request.Body.RequestUuid, currency, requestMapper)
 	if err != nil {
 		errStatus := getCErrorStatus(err)
 		return Transactionwin200JSONResponse{Status: errStatus, RequestUuid: request.Body.RequestUuid}, nil
 	}
-	amt := fromPamAmount(tranRes.Balance.CashAmount)
+	amt, err := fromPamAmount(tranRes.Balance.CashAmount)
+	if err != nil {
+		return Transactionwin200JSONResponse{Status: getCErrorStatus(err), RequestUuid: request.Body.RequestUuid}, nil
+	}
 	return Transactionwin200JSONResponse{
 		Balance:     amt,
 		Status:      RSOK,
 		User:        &request.Body.SupplierUser,
 		RequestUuid: request.Body.RequestUuid,
-		Currency:    (*Currency)(&session.Currency),
+		Currency:    (*Currency)(&currency),
 	}, nil
 }
 
@@ -201,11 +225,21 @@ func (s *WalletService) Walletrollback(ctx context.Context, request Walletrollba
 		return Walletrollback200JSONResponse{Status: errStatus, RequestUuid: request.Body.RequestUuid}, nil
 	}
 
-	var tranRes *pam.TransactionResult
+	transType := pam.CANCEL
 	if request.Body.IsFree != nil && *request.Body.IsFree {
-		tranRes, err = s.PamClient.AddTransaction(cancelTransactionMapper(ctx, &request, session, pam.PROMOCANCEL, roundTransactions))
+		transType = pam.PROMOCANCEL
+	}
+	currency := session.Currency
+	var tranRes *pam.TransactionResult
+	if stake := s.rollbackStake(ctx, request.Body); stake != nil {
+		// The rollback has no currency, it is made in the currency of the stake at its rate
+		currency = stakeCurrency(stake)
+		tranRes, err = s.PamClient.AddTransaction(s.exchange.Settlement(cancelTransactionMapper(ctx, &request, currency, transType, roundTransactions), stake, true))
+		if err == nil {
+			err = s.exchange.SettlementBalance(ctx, stake, currency, tranRes.Balance)
+		}
 	} else {
-		tranRes, err = s.PamClient.AddTransaction(cancelTransactionMapper(ctx, &request, session, pam.CANCEL, roundTransactions))
+		tranRes, err = s.PamClient.AddTransaction(cancelTransactionMapper(ctx, &request, currency, transType, roundTransactions))
 	}
 	if err != nil {
 		errStatus := getCErrorStatus(err)
@@ -221,30 +255,89 @@ func (s *WalletService) Walletrollback(ctx context.Context, request Walletrollba
 
 	amt := 0
 	if tranRes != nil && tranRes.Balance != nil {
-		amt = *fromPamAmount(tranRes.Balance.CashAmount)
+		if balance, err := fromPamAmount(tranRes.Balance.CashAmount); err == nil {
+			amt = *balance
+		} else {
+			log.Ctx(ctx).Err(err).Msg("Rollback balance not reported")
+		}
 	}
 
 	return Walletrollback200JSONResponse{
 		Status:      RSOK,
 		Balance:     &amt,
-		Currency:    (*Currency)(&session.Currency),
+		Currency:    (*Currency)(&currency),
 		RequestUuid: request.Body.RequestUuid,
 		User:        request.Body.User,
 	}, nil
 }
 
-// getRoundTransactions fetches all transactions linked to a given round (only if PAM transaction supplier is "PROVIDER")
+// playCurrency returns the currency the game of token is played in, when converting currencies, otherwise wallet
+func (s *WalletService) playCurrency(token, wallet string) string {
+	if s.exchange == nil {
+		return wallet
+	}
+	return s.playCurrencies.get(token, wallet)
+}
+
+// playedCurrency returns the currency of a bet or win, remembering it as the play currency of token, when
+// converting currencies, otherwise wallet
+func (s *WalletService) playedCurrency(token, currency, wallet string) string {
+	if s.exchange == nil || currency == "" {
+		return wallet
+	}
+	s.playCurrencies.set(token, currency)
+	return currency
+}
+
+// addTransaction adds the transaction converted to the wallet currency, returning the balance in currency
+func (s *WalletService) addTransaction(ctx context.Context, token, requestID, currency string, mapper pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	tranRes, err := s.PamClient.AddTransaction(s.exchange.Transaction(mapper))
+	if err != nil {
+		return nil, err
+	}
+	return tranRes, s.exchange.Balance(ctx, token, requestID, currency, tranRes.Balance)
+}
+
+// rollbackStake looks up the stake being rolled back when converting currencies, or nil if it is not found, in which
+// case the rollback is made unconverted and rejected by the PAM
+func (s *WalletService) rollbackStake(ctx context.Context, r *WalletRollbackBody) *pam.Transaction {
+	if s.exchange == nil {
+		return nil
+	}
+	transactions, err := s.PamClient.GetTransactions(rollbackStakeMapper(ctx, r))
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Stake of rollback not found")
+		return nil
+	}
+	for i, t := range transactions {
+		if t.TransactionType == pam.WITHDRAW || t.TransactionType == pam.PROMOWITHDRAW {
+			return &transactions[i]
+		}
+	}
+	return nil
+}
+
+// getRoundTransactions fetches all transactions linked to a given round. Required if PAM transaction supplier
+// is "PROVIDER", optional for "OPERATOR" when RoundTransactionLookup is enabled.
 func (s *WalletService) getRoundTransactions(ctx context.Context, transactionSupplier pam.TransactionSupplier, round string) (*[]roundTransaction, error) {
 	var roundTransactions *[]roundTransaction
 
+	switch {
 	// If provider is expected to supply transactions, get the round transactions
-	if transactionSupplier == pam.PROVIDER {
+	case transactionSupplier == pam.PROVIDER:
 		if rounds, err := s.APIClient.getRoundTransactions(ctx, round); err != nil {
 			log.Warn().Msg(fmt.Sprintf("Failed to get round transactions for ID %s, reason: %s", round, err.Error()))
 			return nil, err
 		} else {
 			roundTransactions = rounds.RoundTransactions
 		}
+	// Pre-settlement lookup is best effort, the PAM keeps its own transactions
+	case s.RoundTransactionLookup:
+		if rounds, err := s.APIClient.getRoundTransactions(ctx, round); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("round", round).Msg("Failed to look up round transactions, continuing without")
+		} else {
+			roundTransactions = rounds.RoundTransactions
+		}
 	}
 
 	return roundTransactions, nil
@@ -6,10 +6,13 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
@@ -437,11 +440,83 @@ func Test_Rollback(t *testing.T) {
 	}
 }
 
+type rateSourceStub struct{}
+
+func (rateSourceStub) Rate(_ context.Context, from, to string) (fx.Rate, error) {
+	rates := map[string]string{"EUR/SEK": "11.5", "SEK/EUR": "0.086956521739130435"}
+	return fx.Rate{From: from, To: to, Value: decimal.RequireFromString(rates[from+"/"+to])}, nil
+}
+
+// recordingPamStub records the transactions sent to the PAM
+type recordingPamStub struct {
+	pamStub
+	addTransReq *pam.AddTransactionRequest
+}
+
+func (s *recordingPamStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	_, s.addTransReq, _ = rm(pam.SixDecimalRounder)
+	return s.pamStub.AddTransaction(rm)
+}
+
+func Test_PlayCurrency(t *testing.T) {
+	stub := &recordingPamStub{pamStub: pamStub{
+		sessionFn:        func() (*pam.Session, error) { return &pam.Session{Currency: "SEK"}, nil },
+		refreshSessionFn: func() (*pam.Session, error) { return &pam.Session{Token: "token2", Currency: "SEK"}, nil },
+		balanceFn: func() (*pam.Balance, error) {
+			return &pam.Balance{CashAmount: pam.Amount(decimal.NewFromInt(1150))}, nil
+		},
+		addTransFn: func() (*pam.TransactionResult, error) {
+			return &pam.TransactionResult{Balance: &pam.Balance{CashAmount: pam.Amount(decimal.NewFromInt(1035))}}, nil
+		},
+		getTransFn: func() ([]pam.Transaction, error) {
+			return []pam.Transaction{{
+				TransactionType:    pam.WITHDRAW,
+				Currency:           "SEK",
+				CashAmount:         pam.Amount(decimal.NewFromInt(115)),
+				CurrencyConversion: &pam.CurrencyConversion{ProviderCurrency: "EUR", Rate: "12"},
+			}}, nil
+		},
+		getTransactionSupplierFn: func() pam.TransactionSupplier { return pam.OPERATOR },
+	}}
+	sut := NewWalletService(stub, nil)
+	sut.exchange = fx.NewExchange(fx.NewConverter(rateSourceStub{}), stub, ProviderName)
+	sut.playCurrencies = newPlayCurrencies()
+	// remembered when the game was launched
+	sut.playCurrencies.set("token", "EUR")
+	eur := EUR
+	ctx := context.Background()
+
+	// the play currency is kept when the token is exchanged
+	_, err := sut.Walletcheck(ctx, WalletcheckRequestObject{Body: &WalletCheckBody{Token: "token"}})
+	require.NoError(t, err)
+	balance, err := sut.Walletbalance(ctx, WalletbalanceRequestObject{Body: &WalletBalanceBody{Token: "token2"}})
+	require.NoError(t, err)
+	assert.Equal(t, &eur, balance.(Walletbalance200JSONResponse).Currency)
+	assert.Equal(t, 10000000, *balance.(Walletbalance200JSONResponse).Balance, "balance in the play currency")
+
+	bet, err := sut.Walletbet(ctx, WalletbetRequestObject{Body: &WalletBetBody{Token: "token2", Currency: EUR, Amount: 1000000}})
+	require.NoError(t, err)
+	assert.Equal(t, RSOK, bet.(Walletbet200JSONResponse).Status)
+	assert.Equal(t, &eur, bet.(Walletbet200JSONResponse).Currency)
+	assert.Equal(t, 9000000, *bet.(Walletbet200JSONResponse).Balance)
+	assert.Equal(t, "SEK", stub.addTransReq.Body.Currency)
+	assert.Equal(t, "115", decimal.Decimal(stub.addTransReq.Body.CashAmount).String(), "bet converted to the wallet currency")
+
+	rollback, err := sut.Walletrollback(ctx, WalletrollbackRequestObject{Body: &WalletRollbackBody{Token: "unknown", ReferenceTransactionUuid: "bet1"}})
+	require.NoError(t, err)
+	assert.Equal(t, &eur, rollback.(Walletrollback200JSONResponse).Currency, "rollback in the currency of the stake")
+	assert.Equal(t, 9000000, *rollback.(Walletrollback200JSONResponse).Balance)
+	assert.Equal(t, "SEK", stub.addTransReq.Body.Currency)
+	require.NotNil(t, stub.addTransReq.Body.CurrencyConversion)
+	assert.Equal(t, "12", stub.addTransReq.Body.CurrencyConversion.Rate, "rolled back at the rate of the stake")
+}
+
 func Test_getRoundTransactions(t *testing.T) {
 	testError := fmt.Errorf("error fetching transactions")
 	tests := []struct {
 		name                   string
 		transactionSupplier    pam.TransactionSupplier
+		roundTransactionLookup bool
 		round                  string
 		getRoundTransactionsFn func(ctx context.Context, gameRoundID string) (*transactionResponse, error)
 		want                   *[]roundTransaction
@@ -452,6 +527,29 @@ func Test_getRoundTransactions(t *testing.T) {
 			transactionSupplier: pam.OPERATOR,
 			round:               "909",
 		},
+		{
+			name:                   "Operator transaction supplier with lookup should return round transactions",
+			transactionSupplier:    pam.OPERATOR,
+			roundTransactionLookup: true,
+			round:                  "909",
+			getRoundTransactionsFn: func(ctx context.Context, gameRoundID string) (*transactionResponse, error) {
+				return &transactionResponse{
+					RoundID:           gameRoundID,
+					RoundTransactions: &[]roundTransaction{{RoundID: 909, TxnUUID: "txn-uuid"}},
+				}, nil
+			},
+			want: &[]roundTransaction{{RoundID: 909, TxnUUID: "txn-uuid"}},
+		},
+		{
+			name:                   "Operator transaction supplier with lookup ignores failures",
+			transactionSupplier:    pam.OPERATOR,
+			roundTransactionLookup: true,
+			round:                  "909",
+			getRoundTransactionsFn: func(ctx context.Context, gameRoundID string) (*transactionResponse, error) {
+				return nil, testError
+			},
+			want: nil,
+		},
 		{
 			name:                "Provider transaction should return round transactions",
 			transactionSupplier: pam.PROVIDER,
@@ -502,6 +600,7 @@ func Test_getRoundTransactions(t *testing.T) {
 			pamstub := pamStub{getTransactionSupplierFn: func() pam.TransactionSupplier { return test.transactionSupplier }}
 			api := &mockAPIClient{getRoundTransactionsFn: test.getRoundTransactionsFn}
 			service := NewWalletService(&pamstub, api)
+			service.RoundTransactionLookup = test.roundTransactionLookup
 			resp, err := service.getRoundTransactions(ctx, test.transactionSupplier, test.round)
 			assert.Equal(tt, test.want, resp)
 			assert.Equal(tt, test.wantErr, err)
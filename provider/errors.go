@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// ErrorMapping the native error a provider responds with for each ValkErrorCode
+type ErrorMapping map[pam.ValkErrorCode]string
+
+var (
+	errorMappingsMu sync.RWMutex
+	errorMappings   = map[string]ErrorMapping{}
+)
+
+// RegisterErrorMapping registers the error mapping of a provider, which should map every code of the error catalogue
+func RegisterErrorMapping(name string, mapping ErrorMapping) {
+	errorMappingsMu.Lock()
+	defer errorMappingsMu.Unlock()
+	errorMappings[name] = mapping
+}
+
+// NewErrorMapping returns the error mapping of native errors of any type
+func NewErrorMapping[T any](errCodes map[pam.ValkErrorCode]T) ErrorMapping {
+	mapping := make(ErrorMapping, len(errCodes))
+	for code, native := range errCodes {
+		mapping[code] = fmt.Sprint(native)
+	}
+	return mapping
+}
+
+// GetErrorMapping returns the error mapping registered by a provider
+func GetErrorMapping(name string) (ErrorMapping, bool) {
+	errorMappingsMu.RLock()
+	defer errorMappingsMu.RUnlock()
+	mapping, found := errorMappings[name]
+	return mapping, found
+}
+
+// CheckErrorMapping returns an error if a provider has not registered an error mapping of every ValkErrorCode
+func CheckErrorMapping(name string) error {
+	mapping, found := GetErrorMapping(name)
+	if !found {
+		return fmt.Errorf("provider '%s' has no error mapping", name)
+	}
+	if missing := pam.MissingErrorCodes(mapping); len(missing) > 0 {
+		return fmt.Errorf("provider '%s' does not map error codes %v", name, missing)
+	}
+	return nil
+}
+
+// ErrorCatalogueResponse the error catalogue with the effective error mapping of each provider
+type ErrorCatalogueResponse struct {
+	Codes []pam.ErrorInfo `json:"codes"`
+	// Providers the native error of each provider by ValkErrorCode name
+	Providers map[string]map[string]string `json:"providers"`
+}
+
+// ErrorCatalogueEndpoint returns the error catalogue and the error mappings of the providers
+func ErrorCatalogueEndpoint(providers []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		resp := ErrorCatalogueResponse{Codes: pam.ErrorCatalogue(), Providers: map[string]map[string]string{}}
+		for _, name := range providers {
+			mapping, _ := GetErrorMapping(name)
+			effective := map[string]string{}
+			for code, native := range mapping {
+				effective[code.String()] = native
+			}
+			resp.Providers[name] = effective
+		}
+		return c.JSON(resp)
+	}
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func TestCheckErrorMapping(t *testing.T) {
+	complete := map[pam.ValkErrorCode]int{}
+	for _, info := range pam.ErrorCatalogue() {
+		complete[info.Code] = int(info.Code)
+	}
+	RegisterErrorMapping("complete", NewErrorMapping(complete))
+	RegisterErrorMapping("incomplete", ErrorMapping{pam.ValkErrUndefined: "GENERIC"})
+
+	assert.NoError(t, CheckErrorMapping("complete"))
+	assert.ErrorContains(t, CheckErrorMapping("incomplete"), "does not map error codes [ValkErrAPISession")
+	assert.ErrorContains(t, CheckErrorMapping("unknown"), "has no error mapping")
+}
+
+func TestErrorCatalogueEndpoint(t *testing.T) {
+	RegisterErrorMapping("test", NewErrorMapping(map[pam.ValkErrorCode]int{pam.ValkErrTimeout: 504}))
+	app := fiber.New()
+	app.Get("/errors", ErrorCatalogueEndpoint([]string{"test", "unknown"}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/errors", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body ErrorCatalogueResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Codes, len(pam.ErrorCatalogue()))
+	assert.Equal(t, map[string]map[string]string{
+		"test":    {"ValkErrTimeout": "504"},
+		"unknown": {},
+	}, body.Providers)
+}
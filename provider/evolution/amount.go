@@ -3,9 +3,13 @@ package evolution
 import (
 	"github.com/shopspring/decimal"
 
+	"github.com/valkyrie-fnd/valkyrie/money"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
+// amountPlaces number of decimals of amounts
+const amountPlaces = 6
+
 // Amount alias for use in evolution integration providing
 // primarily custom wire format
 type Amount pam.Amt
@@ -20,12 +24,13 @@ func (a Amount) Equal(b Amount) bool {
 	return decimal.Decimal(a).Equal(decimal.Decimal(b))
 }
 
+// fromPamAmount converts a PAM balance amount, rounded down to never report more than the player has
 func fromPamAmount(a *pam.Amount) Amount {
-	return Amount(a.ToAmt())
+	return Amount(money.RoundDown.Round(decimal.Decimal(*a), amountPlaces))
 }
 
 func (m *Amount) MarshalJSON() ([]byte, error) {
-	return []byte(decimal.Decimal(*m).StringFixed(6)), nil
+	return []byte(decimal.Decimal(*m).StringFixed(amountPlaces)), nil
 }
 
 func (m *Amount) UnmarshalJSON(data []byte) error {
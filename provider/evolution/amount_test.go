@@ -0,0 +1,46 @@
+package evolution
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/quick"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/money"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func TestAmount_roundTrip(t *testing.T) {
+	property := func(units int64) bool {
+		a := Amount(money.FromUnits(units, amountPlaces))
+		bytes, err := json.Marshal(&a)
+		if err != nil {
+			return false
+		}
+		var res Amount
+		return json.Unmarshal(bytes, &res) == nil && res.Equal(a)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func Test_fromPamAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   string
+	}{
+		{amount: "10.1", want: "10.100000"},
+		{amount: "0.000001", want: "0.000001"},
+		{amount: "1.2345678", want: "1.234567"},
+	}
+	for _, test := range tests {
+		t.Run(test.amount, func(t *testing.T) {
+			amount := pam.Amount(decimal.RequireFromString(test.amount))
+			a := fromPamAmount(&amount)
+			bytes, err := json.Marshal(&a)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, string(bytes))
+		})
+	}
+}
@@ -22,3 +22,19 @@ func GetAuthConf(c configs.ProviderConf) (AuthConf, error) {
 	}
 	return auth, nil
 }
+
+// EvoConf Evolution specific configuration from valkyrie config file
+type EvoConf struct {
+	// TipTables ids of tables on which debits are dealer tips rather than bets
+	TipTables []string `mapstructure:"tip_tables"`
+}
+
+// GetEvoConf parse provider specific configuration
+func GetEvoConf(c configs.ProviderConf) (EvoConf, error) {
+	var conf EvoConf
+	err := mapstructure.Decode(c.ProviderSpecific, &conf)
+	if err != nil {
+		return conf, err
+	}
+	return conf, nil
+}
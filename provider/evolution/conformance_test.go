@@ -0,0 +1,95 @@
+package evolution
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/provider/providertest"
+)
+
+const conformanceAPIKey = "conformance-api-key"
+
+type conformanceAdapter struct{}
+
+func (conformanceAdapter) Config() configs.ProviderConf {
+	return configs.ProviderConf{
+		Name: ProviderName,
+		Auth: map[string]any{"api_key": conformanceAPIKey},
+	}
+}
+
+func (conformanceAdapter) Codes() map[providertest.Outcome]string {
+	return map[providertest.Outcome]string{
+		providertest.Success:           StatusOK.code,
+		providertest.DuplicateBet:      StatusOK.code,
+		providertest.InsufficientFunds: StatusInsufficientFunds.code,
+		providertest.ExpiredSession:    StatusInvalidSID.code,
+		providertest.BetNotFound:       StatusBetDoesNotExist.code,
+		providertest.AlreadySettled:    StatusBetAlreadySettled.code,
+	}
+}
+
+func (conformanceAdapter) post(c *providertest.Client, path string, req any) (providertest.Response, error) {
+	var resp StandardResponse
+	res, err := c.PostJSON(fmt.Sprintf("%s?%s=%s", path, apiTokenParamName, conformanceAPIKey), nil, req, &resp)
+	if err != nil {
+		return res, err
+	}
+	balance := decimal.Decimal(resp.Balance)
+	res.Code, res.Balance = resp.Status, &balance
+	return res, nil
+}
+
+func requestBase(s providertest.Session) RequestBase {
+	return RequestBase{SID: s.Token, UserID: s.PlayerID, UUID: "uuid"}
+}
+
+func game(s providertest.Session, roundID string) Game {
+	return Game{ID: roundID, Type: "blackjack", Details: GameDetails{Table: GameTable{ID: s.GameID}}}
+}
+
+func (conformanceAdapter) Authenticate(c *providertest.Client, s providertest.Session) (providertest.Response, error) {
+	var resp CheckResponse
+	res, err := c.PostJSON("/check?"+apiTokenParamName+"="+conformanceAPIKey, nil, &CheckRequest{RequestBase: requestBase(s)}, &resp)
+	res.Code, res.Token = resp.Status, resp.SID
+	return res, err
+}
+
+func (a conformanceAdapter) Balance(c *providertest.Client, s providertest.Session) (providertest.Response, error) {
+	return a.post(c, "/balance", &BalanceRequest{RequestBase: requestBase(s), Currency: s.Currency})
+}
+
+func (a conformanceAdapter) Bet(c *providertest.Client, s providertest.Session, bet providertest.Bet) (providertest.Response, error) {
+	return a.post(c, "/debit", &DebitRequest{
+		RequestBase: requestBase(s),
+		Currency:    s.Currency,
+		Game:        game(s, bet.RoundID),
+		Transaction: Transaction{ID: bet.TransactionID, RefID: bet.TransactionID, Amount: Amount(pam.Amt(bet.Amount))},
+	})
+}
+
+func (a conformanceAdapter) Win(c *providertest.Client, s providertest.Session, win providertest.Win) (providertest.Response, error) {
+	return a.post(c, "/credit", &CreditRequest{
+		RequestBase: requestBase(s),
+		Currency:    s.Currency,
+		Game:        game(s, win.Bet.RoundID),
+		Transaction: Transaction{ID: win.TransactionID, RefID: win.Bet.TransactionID, Amount: Amount(pam.Amt(win.Amount))},
+	})
+}
+
+func (a conformanceAdapter) Rollback(c *providertest.Client, s providertest.Session, rollback providertest.Rollback) (providertest.Response, error) {
+	return a.post(c, "/cancel", &CancelRequest{
+		RequestBase: requestBase(s),
+		Currency:    s.Currency,
+		Game:        game(s, rollback.Bet.RoundID),
+		Transaction: Transaction{ID: rollback.TransactionID, RefID: rollback.Bet.TransactionID, Amount: Amount(pam.Amt(rollback.Bet.Amount))},
+	})
+}
+
+func TestConformance(t *testing.T) {
+	providertest.Run(t, conformanceAdapter{})
+}
@@ -24,6 +24,10 @@ type statusCode struct {
 	httpCode int
 }
 
+func (s statusCode) String() string {
+	return s.code
+}
+
 var (
 	StatusOK                 = statusCode{"OK", http.StatusOK}                         // Success
 	StatusTemporaryError     = statusCode{"TEMPORARY_ERROR", http.StatusOK}            // There is a temporary problem with the game server.
@@ -43,16 +47,48 @@ var retryableErrors = map[statusCode]bool{
 }
 
 var errCodes = map[pam.ValkErrorCode]statusCode{
-	pam.ValkErrUndefined:         StatusUnknownError,
-	pam.ValkErrOpSessionExpired:  StatusInvalidSID,
-	pam.ValkErrOpSessionNotFound: StatusInvalidSID,
-	pam.ValkErrOpCashOverdraft:   StatusInsufficientFunds,
-	pam.ValkErrAuth:              StatusInvalidTokenID,
-	pam.ValkErrOpUserNotFound:    StatusInvalidSID,
-	pam.ValkErrAlreadySettled:    StatusBetAlreadySettled,
-	pam.ValkErrBetNotFound:       StatusBetDoesNotExist,
-	pam.ValkErrOpCancelNotFound:  StatusBetDoesNotExist,
-	pam.ValkErrOpTransNotFound:   StatusBetDoesNotExist,
+	pam.ValkErrUndefined:           StatusUnknownError,
+	pam.ValkErrAPISession:          StatusInvalidTokenID,
+	pam.ValkErrAuth:                StatusInvalidTokenID,
+	pam.ValkErrGetBalance:          StatusUnknownError,
+	pam.ValkErrStakeValue:          StatusInvalidParameter,
+	pam.ValkErrPromoValue:          StatusInvalidParameter,
+	pam.ValkErrWithdraw:            StatusUnknownError,
+	pam.ValkErrWithdrawCurrency:    StatusInvalidParameter,
+	pam.ValkErrInterpretBalance:    StatusUnknownError,
+	pam.ValkErrPayoutValue:         StatusInvalidParameter,
+	pam.ValkErrPayoutPromoValue:    StatusInvalidParameter,
+	pam.ValkErrDeposit:             StatusUnknownError,
+	pam.ValkErrRefundValue:         StatusInvalidParameter,
+	pam.ValkErrRefundPromoValue:    StatusInvalidParameter,
+	pam.ValkErrCancel:              StatusUnknownError,
+	pam.ValkErrPayoutNegativeStake: StatusInvalidParameter,
+	pam.ValkErrPayoutZero:          StatusInvalidParameter,
+	pam.ValkErrOpUserNotFound:      StatusInvalidSID,
+	pam.ValkErrOpGameNotFound:      StatusInvalidParameter,
+	pam.ValkErrOpTransNotFound:     StatusBetDoesNotExist,
+	pam.ValkErrOpCashOverdraft:     StatusInsufficientFunds,
+	pam.ValkErrOpBonusOverdraft:    StatusInsufficientFunds,
+	pam.ValkErrOpSessionNotFound:   StatusInvalidSID,
+	pam.ValkErrOpSessionExpired:    StatusInvalidSID,
+	pam.ValkErrOpMissingProvider:   StatusUnknownError,
+	pam.ValkErrOpTransCurrency:     StatusInvalidParameter,
+	pam.ValkErrOpNegativeStake:     StatusInvalidParameter,
+	pam.ValkErrOpZeroStake:         StatusInvalidParameter,
+	pam.ValkErrOpRoundExists:       StatusInvalidParameter,
+	pam.ValkErrOpCancelNotFound:    StatusBetDoesNotExist,
+	pam.ValkErrOpCancelExists:      StatusBetAlreadySettled,
+	pam.ValkErrOpCancelNonWithdraw: StatusInvalidParameter,
+	pam.ValkErrOpBetNotAllowed:     StatusUnknownError,
+	pam.ValkErrAlreadySettled:      StatusBetAlreadySettled,
+	pam.ValkErrBetNotFound:         StatusBetDoesNotExist,
+	pam.ValkErrOpAccountNotFound:   StatusInvalidSID,
+	pam.ValkErrOpAPIToken:          StatusUnknownError,
+	pam.ValkErrReqInput:            StatusInvalidParameter,
+	pam.ValkErrOpRoundNotFound:     StatusBetDoesNotExist,
+	pam.ValkErrDuplicateTrans:      StatusUnknownError,
+	pam.ValkErrOpPromoOverdraft:    StatusInsufficientFunds,
+	pam.ValkErrTimeout:             StatusTemporaryError,
 }
 
 var httpErrCodes = map[int]statusCode{
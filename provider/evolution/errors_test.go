@@ -56,3 +56,7 @@ func Test_toProviderError(t *testing.T) {
 		})
 	}
 }
+
+func Test_errCodes(t *testing.T) {
+	assert.Empty(t, pam.MissingErrorCodes(errCodes), "every ValkErrorCode is mapped")
+}
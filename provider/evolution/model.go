@@ -69,13 +69,23 @@ type CancelRequest struct {
 }
 
 type PromoTransaction struct {
-	Type            string `json:"type"`
-	ID              string `json:"id"`
-	Amount          Amount `json:"amount"`
-	VoucherID       string `json:"voucherId"`
-	RemainingRounds int    `json:"remainingRounds"`
+	Type            string         `json:"type"`
+	ID              string         `json:"id"`
+	Amount          Amount         `json:"amount"`
+	VoucherID       string         `json:"voucherId"`
+	RemainingRounds int            `json:"remainingRounds"`
+	Jackpots        []PromoJackpot `json:"jackpots,omitempty"`
 }
 
+// PromoJackpot jackpot won, included in promo transactions of type JackpotWin
+type PromoJackpot struct {
+	ID        string `json:"id"`
+	WinAmount Amount `json:"winAmount"`
+}
+
+// JackpotWin promo transaction type for jackpot wins
+const JackpotWin = "JackpotWin"
+
 type PromoPayoutRequest struct {
 	RequestBase
 	Currency         string           `json:"currency" validate:"required,len=3"`
@@ -42,6 +42,11 @@ func (service *WalletService) debitRequestMapper(r DebitRequest, transTime time.
 			return service.ctx, nil, roundingErr
 		}
 
+		var tip *pam.Tip
+		if service.isTip(r.Game) {
+			tip = &pam.Tip{TipAmount: cashAmt}
+		}
+
 		return service.ctx, &pam.AddTransactionRequest{
 			PlayerID: r.UserID,
 			Params: pam.AddTransactionParams{
@@ -50,6 +55,7 @@ func (service *WalletService) debitRequestMapper(r DebitRequest, transTime time.
 				XCorrelationID: r.UUID,
 			},
 			Body: pam.AddTransactionJSONRequestBody{
+				Tip:                   tip,
 				Currency:              r.Currency,
 				CashAmount:            *cashAmt,
 				BonusAmount:           pam.ZeroAmount,
@@ -136,6 +142,11 @@ func (service *WalletService) promoPayoutTransRequestMapper(r PromoPayoutRequest
 			return service.ctx, nil, roundingErr
 		}
 
+		jackpots, roundingErr := jackpotWinMapper(r.PromoTransaction, r.Currency, round)
+		if roundingErr != nil {
+			return service.ctx, nil, roundingErr
+		}
+
 		return service.ctx, &pam.AddTransactionRequest{
 			PlayerID: r.UserID,
 			Params: pam.AddTransactionParams{
@@ -144,6 +155,7 @@ func (service *WalletService) promoPayoutTransRequestMapper(r PromoPayoutRequest
 				XCorrelationID: r.UUID,
 			},
 			Body: pam.AddTransactionJSONRequestBody{
+				Jackpots:              jackpots,
 				Currency:              r.Currency,
 				CashAmount:            *roundedPromoAmount,
 				BonusAmount:           pam.ZeroAmount,
@@ -186,3 +198,35 @@ func (service *WalletService) findTransForCancelRequestMapper(r CancelRequest) p
 		}, nil
 	}
 }
+
+// jackpotWinMapper maps jackpots won in a JackpotWin promo transaction. Each jackpot is mapped to
+// a pam.Jackpot, with the whole win as a single bucket.
+func jackpotWinMapper(t PromoTransaction, currency string, round pam.AmountRounder) (*[]pam.Jackpot, error) {
+	if t.Type != JackpotWin {
+		return nil, nil
+	}
+
+	won := t.Jackpots
+	if len(won) == 0 {
+		// No jackpot details, the whole promo amount is the jackpot win
+		won = []PromoJackpot{{ID: t.ID, WinAmount: t.Amount}}
+	}
+
+	jackpots := make([]pam.Jackpot, 0, len(won))
+	for _, j := range won {
+		amount, err := round(j.WinAmount.toAmt())
+		if err != nil {
+			return nil, err
+		}
+		jackpotID := j.ID
+		jackpots = append(jackpots, pam.Jackpot{
+			JackpotId:     &jackpotID,
+			JackpotAmount: amount,
+			JackpotBuckets: &[]pam.JackpotBucket{{
+				BucketAmount: amount,
+				Currency:     &currency,
+			}},
+		})
+	}
+	return &jackpots, nil
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
+
 	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
 
 	"github.com/stretchr/testify/assert"
@@ -162,6 +164,39 @@ func Test_debitRequestMapper(t *testing.T) {
 	}
 }
 
+func Test_debitRequestMapperTip(t *testing.T) {
+	service := s.WithTipTables("tip_table")
+	tests := []struct {
+		name    string
+		tableID string
+		wantTip *pam.Tip
+	}{
+		{
+			name:    "debit on tip table is mapped as tip",
+			tableID: "tip_table",
+			wantTip: &pam.Tip{TipAmount: testutils.Ptr(testutils.NewFloatAmount(5))},
+		},
+		{
+			name:    "debit on other table is not a tip",
+			tableID: "table_id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := DebitRequest{
+				Currency: "EUR",
+				Game:     Game{Details: GameDetails{Table: GameTable{ID: tt.tableID}}},
+				Transaction: Transaction{
+					Amount: Amount(decimal.NewFromFloat(5)),
+				},
+			}
+			_, res, err := service.debitRequestMapper(req, time.Now())(noRoundingRounder)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTip, res.Body.Tip)
+		})
+	}
+}
+
 func Test_creditTransRequestMapper(t *testing.T) {
 	providerGameID := "table_id"
 	providerRoundID := "evo_round_id_1"
@@ -353,6 +388,66 @@ func Test_promoPayoutTransRequestMapper(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "jackpot win promo payout maps jackpots",
+			args: args{
+				r: PromoPayoutRequest{
+					Currency: "EUR",
+					PromoTransaction: PromoTransaction{
+						Type:   JackpotWin,
+						ID:     "evo_trans_id",
+						Amount: amountFromFloat(150),
+						Jackpots: []PromoJackpot{
+							{ID: "jp1", WinAmount: amountFromFloat(100)},
+							{ID: "jp2", WinAmount: amountFromFloat(50)},
+						},
+					},
+					RequestBase: RequestBase{
+						SID:    "sessXXX",
+						UserID: "player1",
+					},
+					Game: Game{
+						ID: "evo_round_id_1",
+						Details: GameDetails{
+							Table: GameTable{
+								ID: "table_id",
+							},
+						},
+					},
+				},
+			},
+			want: &pam.AddTransactionRequest{
+				PlayerID: "player1",
+				Params: pam.AddTransactionParams{
+					Provider:     ProviderName,
+					XPlayerToken: "sessXXX",
+				},
+				Body: pam.AddTransactionJSONRequestBody{
+					TransactionType:       pam.PROMODEPOSIT,
+					CashAmount:            testutils.NewFloatAmount(150),
+					BonusAmount:           pam.ZeroAmount,
+					PromoAmount:           pam.ZeroAmount,
+					Currency:              "EUR",
+					ProviderTransactionId: "evo_trans_id",
+					ProviderGameId:        &providerGameID,
+					ProviderRoundId:       &providerRoundID,
+					TransactionDateTime:   transTime,
+					Provider:              ProviderName,
+					Jackpots: &[]pam.Jackpot{
+						{
+							JackpotId:      testutils.Ptr("jp1"),
+							JackpotAmount:  testutils.Ptr(testutils.NewFloatAmount(100)),
+							JackpotBuckets: &[]pam.JackpotBucket{{BucketAmount: testutils.Ptr(testutils.NewFloatAmount(100)), Currency: testutils.Ptr("EUR")}},
+						},
+						{
+							JackpotId:      testutils.Ptr("jp2"),
+							JackpotAmount:  testutils.Ptr(testutils.NewFloatAmount(50)),
+							JackpotBuckets: &[]pam.JackpotBucket{{BucketAmount: testutils.Ptr(testutils.NewFloatAmount(50)), Currency: testutils.Ptr("EUR")}},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,89 @@
+package evolution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 500
+)
+
+// OperatorService Evolution specific operator functionality
+type OperatorService interface {
+	GetGameHistory(ctx context.Context, req GameHistoryRequest) (*GameHistoryPage, error)
+	GetTableStates(ctx context.Context) (*TableStateResponse, error)
+}
+
+type OperatorController struct {
+	service OperatorService
+}
+
+func NewOperatorController(service OperatorService) *OperatorController {
+	return &OperatorController{service: service}
+}
+
+// GameHistoryEndpoint Returns a page of game rounds played within the period given by the "from" and
+// "to" query parameters (RFC3339), optionally filtered on "playerId". Paging is controlled using
+// "page" (zero based) and "pageSize".
+func (ctrl *OperatorController) GameHistoryEndpoint(c *fiber.Ctx) error {
+	req, err := parseGameHistoryRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(err.Error())
+	}
+
+	page, err := ctrl.service.GetGameHistory(c.UserContext(), req)
+	if err != nil {
+		return operatorErrorResponse(c, err)
+	}
+	return c.JSON(page)
+}
+
+// TableStateEndpoint Returns the current lobby state of all tables
+func (ctrl *OperatorController) TableStateEndpoint(c *fiber.Ctx) error {
+	tables, err := ctrl.service.GetTableStates(c.UserContext())
+	if err != nil {
+		return operatorErrorResponse(c, err)
+	}
+	return c.JSON(tables)
+}
+
+func parseGameHistoryRequest(c *fiber.Ctx) (GameHistoryRequest, error) {
+	req := GameHistoryRequest{
+		PlayerID: c.Query("playerId"),
+		Page:     c.QueryInt("page", 0),
+		PageSize: c.QueryInt("pageSize", defaultHistoryPageSize),
+	}
+	var err error
+	if req.From, err = time.Parse(time.RFC3339, c.Query("from")); err != nil {
+		return req, fmt.Errorf("invalid from: %w", err)
+	}
+	if req.To, err = time.Parse(time.RFC3339, c.Query("to")); err != nil {
+		return req, fmt.Errorf("invalid to: %w", err)
+	}
+	if !req.To.After(req.From) {
+		return req, errors.New("to must be after from")
+	}
+	if req.Page < 0 {
+		return req, errors.New("page must not be negative")
+	}
+	if req.PageSize < 1 || req.PageSize > maxHistoryPageSize {
+		return req, fmt.Errorf("pageSize must be between 1 and %d", maxHistoryPageSize)
+	}
+	return req, nil
+}
+
+func operatorErrorResponse(c *fiber.Ctx, err error) error {
+	hErr := &valkhttp.HTTPError{}
+	if errors.As(err, hErr) {
+		return c.Status(hErr.Code).SendString(hErr.Error())
+	}
+	return c.Status(fiber.StatusBadGateway).JSON(err.Error())
+}
@@ -0,0 +1,113 @@
+package evolution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+type operatorServiceStub struct {
+	historyFn func(req GameHistoryRequest) (*GameHistoryPage, error)
+	tablesFn  func() (*TableStateResponse, error)
+}
+
+func (s operatorServiceStub) GetGameHistory(_ context.Context, req GameHistoryRequest) (*GameHistoryPage, error) {
+	return s.historyFn(req)
+}
+
+func (s operatorServiceStub) GetTableStates(_ context.Context) (*TableStateResponse, error) {
+	return s.tablesFn()
+}
+
+func TestOperatorController_GameHistoryEndpoint(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		wantStatus     int
+		wantPlayerID   string
+		wantPage       int
+		wantPageSize   int
+		serviceFailure error
+	}{
+		{
+			name:         "valid request with defaults",
+			query:        "from=2023-01-01T00:00:00Z&to=2023-01-02T00:00:00Z",
+			wantStatus:   http.StatusOK,
+			wantPageSize: defaultHistoryPageSize,
+		},
+		{
+			name:         "valid request for player with paging",
+			query:        "from=2023-01-01T00:00:00Z&to=2023-01-02T00:00:00Z&playerId=p1&page=2&pageSize=10",
+			wantStatus:   http.StatusOK,
+			wantPlayerID: "p1",
+			wantPage:     2,
+			wantPageSize: 10,
+		},
+		{
+			name:       "missing period",
+			query:      "playerId=p1",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "period ending before start",
+			query:      "from=2023-01-02T00:00:00Z&to=2023-01-01T00:00:00Z",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too large page size",
+			query:      "from=2023-01-01T00:00:00Z&to=2023-01-02T00:00:00Z&pageSize=501",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "provider error status is forwarded",
+			query:          "from=2023-01-01T00:00:00Z&to=2023-01-02T00:00:00Z",
+			wantStatus:     http.StatusUnauthorized,
+			wantPageSize:   defaultHistoryPageSize,
+			serviceFailure: valkhttp.NewHTTPError(http.StatusUnauthorized, "unauthorized"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			ctrl := NewOperatorController(operatorServiceStub{
+				historyFn: func(req GameHistoryRequest) (*GameHistoryPage, error) {
+					assert.Equal(tt, test.wantPlayerID, req.PlayerID)
+					assert.Equal(tt, test.wantPage, req.Page)
+					assert.Equal(tt, test.wantPageSize, req.PageSize)
+					if test.serviceFailure != nil {
+						return nil, test.serviceFailure
+					}
+					return &GameHistoryPage{Rounds: []GameRound{}}, nil
+				},
+			})
+			app := fiber.New()
+			app.Get("/gamehistory", ctrl.GameHistoryEndpoint)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/gamehistory?"+test.query, nil))
+			assert.NoError(tt, err)
+			assert.Equal(tt, test.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestOperatorController_TableStateEndpoint(t *testing.T) {
+	ctrl := NewOperatorController(operatorServiceStub{
+		tablesFn: func() (*TableStateResponse, error) {
+			return &TableStateResponse{Tables: []TableState{{TableID: "t1", Name: "Blackjack", Open: true, Players: 3}}}, nil
+		},
+	})
+	app := fiber.New()
+	app.Get("/tables", ctrl.TableStateEndpoint)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/tables", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"tables":[{"tableId":"t1","name":"Blackjack","gameType":"","open":true,"players":3,"betLimits":null}]}`, string(body))
+}
@@ -0,0 +1,111 @@
+package evolution
+
+import "time"
+
+// GameHistoryResponse Evolution game history API response
+type GameHistoryResponse struct {
+	Data []GameHistoryDay `json:"data"`
+}
+
+type GameHistoryDay struct {
+	Date  string            `json:"date"`
+	Games []GameHistoryGame `json:"games"`
+}
+
+type GameHistoryGame struct {
+	ID           string                   `json:"id"`
+	StartedAt    time.Time                `json:"startedAt"`
+	SettledAt    *time.Time               `json:"settledAt,omitempty"`
+	Status       string                   `json:"status"`
+	GameType     string                   `json:"gameType"`
+	Table        GameHistoryTable         `json:"table"`
+	Currency     string                   `json:"currency"`
+	Participants []GameHistoryParticipant `json:"participants"`
+	Wager        Amount                   `json:"wager"`
+	Payout       Amount                   `json:"payout"`
+}
+
+type GameHistoryTable struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type GameHistoryParticipant struct {
+	PlayerID     string           `json:"playerId"`
+	SessionID    string           `json:"sessionId"`
+	PlayerGameID string           `json:"playerGameId"`
+	Currency     string           `json:"currency"`
+	Bets         []GameHistoryBet `json:"bets"`
+}
+
+type GameHistoryBet struct {
+	Code          string    `json:"code"`
+	Stake         Amount    `json:"stake"`
+	Payout        Amount    `json:"payout"`
+	PlacedOn      time.Time `json:"placedOn"`
+	TransactionID string    `json:"transactionId"`
+}
+
+// LobbyStateResponse Evolution lobby state API response, tables are keyed by table id
+type LobbyStateResponse struct {
+	Tables map[string]LobbyTable `json:"tables"`
+}
+
+type LobbyTable struct {
+	Name      string                   `json:"name"`
+	GameType  string                   `json:"gameType"`
+	Open      bool                     `json:"open"`
+	Players   int                      `json:"players"`
+	BetLimits map[string]LobbyBetLimit `json:"betLimits"`
+}
+
+type LobbyBetLimit struct {
+	Symbol string `json:"symbol"`
+	Min    Amount `json:"min"`
+	Max    Amount `json:"max"`
+}
+
+// GameHistoryRequest operator request for game rounds within a period, optionally for a single player
+type GameHistoryRequest struct {
+	From     time.Time
+	To       time.Time
+	PlayerID string
+	Page     int
+	PageSize int
+}
+
+// GameHistoryPage normalised page of game rounds returned to the operator
+type GameHistoryPage struct {
+	Rounds   []GameRound `json:"rounds"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+	Total    int         `json:"total"`
+}
+
+type GameRound struct {
+	GameRoundID string     `json:"gameRoundId"`
+	GameType    string     `json:"gameType"`
+	TableID     string     `json:"tableId"`
+	TableName   string     `json:"tableName"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"startedAt"`
+	SettledAt   *time.Time `json:"settledAt,omitempty"`
+	Currency    string     `json:"currency"`
+	Players     []string   `json:"players"`
+	Stake       Amount     `json:"stake"`
+	Payout      Amount     `json:"payout"`
+}
+
+// TableState normalised lobby table state returned to the operator
+type TableState struct {
+	TableID   string                   `json:"tableId"`
+	Name      string                   `json:"name"`
+	GameType  string                   `json:"gameType"`
+	Open      bool                     `json:"open"`
+	Players   int                      `json:"players"`
+	BetLimits map[string]LobbyBetLimit `json:"betLimits"`
+}
+
+type TableStateResponse struct {
+	Tables []TableState `json:"tables"`
+}
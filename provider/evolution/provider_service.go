@@ -5,8 +5,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 
 	"github.com/valkyrie-fnd/valkyrie/ops"
 
@@ -66,24 +69,17 @@ func (service EvoService) GameLaunch(ctx *fiber.Ctx, g *provider.GameLaunchReque
 
 func (service EvoService) GetGameRoundRender(ctx *fiber.Ctx, req provider.GameRoundRenderRequest) (int, error) {
 	renderURL := fmt.Sprintf("%s/api/render/v1/details", service.Conf.URL)
-	encodedAuth := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", service.Auth.CasinoKey, service.Auth.CasinoToken)))
 	r := &valkhttp.HTTPRequest{
-		URL:   renderURL,
-		Query: map[string]string{"gameId": req.GameRoundID},
-		Headers: map[string]string{
-			"Authorization": fmt.Sprintf("Basic %s", encodedAuth),
-		},
+		URL:      renderURL,
+		Query:    map[string]string{"gameId": req.GameRoundID},
+		Headers:  service.casinoAuthHeaders(),
+		StreamTo: ctx.Response().BodyWriter(),
 	}
-	var resp []byte
-	err := service.Client.Get(ctx.UserContext(), &valkhttp.PlainParser, r, &resp)
 	ctx.Set(fiber.HeaderContentType, fiber.MIMETextHTML)
+	err := service.Client.Get(ctx.UserContext(), &valkhttp.PlainParser, r, nil)
 	if err != nil {
 		return fiber.StatusBadRequest, err
 	}
-	_, err = ctx.Response().BodyWriter().Write(resp)
-	if err != nil {
-		return fiber.StatusInternalServerError, err
-	}
 	return fiber.StatusOK, nil
 }
 
@@ -102,3 +98,117 @@ func (service EvoService) makeAuthCall(ctx context.Context, request UserAuthenti
 
 	return resp, nil
 }
+
+// GetGameHistory fetches the game rounds played within the requested period from the
+// Evolution game history API, optionally filtered on a single player. Evolution returns the
+// whole period at once, so paging is applied on the normalised rounds.
+func (service EvoService) GetGameHistory(ctx context.Context, req GameHistoryRequest) (*GameHistoryPage, error) {
+	historyURL := fmt.Sprintf("%s/api/gamehistory/v1/casino/games", service.Conf.URL)
+	r := &valkhttp.HTTPRequest{
+		URL: historyURL,
+		Query: map[string]string{
+			"startDate": req.From.UTC().Format(time.RFC3339),
+			"endDate":   req.To.UTC().Format(time.RFC3339),
+		},
+		Headers: service.casinoAuthHeaders(),
+	}
+	resp := &GameHistoryResponse{}
+	if err := service.Client.Get(ctx, &valkhttp.JSONParser, r, resp); err != nil {
+		return nil, fmt.Errorf("failed calling evo game history: %w", err)
+	}
+
+	rounds := make([]GameRound, 0)
+	for _, day := range resp.Data {
+		for _, game := range day.Games {
+			if round, ok := toGameRound(game, req.PlayerID); ok {
+				rounds = append(rounds, round)
+			}
+		}
+	}
+
+	start := min(req.Page*req.PageSize, len(rounds))
+	end := min(start+req.PageSize, len(rounds))
+	return &GameHistoryPage{
+		Rounds:   rounds[start:end],
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Total:    len(rounds),
+	}, nil
+}
+
+// GetTableStates fetches the current state of all tables from the Evolution lobby state API
+func (service EvoService) GetTableStates(ctx context.Context) (*TableStateResponse, error) {
+	stateURL := fmt.Sprintf("%s/api/lobby/v1/%s/state", service.Conf.URL, service.Auth.CasinoKey)
+	r := &valkhttp.HTTPRequest{
+		URL:     stateURL,
+		Headers: service.casinoAuthHeaders(),
+	}
+	resp := &LobbyStateResponse{}
+	if err := service.Client.Get(ctx, &valkhttp.JSONParser, r, resp); err != nil {
+		return nil, fmt.Errorf("failed calling evo lobby state: %w", err)
+	}
+
+	tables := make([]TableState, 0, len(resp.Tables))
+	for id, t := range resp.Tables {
+		tables = append(tables, TableState{
+			TableID:   id,
+			Name:      t.Name,
+			GameType:  t.GameType,
+			Open:      t.Open,
+			Players:   t.Players,
+			BetLimits: t.BetLimits,
+		})
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].TableID < tables[j].TableID })
+
+	return &TableStateResponse{Tables: tables}, nil
+}
+
+// casinoAuthHeaders basic auth headers used by the Evolution casino APIs
+func (service EvoService) casinoAuthHeaders() map[string]string {
+	encodedAuth := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", service.Auth.CasinoKey, service.Auth.CasinoToken)))
+	return map[string]string{
+		"Authorization": fmt.Sprintf("Basic %s", encodedAuth),
+	}
+}
+
+// toGameRound normalises an Evolution game. When playerID is set, only the bets of that
+// player are included and false is returned if the player did not participate.
+func toGameRound(game GameHistoryGame, playerID string) (GameRound, bool) {
+	round := GameRound{
+		GameRoundID: game.ID,
+		GameType:    game.GameType,
+		TableID:     game.Table.ID,
+		TableName:   game.Table.Name,
+		Status:      game.Status,
+		StartedAt:   game.StartedAt,
+		SettledAt:   game.SettledAt,
+		Currency:    game.Currency,
+		Players:     make([]string, 0, len(game.Participants)),
+		Stake:       game.Wager,
+		Payout:      game.Payout,
+	}
+	if playerID == "" {
+		for _, p := range game.Participants {
+			round.Players = append(round.Players, p.PlayerID)
+		}
+		return round, true
+	}
+
+	for _, p := range game.Participants {
+		if p.PlayerID != playerID {
+			continue
+		}
+		stake, payout := decimal.Zero, decimal.Zero
+		for _, b := range p.Bets {
+			stake = stake.Add(decimal.Decimal(b.Stake))
+			payout = payout.Add(decimal.Decimal(b.Payout))
+		}
+		round.Players = append(round.Players, p.PlayerID)
+		round.Currency = p.Currency
+		round.Stake = Amount(stake)
+		round.Payout = Amount(payout)
+		return round, true
+	}
+	return round, false
+}
@@ -3,11 +3,14 @@ package evolution
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 
@@ -20,6 +23,7 @@ type MockClient struct {
 	valkhttp.HTTPClient
 	PostJSONFunc func(ctx context.Context, req *valkhttp.HTTPRequest, resp any) error
 	GetFunc      func(ctx context.Context, req *valkhttp.HTTPRequest, resp *[]byte) error
+	GetJSONFunc  func(ctx context.Context, req *valkhttp.HTTPRequest, resp any) error
 }
 
 func (m MockClient) Post(ctx context.Context, _ valkhttp.Parser, req *valkhttp.HTTPRequest, resp any) error {
@@ -27,6 +31,17 @@ func (m MockClient) Post(ctx context.Context, _ valkhttp.Parser, req *valkhttp.H
 }
 
 func (m MockClient) Get(ctx context.Context, _ valkhttp.Parser, req *valkhttp.HTTPRequest, resp any) error {
+	if m.GetJSONFunc != nil {
+		return m.GetJSONFunc(ctx, req, resp)
+	}
+	if req.StreamTo != nil {
+		var body []byte
+		if err := m.GetFunc(ctx, req, &body); err != nil {
+			return err
+		}
+		_, err := req.StreamTo.Write(body)
+		return err
+	}
 	return m.GetFunc(ctx, req, resp.(*[]byte))
 }
 
@@ -249,3 +264,113 @@ func TestGameRoundRender(t *testing.T) {
 		})
 	}
 }
+
+func TestEvoService_GetGameHistory(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	history := `{"data":[{"date":"2023-01-01","games":[
+		{"id":"g1","gameType":"blackjack","table":{"id":"t1","name":"Blackjack"},"status":"Resolved","currency":"EUR","wager":15,"payout":20,
+		 "participants":[
+			{"playerId":"p1","currency":"EUR","bets":[{"stake":5,"payout":10},{"stake":5,"payout":0}]},
+			{"playerId":"p2","currency":"EUR","bets":[{"stake":5,"payout":10}]}]},
+		{"id":"g2","gameType":"roulette","table":{"id":"t2","name":"Roulette"},"status":"Resolved","currency":"EUR","wager":1,"payout":0,
+		 "participants":[{"playerId":"p2","currency":"EUR","bets":[{"stake":1,"payout":0}]}]}]}]}`
+
+	tests := []struct {
+		name       string
+		req        GameHistoryRequest
+		wantRounds []string
+		wantTotal  int
+		wantStake  string
+	}{
+		{
+			name:       "all rounds in period",
+			req:        GameHistoryRequest{From: from, To: to, PageSize: 10},
+			wantRounds: []string{"g1", "g2"},
+			wantTotal:  2,
+			wantStake:  "15",
+		},
+		{
+			name:       "rounds for player only include player bets",
+			req:        GameHistoryRequest{From: from, To: to, PlayerID: "p1", PageSize: 10},
+			wantRounds: []string{"g1"},
+			wantTotal:  1,
+			wantStake:  "10",
+		},
+		{
+			name:       "paging",
+			req:        GameHistoryRequest{From: from, To: to, Page: 1, PageSize: 1},
+			wantRounds: []string{"g2"},
+			wantTotal:  2,
+			wantStake:  "1",
+		},
+		{
+			name:       "page out of range",
+			req:        GameHistoryRequest{From: from, To: to, Page: 5, PageSize: 1},
+			wantRounds: []string{},
+			wantTotal:  2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			sut := EvoService{
+				Auth: AuthConf{CasinoKey: "key", CasinoToken: "token"},
+				Conf: &configs.ProviderConf{URL: "evo-url"},
+				Client: MockClient{GetJSONFunc: func(_ context.Context, req *valkhttp.HTTPRequest, resp any) error {
+					assert.Equal(tt, "evo-url/api/gamehistory/v1/casino/games", req.URL)
+					assert.Equal(tt, "2023-01-01T00:00:00Z", req.Query["startDate"])
+					assert.Equal(tt, "2023-01-02T00:00:00Z", req.Query["endDate"])
+					assert.Equal(tt, "Basic a2V5OnRva2Vu", req.Headers["Authorization"])
+					return json.Unmarshal([]byte(history), resp)
+				}},
+			}
+
+			page, err := sut.GetGameHistory(context.Background(), test.req)
+			assert.NoError(tt, err)
+			assert.Equal(tt, test.wantTotal, page.Total)
+			ids := make([]string, 0)
+			for _, r := range page.Rounds {
+				ids = append(ids, r.GameRoundID)
+			}
+			assert.Equal(tt, test.wantRounds, ids)
+			if test.wantStake != "" {
+				assert.Equal(tt, test.wantStake, decimal.Decimal(page.Rounds[0].Stake).String())
+			}
+		})
+	}
+}
+
+func TestEvoService_GetTableStates(t *testing.T) {
+	state := `{"tables":{
+		"t2":{"name":"Roulette","gameType":"roulette","open":false,"players":0},
+		"t1":{"name":"Blackjack","gameType":"blackjack","open":true,"players":7,"betLimits":{"EUR":{"symbol":"€","min":1,"max":500}}}}}`
+	sut := EvoService{
+		Auth: AuthConf{CasinoKey: "key", CasinoToken: "token"},
+		Conf: &configs.ProviderConf{URL: "evo-url"},
+		Client: MockClient{GetJSONFunc: func(_ context.Context, req *valkhttp.HTTPRequest, resp any) error {
+			assert.Equal(t, "evo-url/api/lobby/v1/key/state", req.URL)
+			return json.Unmarshal([]byte(state), resp)
+		}},
+	}
+
+	resp, err := sut.GetTableStates(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, resp.Tables, 2)
+	assert.Equal(t, "t1", resp.Tables[0].TableID)
+	assert.True(t, resp.Tables[0].Open)
+	assert.Equal(t, 7, resp.Tables[0].Players)
+	assert.Equal(t, "500", decimal.Decimal(resp.Tables[0].BetLimits["EUR"].Max).String())
+	assert.Equal(t, "t2", resp.Tables[1].TableID)
+}
+
+func TestEvoService_GetTableStatesError(t *testing.T) {
+	sut := EvoService{
+		Conf: &configs.ProviderConf{URL: "evo-url"},
+		Client: MockClient{GetJSONFunc: func(_ context.Context, _ *valkhttp.HTTPRequest, _ any) error {
+			return valkhttp.NewHTTPError(401, "unauthorized")
+		}},
+	}
+
+	_, err := sut.GetTableStates(context.Background())
+	assert.ErrorAs(t, err, &valkhttp.HTTPError{})
+}
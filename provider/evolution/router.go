@@ -6,8 +6,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 	"github.com/valkyrie-fnd/valkyrie/provider"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
 
@@ -17,19 +20,28 @@ const (
 )
 
 func init() {
+	provider.RegisterErrorMapping(ProviderName, provider.NewErrorMapping(errCodes))
+	redact.Register("sid", apiTokenParamName)
 	provider.ProviderFactory().
 		Register(ProviderName, func(args provider.ProviderArgs) (*provider.Router, error) {
 			if args.PamClient.GetTransactionSupplier() == pam.PROVIDER {
 				return nil, fmt.Errorf("unsupported transaction supplier")
 			}
-			service := NewService(args.PamClient)
+			conf, err := GetEvoConf(args.Config)
+			if err != nil {
+				return nil, err
+			}
+			service := NewService(args.PamClient).
+				WithTipTables(conf.TipTables...).
+				WithExchange(fx.NewExchange(args.Converter, args.PamClient, ProviderName))
 			controller := NewProviderController(service)
 			return NewProviderRouter(args.Config, controller)
 		})
 	provider.OperatorFactory().
 		Register(ProviderName, func(args provider.OperatorArgs) (*provider.Router, error) {
-			return NewOperatorRouter(args.Config, args.HTTPClient)
+			return NewOperatorRouter(args.Config, args.HTTPClient, args.Converter)
 		})
+	simulation.Factory().Register(ProviderName, NewSimulator)
 }
 
 type Controller interface {
@@ -90,7 +102,7 @@ func NewProviderRouter(config configs.ProviderConf, controller Controller) (*pro
 	}, nil
 }
 
-func NewOperatorRouter(config configs.ProviderConf, httpClient valkhttp.HTTPClient) (*provider.Router, error) {
+func NewOperatorRouter(config configs.ProviderConf, httpClient valkhttp.HTTPClient, converter *fx.Converter) (*provider.Router, error) {
 	auth, err := GetAuthConf(config)
 	if err != nil {
 		return nil, err
@@ -100,8 +112,9 @@ func NewOperatorRouter(config configs.ProviderConf, httpClient valkhttp.HTTPClie
 		Conf:   &config,
 		Client: httpClient,
 	}
-	glController := provider.NewGameLaunchController(&evoService)
+	glController := provider.NewGameLaunchController(&evoService).WithConverter(converter)
 	grCtrl := provider.NewGameRoundController(&evoService)
+	opCtrl := NewOperatorController(&evoService)
 	routes := []provider.Route{
 		{
 			Path:        "/gamelaunch",
@@ -113,6 +126,16 @@ func NewOperatorRouter(config configs.ProviderConf, httpClient valkhttp.HTTPClie
 			Method:      "GET",
 			HandlerFunc: grCtrl.GetGameRoundEndpoint,
 		},
+		{
+			Path:        "/gamehistory",
+			Method:      "GET",
+			HandlerFunc: opCtrl.GameHistoryEndpoint,
+		},
+		{
+			Path:        "/tables",
+			Method:      "GET",
+			HandlerFunc: opCtrl.TableStateEndpoint,
+		},
 	}
 
 	return &provider.Router{
@@ -0,0 +1,83 @@
+package evolution
+
+import (
+	"context"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+// simulator sends wallet requests the way Evolution does, authenticated by the api key as query parameter
+type simulator struct {
+	client valkhttp.HTTPClient
+	url    string
+	apiKey string
+}
+
+// simulatorResponse fields of the wallet responses used by the simulator
+type simulatorResponse struct {
+	Status string `json:"status"`
+	SID    string `json:"sid"`
+}
+
+// NewSimulator creates a simulator using the "api_key" auth configuration
+func NewSimulator(args simulation.Args) (simulation.Simulator, error) {
+	auth, err := GetAuthConf(args.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &simulator{client: args.HTTPClient, url: args.URL, apiKey: auth.APIKey}, nil
+}
+
+func (s *simulator) post(ctx context.Context, path string, req any) (simulation.Response, error) {
+	var resp simulatorResponse
+	err := s.client.Post(ctx, &valkhttp.JSONParser, &valkhttp.HTTPRequest{
+		URL:   s.url + path,
+		Query: map[string]string{apiTokenParamName: s.apiKey},
+		Body:  req,
+	}, &resp)
+	if resp.Status == "" {
+		return simulation.Response{}, err
+	}
+	return simulation.Response{Code: resp.Status, OK: resp.Status == StatusOK.code, Token: resp.SID}, nil
+}
+
+func simulatorRequestBase(session simulation.Session, uuid string) RequestBase {
+	return RequestBase{SID: session.Token, UserID: session.PlayerID, UUID: uuid}
+}
+
+func simulatorGame(session simulation.Session, roundID string) Game {
+	return Game{ID: roundID, Type: "blackjack", Details: GameDetails{Table: GameTable{ID: session.GameID}}}
+}
+
+func (s *simulator) Authenticate(ctx context.Context, session simulation.Session) (simulation.Response, error) {
+	return s.post(ctx, "/check", &CheckRequest{RequestBase: simulatorRequestBase(session, "check-"+session.Token)})
+}
+
+func (s *simulator) Bet(ctx context.Context, session simulation.Session, bet simulation.Bet) (simulation.Response, error) {
+	return s.post(ctx, "/debit", &DebitRequest{
+		RequestBase: simulatorRequestBase(session, bet.TransactionID),
+		Currency:    session.Currency,
+		Game:        simulatorGame(session, bet.RoundID),
+		Transaction: Transaction{ID: bet.TransactionID, RefID: bet.TransactionID, Amount: Amount(pam.Amt(bet.Amount))},
+	})
+}
+
+func (s *simulator) Win(ctx context.Context, session simulation.Session, win simulation.Win) (simulation.Response, error) {
+	return s.post(ctx, "/credit", &CreditRequest{
+		RequestBase: simulatorRequestBase(session, win.TransactionID),
+		Currency:    session.Currency,
+		Game:        simulatorGame(session, win.Bet.RoundID),
+		Transaction: Transaction{ID: win.TransactionID, RefID: win.Bet.TransactionID, Amount: Amount(pam.Amt(win.Amount))},
+	})
+}
+
+func (s *simulator) Rollback(ctx context.Context, session simulation.Session, rollback simulation.Rollback) (simulation.Response, error) {
+	return s.post(ctx, "/cancel", &CancelRequest{
+		RequestBase: simulatorRequestBase(session, rollback.TransactionID),
+		Currency:    session.Currency,
+		Game:        simulatorGame(session, rollback.Bet.RoundID),
+		Transaction: Transaction{ID: rollback.TransactionID, RefID: rollback.Bet.TransactionID, Amount: Amount(pam.Amt(rollback.Bet.Amount))},
+	})
+}
@@ -4,20 +4,43 @@ import (
 	"context"
 	"time"
 
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
 type WalletService struct {
 	pamClient pam.PamClient
 	ctx       context.Context
+	tipTables map[string]struct{}
+	exchange  *fx.Exchange
 }
 
 func NewService(pamClient pam.PamClient) *WalletService {
 	return &WalletService{pamClient: pamClient, ctx: context.Background()}
 }
 
+// WithTipTables returns a service treating debits on the given tables as dealer tips
+func (service *WalletService) WithTipTables(tables ...string) *WalletService {
+	tipTables := make(map[string]struct{}, len(tables))
+	for _, t := range tables {
+		tipTables[t] = struct{}{}
+	}
+	return &WalletService{pamClient: service.pamClient, ctx: service.ctx, tipTables: tipTables, exchange: service.exchange}
+}
+
+// WithExchange returns a service converting wallet amounts between the play currency and the wallet currency
+func (service *WalletService) WithExchange(exchange *fx.Exchange) *WalletService {
+	return &WalletService{pamClient: service.pamClient, ctx: service.ctx, tipTables: service.tipTables, exchange: exchange}
+}
+
 func (service *WalletService) WithContext(ctx context.Context) Service {
-	return &WalletService{pamClient: service.pamClient, ctx: ctx}
+	return &WalletService{pamClient: service.pamClient, ctx: ctx, tipTables: service.tipTables, exchange: service.exchange}
+}
+
+// isTip whether a debit on the game is a dealer tip
+func (service *WalletService) isTip(game Game) bool {
+	_, found := service.tipTables[game.Details.Table.ID]
+	return found
 }
 
 // @Id           EvoCheck
@@ -66,7 +89,7 @@ func (service *WalletService) Check(req CheckRequest) (*CheckResponse, error) {
 // @Failure      500     {object}  StandardResponse
 // @Router       /providers/evolution/balance [post]
 func (service *WalletService) Balance(req BalanceRequest) (*StandardResponse, error) {
-	balance, err := service.pamClient.GetBalance(service.balanceRequestMapper(req.RequestBase))
+	balance, err := service.balance(req.RequestBase, req.Currency)
 
 	if err != nil {
 		return nil, toProviderError(err, req.UUID, ZeroAmount, ZeroAmount)
@@ -94,7 +117,7 @@ func (service *WalletService) Balance(req BalanceRequest) (*StandardResponse, er
 // @Router       /providers/evolution/debit [post]
 func (service *WalletService) Debit(req DebitRequest) (*StandardResponse, error) {
 	// Send the debit transaction and ignore the success response
-	transactionResp, err := service.pamClient.AddTransaction(service.debitRequestMapper(req, time.Now()))
+	transactionResp, err := service.addTransaction(req.RequestBase, req.Currency, service.debitRequestMapper(req, time.Now()))
 
 	if err != nil {
 		if transactionResp != nil && transactionResp.Balance != nil {
@@ -136,7 +159,7 @@ func (service *WalletService) Credit(req CreditRequest) (*StandardResponse, erro
 	transactions, err := service.pamClient.GetTransactions(service.findTransForCreditRequestMapper(req))
 
 	if err != nil {
-		balance, balanceErr := service.pamClient.GetBalance(service.balanceRequestMapper(req.RequestBase))
+		balance, balanceErr := service.balance(req.RequestBase, req.Currency)
 		if balanceErr != nil {
 			return nil, toProviderError(balanceErr, req.UUID, ZeroAmount, ZeroAmount)
 		} else {
@@ -154,7 +177,7 @@ func (service *WalletService) Credit(req CreditRequest) (*StandardResponse, erro
 
 	// fetch balance and bail if validation failed
 	if validationError != nil {
-		balance, balanceErr := service.pamClient.GetBalance(service.balanceRequestMapper(req.RequestBase))
+		balance, balanceErr := service.balance(req.RequestBase, req.Currency)
 		if balanceErr != nil {
 			return nil, toProviderError(balanceErr, req.UUID, ZeroAmount, ZeroAmount)
 		} else {
@@ -163,7 +186,7 @@ func (service *WalletService) Credit(req CreditRequest) (*StandardResponse, erro
 	}
 
 	// Send the credit transaction and ignore the success response
-	transactionResp, err := service.pamClient.AddTransaction(service.creditTransRequestMapper(req, time.Now()))
+	transactionResp, err := service.settle(req.Currency, findStake(transactions), false, service.creditTransRequestMapper(req, time.Now()))
 	if err != nil {
 		if transactionResp != nil && transactionResp.Balance != nil {
 			return nil, toProviderError(err, req.UUID, fromPamAmount(&transactionResp.Balance.CashAmount), fromPamAmount(&transactionResp.Balance.BonusAmount))
@@ -204,7 +227,7 @@ func (service *WalletService) Cancel(req CancelRequest) (*StandardResponse, erro
 	transactions, err := service.pamClient.GetTransactions(service.findTransForCancelRequestMapper(req))
 
 	if err != nil {
-		balance, balanceErr := service.pamClient.GetBalance(service.balanceRequestMapper(req.RequestBase))
+		balance, balanceErr := service.balance(req.RequestBase, req.Currency)
 		if balanceErr != nil {
 			return nil, toProviderError(balanceErr, req.UUID, ZeroAmount, ZeroAmount)
 		} else {
@@ -222,7 +245,7 @@ func (service *WalletService) Cancel(req CancelRequest) (*StandardResponse, erro
 
 	// fetch balance and bail if validation failed
 	if validationError != nil {
-		balance, balanceErr := service.pamClient.GetBalance(service.balanceRequestMapper(req.RequestBase))
+		balance, balanceErr := service.balance(req.RequestBase, req.Currency)
 		if balanceErr != nil {
 			return nil, toProviderError(balanceErr, req.UUID, ZeroAmount, ZeroAmount)
 		} else {
@@ -230,8 +253,8 @@ func (service *WalletService) Cancel(req CancelRequest) (*StandardResponse, erro
 		}
 	}
 
-	// Send the debit transaction and ignore the success response
-	transactionResp, err := service.pamClient.AddTransaction(service.cancelTransRequestMapper(req, time.Now()))
+	// Send the cancel transaction, refunded at the rate of the stake, and ignore the success response
+	transactionResp, err := service.settle(req.Currency, findStake(transactions), true, service.cancelTransRequestMapper(req, time.Now()))
 	if err != nil {
 		if transactionResp != nil && transactionResp.Balance != nil {
 			return nil, toProviderError(err, req.UUID, fromPamAmount(&transactionResp.Balance.CashAmount), fromPamAmount(&transactionResp.Balance.BonusAmount))
@@ -269,7 +292,7 @@ func (service *WalletService) Cancel(req CancelRequest) (*StandardResponse, erro
 // @Router       /providers/evolution/promo_payout [post]
 func (service *WalletService) PromoPayout(req PromoPayoutRequest) (*StandardResponse, error) {
 	// Send the debit transaction and ignore the success response
-	transactionResp, err := service.pamClient.AddTransaction(service.promoPayoutTransRequestMapper(req, time.Now()))
+	transactionResp, err := service.addTransaction(req.RequestBase, req.Currency, service.promoPayoutTransRequestMapper(req, time.Now()))
 
 	if err != nil {
 		if transactionResp != nil && transactionResp.Balance != nil {
@@ -294,6 +317,48 @@ func (service *WalletService) PromoPayout(req PromoPayoutRequest) (*StandardResp
 	}, err
 }
 
+// balance gets the balance of the player in the play currency
+func (service *WalletService) balance(r RequestBase, currency string) (*pam.Balance, error) {
+	balance, err := service.pamClient.GetBalance(service.balanceRequestMapper(r))
+	if err != nil {
+		return nil, err
+	}
+	return balance, service.exchange.Balance(service.ctx, r.SID, r.UUID, currency, balance)
+}
+
+// addTransaction adds the transaction converted to the wallet currency, returning the balance in the play currency
+func (service *WalletService) addTransaction(r RequestBase, currency string, mapper pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	transactionResp, err := service.pamClient.AddTransaction(service.exchange.Transaction(mapper))
+	if transactionResp != nil && transactionResp.Balance != nil {
+		if balanceErr := service.exchange.Balance(service.ctx, r.SID, r.UUID, currency, transactionResp.Balance); balanceErr != nil && err == nil {
+			err = balanceErr
+		}
+	}
+	return transactionResp, err
+}
+
+// settle adds the transaction settling stake, converted to the wallet currency of the stake since the player session
+// may have ended, returning the balance in the play currency. With stakeRate the rate of the stake is reused.
+func (service *WalletService) settle(currency string, stake *pam.Transaction, stakeRate bool, mapper pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	transactionResp, err := service.pamClient.AddTransaction(service.exchange.Settlement(mapper, stake, stakeRate))
+	if transactionResp != nil && transactionResp.Balance != nil {
+		if balanceErr := service.exchange.SettlementBalance(service.ctx, stake, currency, transactionResp.Balance); balanceErr != nil && err == nil {
+			err = balanceErr
+		}
+	}
+	return transactionResp, err
+}
+
+// findStake returns the first stake of transactions, which must contain one
+func findStake(transactions []pam.Transaction) *pam.Transaction {
+	for i, t := range transactions {
+		if t.TransactionType == pam.WITHDRAW {
+			return &transactions[i]
+		}
+	}
+	return nil
+}
+
 func containsType(trx *[]pam.Transaction, tty ...pam.TransactionType) bool {
 	for _, tr := range *trx {
 		for _, ttype := range tty {
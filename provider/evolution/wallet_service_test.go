@@ -1,13 +1,17 @@
 package evolution
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
@@ -431,6 +435,92 @@ func TestProviderService_PromoPayout(t *testing.T) {
 	}
 }
 
+type rateSourceStub struct{}
+
+func (rateSourceStub) Rate(_ context.Context, from, to string) (fx.Rate, error) {
+	rates := map[string]string{"EUR/SEK": "11.5", "SEK/EUR": "0.086956521739130435"}
+	return fx.Rate{From: from, To: to, Value: decimal.RequireFromString(rates[from+"/"+to])}, nil
+}
+
+func TestProviderService_Debit_PlayCurrency(t *testing.T) {
+	pamstub := pamStub{
+		sessionFn: func() (*pam.Session, error) { return &pam.Session{Currency: "SEK"}, nil },
+		addTransFn: func() (*pam.TransactionResult, error) {
+			return &pam.TransactionResult{
+				TransactionId: strPtr("trans2"),
+				Balance:       &pam.Balance{CashAmount: testutils.NewFloatAmount(1000), BonusAmount: testutils.NewFloatAmount(11.5)}}, nil
+		},
+	}
+	svc := NewService(&pamstub).WithExchange(fx.NewExchange(fx.NewConverter(rateSourceStub{}), &pamstub, ProviderName))
+
+	resp, err := svc.Debit(DebitRequest{
+		RequestBase: RequestBase{UserID: "user_1", SID: "sid_X"},
+		Currency:    "EUR",
+		Transaction: Transaction{ID: "ext_1", RefID: "ref_A", Amount: amountFromFloat(10.01)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "86.95", decimal.Decimal(resp.Balance).String(), "balance is converted to the play currency")
+	assert.Equal(t, "1", decimal.Decimal(resp.Bonus).String(), "balance is converted to the play currency")
+
+	var mapped *pam.AddTransactionRequest
+	_, mapped, err = svc.exchange.Transaction(svc.debitRequestMapper(DebitRequest{
+		RequestBase: RequestBase{UserID: "user_1", SID: "sid_X"},
+		Currency:    "EUR",
+		Transaction: Transaction{ID: "ext_1", RefID: "ref_A", Amount: amountFromFloat(10.01)},
+	}, time.Now()))(pam.SixDecimalRounder)
+	require.NoError(t, err)
+	assert.Equal(t, "SEK", mapped.Body.Currency)
+	assert.Equal(t, "115.12", decimal.Decimal(mapped.Body.CashAmount).String())
+	assert.Equal(t, "EUR", mapped.Body.CurrencyConversion.ProviderCurrency)
+}
+
+// addTransactionRecordingStub records the transactions sent to the PAM
+type addTransactionRecordingStub struct {
+	pamStub
+	added []pam.Transaction
+}
+
+func (s *addTransactionRecordingStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	_, req, err := rm(pam.SixDecimalRounder)
+	if err != nil {
+		return nil, err
+	}
+	s.added = append(s.added, req.Body)
+	return s.pamStub.AddTransaction(rm)
+}
+
+func TestProviderService_Settle_PlayCurrency(t *testing.T) {
+	stake := createTrans(pam.WITHDRAW, 120, "ref_A", "ext_1")
+	stake.Currency = "SEK"
+	stake.CurrencyConversion = &pam.CurrencyConversion{ProviderCurrency: "EUR", ProviderCashAmount: testutils.NewFloatAmount(10), Rate: "12"}
+	stub := &addTransactionRecordingStub{pamStub: pamStub{
+		sessionFn: func() (*pam.Session, error) {
+			return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrOpSessionNotFound}
+		},
+		getTransFn: func() ([]pam.Transaction, error) { return []pam.Transaction{stake}, nil },
+		addTransFn: func() (*pam.TransactionResult, error) {
+			return &pam.TransactionResult{TransactionId: strPtr("trans2"), Balance: &pam.Balance{CashAmount: testutils.NewFloatAmount(1150)}}, nil
+		},
+	}}
+	svc := NewService(stub).WithExchange(fx.NewExchange(fx.NewConverter(rateSourceStub{}), stub, ProviderName))
+	base := RequestBase{UserID: "user_1", SID: "sid_X"}
+	transaction := Transaction{ID: "ext_2", RefID: "ref_A", Amount: amountFromFloat(10)}
+
+	resp, err := svc.Credit(CreditRequest{RequestBase: base, Currency: "EUR", Transaction: transaction})
+	require.NoError(t, err, "credits do not depend on the player session")
+	assert.Equal(t, "100", decimal.Decimal(resp.Balance).String())
+	resp, err = svc.Cancel(CancelRequest{RequestBase: base, Currency: "EUR", Transaction: transaction})
+	require.NoError(t, err, "cancels do not depend on the player session")
+	assert.Equal(t, "100", decimal.Decimal(resp.Balance).String())
+
+	require.Len(t, stub.added, 2)
+	assert.Equal(t, "SEK", stub.added[0].Currency)
+	assert.Equal(t, "115", decimal.Decimal(stub.added[0].CashAmount).String(), "credits are converted at the current rate")
+	assert.Equal(t, "SEK", stub.added[1].Currency)
+	assert.Equal(t, "120", decimal.Decimal(stub.added[1].CashAmount).String(), "cancels are refunded at the rate of the stake")
+	assert.Equal(t, "12", stub.added[1].CurrencyConversion.Rate)
+}
+
 // Test_UnknownUserResponse verifies that potential IDOR issues are not propagated by Valkyrie. It's assumed
 // that PAM's handle that properly but let's make sure it actually works. Ergo: unknown user issues should
 // result in invalid session and nothing else.
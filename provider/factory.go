@@ -5,6 +5,7 @@ import (
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
 	"github.com/valkyrie-fnd/valkyrie/internal"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
@@ -14,11 +15,15 @@ type ProviderArgs struct {
 	PamClient  pam.PamClient
 	HTTPClient valkhttp.HTTPClient
 	Config     configs.ProviderConf
+	// Converter of amounts between play and wallet currencies, nil unless currency conversion is configured
+	Converter *fx.Converter
 }
 
 type OperatorArgs struct {
 	HTTPClient valkhttp.HTTPClient
 	Config     configs.ProviderConf
+	// Converter of amounts between play and wallet currencies, nil unless currency conversion is configured
+	Converter *fx.Converter
 }
 
 type providerFactory = internal.AbstractFactory[ProviderArgs, *Router]
@@ -6,15 +6,23 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
 
 type GameLaunchController struct {
-	ps ProviderService
+	ps        ProviderService
+	converter *fx.Converter
 }
 
 func NewGameLaunchController(s ProviderService) *GameLaunchController {
-	return &GameLaunchController{s}
+	return &GameLaunchController{ps: s}
+}
+
+// WithConverter returns a controller allowing games to be launched in a play currency other than the wallet
+// currency, as long as converter has a rate between them
+func (ctrl *GameLaunchController) WithConverter(converter *fx.Converter) *GameLaunchController {
+	return &GameLaunchController{ps: ctrl.ps, converter: converter}
 }
 
 var validate = validator.New()
@@ -43,6 +51,17 @@ func (ctrl GameLaunchController) GameLaunchEndpoint(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(validationErrorsMap(err))
 	}
 
+	if g.PlayCurrency != "" && g.PlayCurrency != g.Currency {
+		if ctrl.converter == nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON("play currency is not supported without currency conversion")
+		}
+		if _, err = ctrl.converter.Rate(ctx.UserContext(), g.PlayCurrency, g.Currency); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(err.Error())
+		}
+		// The game is played in the play currency, with amounts converted to and from the wallet currency
+		g.Currency = g.PlayCurrency
+	}
+
 	url, err := ctrl.ps.GameLaunch(ctx, g, h)
 	if err != nil {
 		hErr := &valkhttp.HTTPError{}
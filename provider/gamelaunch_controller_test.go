@@ -2,6 +2,7 @@ package provider
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,9 +12,12 @@ import (
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
 
@@ -166,3 +170,80 @@ func TestGameLaunch(t *testing.T) {
 		}
 	}
 }
+
+type rateSourceStub struct{}
+
+func (rateSourceStub) Rate(_ context.Context, from, to string) (fx.Rate, error) {
+	if from == "EUR" && to == "SEK" {
+		return fx.Rate{From: from, To: to, Value: decimal.RequireFromString("11.5")}, nil
+	}
+	return fx.Rate{}, fmt.Errorf("%w from %s to %s", fx.ErrNoRate, from, to)
+}
+
+func TestGameLaunch_PlayCurrency(t *testing.T) {
+	tests := []struct {
+		name         string
+		playCurrency string
+		converter    *fx.Converter
+		wantStatus   int
+		wantBody     string
+		wantCurrency string
+	}{
+		{
+			name:         "without play currency",
+			wantStatus:   200,
+			wantCurrency: "SEK",
+		},
+		{
+			name:         "play currency same as wallet currency",
+			playCurrency: "SEK",
+			wantStatus:   200,
+			wantCurrency: "SEK",
+		},
+		{
+			name:         "play currency without currency conversion",
+			playCurrency: "EUR",
+			wantStatus:   400,
+			wantBody:     `"play currency is not supported without currency conversion"`,
+		},
+		{
+			name:         "play currency with rate",
+			playCurrency: "EUR",
+			converter:    fx.NewConverter(rateSourceStub{}),
+			wantStatus:   200,
+			wantCurrency: "EUR",
+		},
+		{
+			name:         "play currency without rate",
+			playCurrency: "USD",
+			converter:    fx.NewConverter(rateSourceStub{}),
+			wantStatus:   400,
+			wantBody:     `"no exchange rate from USD to SEK"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var launchedCurrency string
+			ctrl := NewGameLaunchController(ProviderServiceMock{func(gr *GameLaunchRequest, _ *GameLaunchHeaders) (string, error) {
+				launchedCurrency = gr.Currency
+				return "SomeLaunchUrl", nil
+			}}).WithConverter(tt.converter)
+			testApp := fiber.New()
+			testApp.Post("/gamelaunch", ctrl.GameLaunchEndpoint)
+
+			body, _ := json.Marshal(GameLaunchRequest{Currency: "SEK", PlayCurrency: tt.playCurrency, ProviderGameID: "1", PlayerID: "1"})
+			req := httptest.NewRequest(http.MethodPost, "/gamelaunch", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Player-Token", "123")
+
+			resp, err := testApp.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				responseBody, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.wantBody, string(responseBody))
+			}
+			assert.Equal(t, tt.wantCurrency, launchedCurrency)
+		})
+	}
+}
@@ -23,6 +23,7 @@ type GameLaunchHeaders struct {
 type GameLaunchRequest struct {
 	LaunchConfig   map[string]interface{} `json:"launchConfig,omitempty"`
 	Currency       string                 `json:"currency" validate:"required"`
+	PlayCurrency   string                 `json:"playCurrency,omitempty"`
 	ProviderGameID string                 `json:"providerGameId" validate:"required"`
 	PlayerID       string                 `json:"playerId" validate:"required"`
 	Casino         string                 `json:"casino,omitempty"`
@@ -0,0 +1,80 @@
+package providertest
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+// Outcome expected result of a scenario step, mapped by adapters to native result codes
+type Outcome string
+
+const (
+	Success           Outcome = "success"
+	DuplicateBet      Outcome = "duplicate bet"
+	InsufficientFunds Outcome = "insufficient funds"
+	ExpiredSession    Outcome = "expired session"
+	BetNotFound       Outcome = "bet not found"
+	AlreadySettled    Outcome = "already settled"
+)
+
+// Session the player session used by the scenario steps
+type Session struct {
+	Token    string
+	PlayerID string
+	Currency string
+	GameID   string
+}
+
+// Bet a bet placed by the player
+type Bet struct {
+	TransactionID string
+	RoundID       string
+	Amount        decimal.Decimal
+}
+
+// Win a win settling a bet, ending the game round
+type Win struct {
+	TransactionID string
+	Bet           Bet
+	Amount        decimal.Decimal
+}
+
+// Rollback a rollback of a bet
+type Rollback struct {
+	TransactionID string
+	Bet           Bet
+}
+
+// Response native provider response, normalised by the adapter
+type Response struct {
+	// StatusCode HTTP status code of the response
+	StatusCode int
+	// Code native result code, compared with the codes of the adapter
+	Code string
+	// Balance cash balance in the response, if any
+	Balance *decimal.Decimal
+	// Token session token in the response, if any
+	Token string
+	// Body of the response, included in assertion messages
+	Body []byte
+}
+
+// Adapter translates scenario steps into requests of a provider's native wallet API
+type Adapter interface {
+	// Config of the provider, as passed to the provider factory. The base path defaults to "/" + name.
+	Config() configs.ProviderConf
+	// Codes returns the native result code of each outcome. Scenarios expecting an outcome
+	// without a code are skipped.
+	Codes() map[Outcome]string
+	// Authenticate authenticates the session, such as exchanging the launch token
+	Authenticate(c *Client, s Session) (Response, error)
+	// Balance gets the balance of the player
+	Balance(c *Client, s Session) (Response, error)
+	// Bet places a bet
+	Bet(c *Client, s Session, bet Bet) (Response, error)
+	// Win settles a bet with a win
+	Win(c *Client, s Session, win Win) (Response, error)
+	// Rollback rolls back a bet
+	Rollback(c *Client, s Session, rollback Rollback) (Response, error)
+}
@@ -0,0 +1,51 @@
+package providertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Client sends requests to the provider router, without starting a server
+type Client struct {
+	app      *fiber.App
+	basePath string
+}
+
+// Post sends body to path, relative to the base path of the provider, and returns the
+// status code and body of the response
+func (c *Client) Post(path string, headers map[string]string, body []byte) (int, []byte, error) {
+	req := httptest.NewRequest(fiber.MethodPost, c.basePath+path, bytes.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	res, err := c.app.Test(req, -1)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	return res.StatusCode, resBody, err
+}
+
+// PostJSON sends req as JSON to path and decodes the response body into res. The returned
+// response has the status code and body set.
+func (c *Client) PostJSON(path string, headers map[string]string, req, res any) (Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	status, resBody, err := c.Post(path, headers, body)
+	if err != nil {
+		return Response{}, err
+	}
+	if err = json.Unmarshal(resBody, res); err != nil {
+		return Response{}, fmt.Errorf("unexpected response with status %d: %s: %w", status, resBody, err)
+	}
+	return Response{StatusCode: status, Body: resBody}, nil
+}
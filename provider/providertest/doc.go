@@ -0,0 +1,12 @@
+// Package providertest runs a standard catalogue of wallet scenarios against a provider
+// router, to verify that a provider integration behaves the same way as the built-in ones.
+//
+// The scenarios are run against an in-memory PAM. Providers take part by implementing an
+// Adapter, translating the scenario steps into requests of the provider's native wallet API
+// and normalising the responses. Each scenario asserts both the native response and the
+// resulting PAM ledger.
+//
+//	func TestConformance(t *testing.T) {
+//		providertest.Run(t, myAdapter{})
+//	}
+package providertest
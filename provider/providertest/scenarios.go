@@ -0,0 +1,276 @@
+package providertest
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/pam/memory"
+	"github.com/valkyrie-fnd/valkyrie/provider"
+)
+
+const providersBasePath = "/providers"
+
+// InitialBalance cash balance of the player at the start of each scenario
+var InitialBalance = decimal.NewFromInt(100)
+
+// Scenario a wallet scenario run with a new player and session
+type Scenario struct {
+	Name string
+	// Outcomes expected by the scenario, the scenario is skipped unless the adapter has codes for all
+	Outcomes []Outcome
+	Run      func(t *testing.T, env *Env)
+}
+
+// Env the provider router, PAM and player session of a scenario
+type Env struct {
+	Adapter Adapter
+	Client  *Client
+	PAM     *memory.PAM
+	Session Session
+	counter int
+}
+
+// Scenarios the standard scenario catalogue
+var Scenarios = []Scenario{
+	{
+		Name:     "authenticate",
+		Outcomes: []Outcome{Success},
+		Run: func(t *testing.T, env *Env) {
+			res, err := env.Adapter.Authenticate(env.Client, env.Session)
+			env.Expect(t, res, err, Success, InitialBalance)
+			if res.Token != "" {
+				_, found := env.PAM.Session(res.Token)
+				assert.True(t, found, "returned token is a PAM session")
+			}
+			env.ExpectLedger(t, InitialBalance)
+		},
+	},
+	{
+		Name:     "balance",
+		Outcomes: []Outcome{Success},
+		Run: func(t *testing.T, env *Env) {
+			res, err := env.Adapter.Balance(env.Client, env.Session)
+			env.Expect(t, res, err, Success, InitialBalance)
+			env.ExpectLedger(t, InitialBalance)
+		},
+	},
+	{
+		Name:     "bet",
+		Outcomes: []Outcome{Success},
+		Run: func(t *testing.T, env *Env) {
+			bet := env.NewBet("10")
+			res, err := env.Adapter.Bet(env.Client, env.Session, bet)
+			env.Expect(t, res, err, Success, dec("90"))
+			env.ExpectLedger(t, dec("90"), pam.WITHDRAW)
+		},
+	},
+	{
+		Name:     "win",
+		Outcomes: []Outcome{Success},
+		Run: func(t *testing.T, env *Env) {
+			bet := env.PlaceBet(t, "10")
+			res, err := env.Adapter.Win(env.Client, env.Session, env.NewWin(bet, "25"))
+			env.Expect(t, res, err, Success, dec("115"))
+			env.ExpectLedger(t, dec("115"), pam.WITHDRAW, pam.DEPOSIT)
+		},
+	},
+	{
+		Name:     "duplicate bet",
+		Outcomes: []Outcome{Success, DuplicateBet},
+		Run: func(t *testing.T, env *Env) {
+			bet := env.PlaceBet(t, "10")
+			res, err := env.Adapter.Bet(env.Client, env.Session, bet)
+			env.Expect(t, res, err, DuplicateBet, dec("90"))
+			env.ExpectLedger(t, dec("90"), pam.WITHDRAW)
+		},
+	},
+	{
+		Name:     "rollback bet",
+		Outcomes: []Outcome{Success},
+		Run: func(t *testing.T, env *Env) {
+			bet := env.PlaceBet(t, "10")
+			res, err := env.Adapter.Rollback(env.Client, env.Session, env.NewRollback(bet))
+			env.Expect(t, res, err, Success, InitialBalance)
+			env.ExpectLedger(t, InitialBalance, pam.WITHDRAW, pam.CANCEL)
+		},
+	},
+	{
+		Name:     "rollback unknown bet",
+		Outcomes: []Outcome{BetNotFound},
+		Run: func(t *testing.T, env *Env) {
+			res, err := env.Adapter.Rollback(env.Client, env.Session, env.NewRollback(env.NewBet("10")))
+			env.Expect(t, res, err, BetNotFound, InitialBalance)
+			env.ExpectLedger(t, InitialBalance)
+		},
+	},
+	{
+		Name:     "rollback after win",
+		Outcomes: []Outcome{Success, AlreadySettled},
+		Run: func(t *testing.T, env *Env) {
+			bet := env.PlaceBet(t, "10")
+			res, err := env.Adapter.Win(env.Client, env.Session, env.NewWin(bet, "25"))
+			env.Expect(t, res, err, Success, dec("115"))
+
+			res, err = env.Adapter.Rollback(env.Client, env.Session, env.NewRollback(bet))
+			env.Expect(t, res, err, AlreadySettled, dec("115"))
+			env.ExpectLedger(t, dec("115"), pam.WITHDRAW, pam.DEPOSIT)
+		},
+	},
+	{
+		Name:     "insufficient funds",
+		Outcomes: []Outcome{InsufficientFunds},
+		Run: func(t *testing.T, env *Env) {
+			res, err := env.Adapter.Bet(env.Client, env.Session, env.NewBet("100.01"))
+			env.Expect(t, res, err, InsufficientFunds, InitialBalance)
+			env.ExpectLedger(t, InitialBalance)
+		},
+	},
+	{
+		Name:     "expired session",
+		Outcomes: []Outcome{ExpiredSession},
+		Run: func(t *testing.T, env *Env) {
+			require.NoError(t, env.PAM.ExpireSession(env.Session.Token))
+			res, err := env.Adapter.Bet(env.Client, env.Session, env.NewBet("10"))
+			env.Expect(t, res, err, ExpiredSession, InitialBalance)
+			env.ExpectLedger(t, InitialBalance)
+		},
+	},
+}
+
+// Run runs the standard scenario catalogue against the provider of the adapter
+func Run(t *testing.T, adapter Adapter) {
+	RunScenarios(t, adapter, Scenarios...)
+}
+
+// RunScenarios runs the scenarios against the provider of the adapter, each with a new PAM
+func RunScenarios(t *testing.T, adapter Adapter, scenarios ...Scenario) {
+	codes := adapter.Codes()
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(tt *testing.T) {
+			for _, outcome := range scenario.Outcomes {
+				if _, found := codes[outcome]; !found {
+					tt.Skipf("adapter has no code for outcome '%s'", outcome)
+				}
+			}
+			scenario.Run(tt, NewEnv(tt, adapter))
+		})
+	}
+}
+
+// NewEnv creates the provider router of the adapter, backed by a PAM with a single player
+// and session
+func NewEnv(t *testing.T, adapter Adapter) *Env {
+	config := adapter.Config()
+	if config.BasePath == "" {
+		config.BasePath = "/" + config.Name
+	}
+
+	p := memory.New()
+	player := memory.Player{
+		ID:       "player-1",
+		Currency: "EUR",
+		Country:  "SE",
+		Language: "en",
+		Balance: pam.Balance{
+			CashAmount:  pam.Amount(InitialBalance),
+			BonusAmount: pam.ZeroAmount,
+			PromoAmount: pam.ZeroAmount,
+		},
+	}
+	p.AddPlayer(player)
+	gameID := "game-1"
+	session, err := p.AddSession(memory.Session{PlayerID: player.ID, GameID: &gameID})
+	require.NoError(t, err)
+
+	router, err := provider.ProviderFactory().Build(config.Name, provider.ProviderArgs{PamClient: p, Config: config})
+	require.NoError(t, err, "provider router could not be created")
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	require.NoError(t, provider.NewRegistry(app, providersBasePath).Register(router))
+
+	return &Env{
+		Adapter: adapter,
+		Client:  &Client{app: app, basePath: providersBasePath + router.BasePath},
+		PAM:     p,
+		Session: Session{Token: session.Token, PlayerID: player.ID, Currency: player.Currency, GameID: gameID},
+	}
+}
+
+// Serve serves the provider router on a local port until the test ends, and returns the base
+// url of the provider endpoints
+func (env *Env) Serve(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = env.Client.app.Listener(ln) }()
+	t.Cleanup(func() { _ = env.Client.app.Shutdown() })
+	return "http://" + ln.Addr().String() + env.Client.basePath
+}
+
+func (env *Env) nextID(prefix string) string {
+	env.counter++
+	return fmt.Sprintf("%s-%d", prefix, env.counter)
+}
+
+// NewBet creates a bet in a new game round, without placing it
+func (env *Env) NewBet(amount string) Bet {
+	return Bet{TransactionID: env.nextID("bet"), RoundID: env.nextID("round"), Amount: dec(amount)}
+}
+
+// NewWin creates a win on bet, without settling it
+func (env *Env) NewWin(bet Bet, amount string) Win {
+	return Win{TransactionID: env.nextID("win"), Bet: bet, Amount: dec(amount)}
+}
+
+// NewRollback creates a rollback of bet, without rolling it back
+func (env *Env) NewRollback(bet Bet) Rollback {
+	return Rollback{TransactionID: env.nextID("rollback"), Bet: bet}
+}
+
+// PlaceBet places a new bet, which must succeed
+func (env *Env) PlaceBet(t *testing.T, amount string) Bet {
+	bet := env.NewBet(amount)
+	res, err := env.Adapter.Bet(env.Client, env.Session, bet)
+	env.Expect(t, res, err, Success, InitialBalance.Sub(bet.Amount))
+	return bet
+}
+
+// Expect asserts the native code of the outcome, and the balance if included in a successful response
+func (env *Env) Expect(t *testing.T, res Response, err error, outcome Outcome, balance decimal.Decimal) {
+	t.Helper()
+	require.NoError(t, err)
+	assert.Equal(t, env.Adapter.Codes()[outcome], res.Code,
+		"expected '%s' (status %d): %s", outcome, res.StatusCode, res.Body)
+	if res.Balance != nil && res.Code == env.Adapter.Codes()[Success] {
+		assert.True(t, balance.Equal(*res.Balance), "expected balance %s, got %s", balance, res.Balance)
+	}
+}
+
+// ExpectLedger asserts the cash balance of the player and the types of the booked transactions
+func (env *Env) ExpectLedger(t *testing.T, balance decimal.Decimal, types ...pam.TransactionType) {
+	t.Helper()
+	player, found := env.PAM.Player(env.Session.PlayerID)
+	require.True(t, found)
+	cash := decimal.Decimal(player.Balance.CashAmount)
+	assert.True(t, balance.Equal(cash), "expected ledger balance %s, got %s", balance, cash)
+
+	booked := make([]string, 0, len(types))
+	for _, transaction := range env.PAM.Transactions(env.Session.PlayerID) {
+		booked = append(booked, string(transaction.TransactionType))
+	}
+	expected := make([]string, 0, len(types))
+	for _, tt := range types {
+		expected = append(expected, string(tt))
+	}
+	assert.Equal(t, strings.Join(expected, ","), strings.Join(booked, ","), "booked transactions")
+}
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
@@ -0,0 +1,120 @@
+package redtiger
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/provider/providertest"
+)
+
+const (
+	conformanceAPIKey  = "conformance-api-key"
+	conformanceSuccess = "success"
+)
+
+type conformanceAdapter struct{}
+
+// conformanceResponse fields of the different wallet responses used by the scenarios
+type conformanceResponse struct {
+	Success bool   `json:"success"`
+	Error   *Error `json:"error"`
+	Result  struct {
+		Token   string   `json:"token"`
+		Balance *Balance `json:"balance"`
+	} `json:"result"`
+	Balance *Balance `json:"balance"`
+}
+
+func (conformanceAdapter) Config() configs.ProviderConf {
+	return configs.ProviderConf{
+		Name: ProviderName,
+		Auth: map[string]any{"api_key": conformanceAPIKey},
+	}
+}
+
+// Codes are missing for expired sessions and rollbacks after wins, which are not mapped to
+// Red Tiger error codes
+func (conformanceAdapter) Codes() map[providertest.Outcome]string {
+	return map[providertest.Outcome]string{
+		providertest.Success:           conformanceSuccess,
+		providertest.DuplicateBet:      conformanceSuccess,
+		providertest.InsufficientFunds: strconv.Itoa(int(InsufficientFunds)),
+		providertest.BetNotFound:       strconv.Itoa(int(TransactionNotFound)),
+	}
+}
+
+func (conformanceAdapter) post(c *providertest.Client, path string, req any) (providertest.Response, error) {
+	var resp conformanceResponse
+	res, err := c.PostJSON(path, map[string]string{"Authorization": "Basic " + conformanceAPIKey}, req, &resp)
+	if err != nil {
+		return res, err
+	}
+	if resp.Success {
+		res.Code = conformanceSuccess
+	} else if resp.Error != nil {
+		res.Code = strconv.Itoa(int(resp.Error.Code))
+	}
+	balance := resp.Balance
+	if balance == nil {
+		balance = resp.Result.Balance
+	}
+	if balance != nil {
+		cash := decimal.Decimal(balance.Cash)
+		res.Balance = &cash
+	}
+	res.Token = resp.Result.Token
+	return res, nil
+}
+
+func baseRequest(s providertest.Session) BaseRequest {
+	return BaseRequest{Token: s.Token, UserID: s.PlayerID, Casino: "casino", Currency: s.Currency}
+}
+
+func conformanceGame(s providertest.Session) Game {
+	return Game{Type: "slot", Key: s.GameID, Version: "1"}
+}
+
+func (a conformanceAdapter) Authenticate(c *providertest.Client, s providertest.Session) (providertest.Response, error) {
+	return a.post(c, "/auth", &AuthRequest{BaseRequest: baseRequest(s)})
+}
+
+// Balance is reported by all Red Tiger responses, there is no balance request
+func (a conformanceAdapter) Balance(c *providertest.Client, s providertest.Session) (providertest.Response, error) {
+	return a.Authenticate(c, s)
+}
+
+func (a conformanceAdapter) Bet(c *providertest.Client, s providertest.Session, bet providertest.Bet) (providertest.Response, error) {
+	return a.post(c, "/stake", &StakeRequest{
+		BaseRequest: baseRequest(s),
+		Transaction: TransactionStake{ID: bet.TransactionID, Stake: Money(pam.Amt(bet.Amount)), StakePromo: zeroMoney()},
+		Game:        conformanceGame(s),
+		Round:       Round{ID: bet.RoundID, Starts: true},
+	})
+}
+
+func (a conformanceAdapter) Win(c *providertest.Client, s providertest.Session, win providertest.Win) (providertest.Response, error) {
+	return a.post(c, "/payout", &PayoutRequest{
+		BaseRequest: baseRequest(s),
+		Transaction: TransactionPayout{ID: win.TransactionID, Payout: Money(pam.Amt(win.Amount)), PayoutPromo: zeroMoney()},
+		Game:        conformanceGame(s),
+		Round:       Round{ID: win.Bet.RoundID, Ends: true},
+	})
+}
+
+// Rollback refunds the stake transaction, Red Tiger has no separate rollback transaction id
+func (a conformanceAdapter) Rollback(c *providertest.Client, s providertest.Session, rollback providertest.Rollback) (providertest.Response, error) {
+	return a.post(c, "/refund", &RefundRequest{
+		BaseRequest: baseRequest(s),
+		Transaction: TransactionStake{ID: rollback.Bet.TransactionID, Stake: Money(pam.Amt(rollback.Bet.Amount)), StakePromo: zeroMoney()},
+		Game:        conformanceGame(s),
+		Round:       Round{ID: rollback.Bet.RoundID, Ends: true},
+	})
+}
+
+func TestConformance(t *testing.T) {
+	providertest.Run(t, conformanceAdapter{})
+}
@@ -51,6 +51,7 @@ var errCodes = map[pam.ValkErrorCode]RTErrorCode{
 	pam.ValkErrOpPromoOverdraft:    InsufficientFunds,
 	pam.ValkErrOpCancelNotFound:    TransactionNotFound,
 	pam.ValkErrOpTransNotFound:     TransactionNotFound,
+	pam.ValkErrOpRoundNotFound:     TransactionNotFound,
 	pam.ValkErrOpNegativeStake:     InvalidInput,
 	pam.ValkErrOpRoundExists:       InvalidInput,
 	pam.ValkErrOpCancelExists:      DuplicateTransaction,
@@ -58,6 +59,14 @@ var errCodes = map[pam.ValkErrorCode]RTErrorCode{
 	pam.ValkErrOpCancelNonWithdraw: InvalidInput,
 	pam.ValkErrOpBetNotAllowed:     BannedUser,
 	pam.ValkErrUndefined:           GenericError,
+	pam.ValkErrOpGameNotFound:      InvalidInput,
+	pam.ValkErrOpSessionExpired:    NotAuthorized,
+	pam.ValkErrOpMissingProvider:   GenericError,
+	pam.ValkErrOpZeroStake:         InvalidInput,
+	pam.ValkErrBetNotFound:         TransactionNotFound,
+	pam.ValkErrOpAPIToken:          InternalServerError,
+	pam.ValkErrDuplicateTrans:      DuplicateTransaction,
+	pam.ValkErrTimeout:             InternalServerError,
 }
 
 func getError(vError pam.ValkErrorCode) RTErrorCode {
@@ -0,0 +1,13 @@
+package redtiger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func Test_errCodes(t *testing.T) {
+	assert.Empty(t, pam.MissingErrorCodes(errCodes), "every ValkErrorCode is mapped")
+}
@@ -2,6 +2,7 @@ package redtiger
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/valkyrie-fnd/valkyrie/pam"
@@ -43,6 +44,19 @@ func (s *WalletService) getTransactionsMapper(req BaseRequest, providerTransacti
 	}
 }
 
+func (s *WalletService) getRoundTransactionsMapper(req BaseRequest, gameRoundID string) pam.GetTransactionsRequestMapper {
+	return func() (context.Context, pam.GetTransactionsRequest, error) {
+		return s.ctx, pam.GetTransactionsRequest{
+			PlayerID: req.UserID,
+			Params: pam.GetTransactionsParams{
+				Provider:        ProviderName,
+				XPlayerToken:    req.Token,
+				ProviderRoundId: &gameRoundID,
+			},
+		}, nil
+	}
+}
+
 func (s *WalletService) getGameRoundMapper(req BaseRequest, gameRoundID string) pam.GetGameRoundRequestMapper {
 	return func() (context.Context, pam.GetGameRoundRequest, error) {
 		return s.ctx, pam.GetGameRoundRequest{
@@ -84,6 +98,7 @@ func (s *WalletService) getPayoutTransactionMapper(req PayoutRequest, transType
 				ProviderRoundId:       roundID,
 				IsGameOver:            &isGameOver,
 				Provider:              ProviderName,
+				Jackpots:              payoutJackpotMapper(req),
 			},
 		}, nil
 	}
@@ -108,6 +123,7 @@ func (s *WalletService) getStakeTransactionMapper(req StakeRequest, transType pa
 				ProviderRoundId:       &req.Round.ID,
 				IsGameOver:            &req.Round.Ends,
 				Provider:              ProviderName,
+				Jackpots:              stakeJackpotMapper(req),
 			},
 		}, nil
 	}
@@ -136,3 +152,58 @@ func (s *WalletService) getRefundTransactionMapper(req RefundRequest, transType
 		}, nil
 	}
 }
+
+// stakeJackpotMapper maps the part of the stake contributed to jackpots
+func stakeJackpotMapper(req StakeRequest) *[]pam.Jackpot {
+	contribution := req.Transaction.Details.Jackpot
+	if contribution.Equal(zeroMoney()) {
+		return nil
+	}
+	return &[]pam.Jackpot{newJackpot(nil, nil, contribution.toAmount(), req.Currency)}
+}
+
+// payoutJackpotMapper maps the jackpot pots won in a payout. If the payout has no pot
+// details the jackpot part of the payout is mapped as a single jackpot.
+func payoutJackpotMapper(req PayoutRequest) *[]pam.Jackpot {
+	var group *string
+	if req.Jackpot.Group != "" {
+		group = &req.Jackpot.Group
+	}
+
+	pots := make([]string, 0, len(req.Transaction.Sources.Jackpot))
+	for pot := range req.Transaction.Sources.Jackpot {
+		pots = append(pots, pot)
+	}
+	sort.Strings(pots)
+
+	jackpots := make([]pam.Jackpot, 0, len(pots))
+	for _, pot := range pots {
+		won := req.Transaction.Sources.Jackpot[pot]
+		if won.Equal(JackpotMoney(pam.ZeroAmount)) {
+			continue
+		}
+		potID := pot
+		jackpots = append(jackpots, newJackpot(&potID, group, pam.Amount(won), req.Currency))
+	}
+
+	if len(jackpots) == 0 {
+		won := req.Transaction.Details.Jackpot
+		if won.Equal(JackpotMoney(pam.ZeroAmount)) {
+			return nil
+		}
+		jackpots = append(jackpots, newJackpot(nil, group, pam.Amount(won), req.Currency))
+	}
+	return &jackpots
+}
+
+func newJackpot(id, reference *string, amount pam.Amount, currency string) pam.Jackpot {
+	return pam.Jackpot{
+		JackpotId:        id,
+		JackpotReference: reference,
+		JackpotAmount:    &amount,
+		JackpotBuckets: &[]pam.JackpotBucket{{
+			BucketAmount: &amount,
+			Currency:     &currency,
+		}},
+	}
+}
@@ -0,0 +1,81 @@
+package redtiger
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+func TestStakeJackpotMapper(t *testing.T) {
+	tests := []struct {
+		name string
+		req  StakeRequest
+		want *[]pam.Jackpot
+	}{
+		{
+			"No contribution maps no jackpots",
+			StakeRequest{BaseRequest: BaseRequest{Currency: "EUR"}},
+			nil,
+		},
+		{
+			"Contribution is mapped",
+			StakeRequest{
+				BaseRequest: BaseRequest{Currency: "EUR"},
+				Transaction: TransactionStake{Details: StakeDetails{Jackpot: Money(decimal.NewFromFloat(0.12))}},
+			},
+			&[]pam.Jackpot{newJackpot(nil, nil, testutils.NewFloatAmount(0.12), "EUR")},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.want, stakeJackpotMapper(test.req))
+		})
+	}
+}
+
+func TestPayoutJackpotMapper(t *testing.T) {
+	tests := []struct {
+		name string
+		req  PayoutRequest
+		want *[]pam.Jackpot
+	}{
+		{
+			"No jackpot win maps no jackpots",
+			PayoutRequest{BaseRequest: BaseRequest{Currency: "EUR"}},
+			nil,
+		},
+		{
+			"Won pots are mapped",
+			PayoutRequest{
+				BaseRequest: BaseRequest{Currency: "EUR"},
+				Jackpot:     Jackpot{Group: "group1"},
+				Transaction: TransactionPayout{Sources: Sources{Jackpot: map[string]JackpotMoney{
+					"pot2": JackpotMoney(decimal.NewFromFloat(20)),
+					"pot1": JackpotMoney(decimal.NewFromFloat(10)),
+					"pot3": JackpotMoney(decimal.Zero),
+				}}},
+			},
+			&[]pam.Jackpot{
+				newJackpot(testutils.Ptr("pot1"), testutils.Ptr("group1"), testutils.NewFloatAmount(10), "EUR"),
+				newJackpot(testutils.Ptr("pot2"), testutils.Ptr("group1"), testutils.NewFloatAmount(20), "EUR"),
+			},
+		},
+		{
+			"Jackpot payout without pots is mapped as single jackpot",
+			PayoutRequest{
+				BaseRequest: BaseRequest{Currency: "EUR"},
+				Transaction: TransactionPayout{Details: PayoutDetails{Jackpot: JackpotMoney(decimal.NewFromFloat(30))}},
+			},
+			&[]pam.Jackpot{newJackpot(nil, nil, testutils.NewFloatAmount(30), "EUR")},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.want, payoutJackpotMapper(test.req))
+		})
+	}
+}
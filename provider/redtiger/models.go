@@ -3,10 +3,21 @@ package redtiger
 import (
 	"github.com/shopspring/decimal"
 
+	"github.com/valkyrie-fnd/valkyrie/money"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
+const (
+	// moneyPlaces number of decimals of amounts
+	moneyPlaces = 2
+	// jackpotMoneyPlaces number of decimals of jackpot amounts
+	jackpotMoneyPlaces = 6
+)
+
+// Money amount with two decimals
 type Money pam.Amt
+
+// JackpotMoney jackpot amount with six decimals
 type JackpotMoney pam.Amt
 
 func zeroMoney() Money {
@@ -22,7 +33,7 @@ func (m JackpotMoney) Equal(b JackpotMoney) bool {
 }
 
 func (m JackpotMoney) MarshalJSON() ([]byte, error) {
-	str := "\"" + decimal.Decimal(m).StringFixed(6) + "\""
+	str := "\"" + decimal.Decimal(m).StringFixed(jackpotMoneyPlaces) + "\""
 	return []byte(str), nil
 }
 
@@ -33,12 +44,25 @@ func (m *JackpotMoney) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// toBalance converts a PAM balance to Money, rounded down to the decimals of the currency, at most two, to never
+// report more than the player has
+func toBalance(b pam.Balance, currency string) Balance {
+	places := int32(moneyPlaces)
+	if c, found := money.Lookup(currency); found && c.MinorUnits < places {
+		places = c.MinorUnits
+	}
+	return Balance{
+		Cash:  Money(money.RoundDown.Round(decimal.Decimal(b.CashAmount), places)),
+		Bonus: Money(money.RoundDown.Round(decimal.Decimal(b.BonusAmount), places)),
+	}
+}
+
 func (m Money) toAmount() pam.Amount {
 	return pam.Amount(m)
 }
 
 func (m Money) MarshalJSON() ([]byte, error) {
-	str := "\"" + decimal.Decimal(m).StringFixed(2) + "\""
+	str := "\"" + decimal.Decimal(m).StringFixed(moneyPlaces) + "\""
 	return []byte(str), nil
 }
 
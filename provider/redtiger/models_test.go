@@ -3,8 +3,11 @@ package redtiger
 import (
 	"encoding/json"
 	"testing"
+	"testing/quick"
 
 	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
+	"github.com/valkyrie-fnd/valkyrie/money"
+	"github.com/valkyrie-fnd/valkyrie/pam"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -124,6 +127,57 @@ func Test_rtJackpotMoney_unmarshal(t *testing.T) {
 	}
 }
 
+func Test_rtMoney_roundTrip(t *testing.T) {
+	property := func(cents int64) bool {
+		m := Money(money.FromUnits(cents, moneyPlaces))
+		bytes, err := json.Marshal(m)
+		if err != nil {
+			return false
+		}
+		var res Money
+		return json.Unmarshal(bytes, &res) == nil && res.Equal(m)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func Test_rtJackpotMoney_roundTrip(t *testing.T) {
+	property := func(units int64) bool {
+		m := JackpotMoney(money.FromUnits(units, jackpotMoneyPlaces))
+		bytes, err := json.Marshal(m)
+		if err != nil {
+			return false
+		}
+		var res JackpotMoney
+		return json.Unmarshal(bytes, &res) == nil && res.Equal(m)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func Test_toBalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		cash     string
+		want     string
+	}{
+		{"rounded down to cents", "EUR", "10.999999", `{"cash": "10.99", "bonus": "0.00"}`},
+		{"rounded down to currency without minor units", "JPY", "100.5", `{"cash": "100.00", "bonus": "0.00"}`},
+		{"rounded down to cents for currency with more decimals", "KWD", "1.239", `{"cash": "1.23", "bonus": "0.00"}`},
+		{"rounded down to cents for unknown currency", "XYZ", "1.239", `{"cash": "1.23", "bonus": "0.00"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			balance := toBalance(pam.Balance{
+				CashAmount:  pam.Amount(decimal.RequireFromString(test.cash)),
+				BonusAmount: pam.ZeroAmount,
+			}, test.currency)
+			res, err := json.Marshal(balance)
+			assert.NoError(tt, err)
+			assert.JSONEq(tt, test.want, string(res))
+		})
+	}
+}
+
 func (m Money) string() string {
 	return decimal.Decimal(m).String()
 }
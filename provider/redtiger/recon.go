@@ -0,0 +1,162 @@
+package redtiger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valkyrie-fnd/valkyrie/ops"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+)
+
+// Red Tiger reconciliation requests are sent with the configured recon token instead of a
+// player session token. They are used to settle (payout) or refund rounds after the player
+// session is gone. The recon token is forwarded to the PAM as reconciliation token, described
+// in the Reconciliation section of the PAM API, and the player given by Red Tiger is verified
+// by looking up the round or stake of the player before the transaction is forwarded.
+
+type reconCtxKey struct{}
+
+const reconAttributeKey = "redtiger.recon"
+
+// withRecon returns a copy of ctx flagged as a reconciliation request
+func withRecon(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reconCtxKey{}, true)
+}
+
+// isRecon returns true if ctx belongs to a reconciliation request
+func isRecon(ctx context.Context) bool {
+	recon, _ := ctx.Value(reconCtxKey{}).(bool)
+	return recon
+}
+
+// tagReconToken flags requests made with the recon token, so that they can be handled
+// without a player session. Recon requests are also tagged in logs, traces and metrics.
+func tagReconToken(reconToken string) fiber.Handler {
+	reconRequests, err := otel.Meter(ProviderName).Int64Counter("redtiger.recon.requests",
+		metric.WithDescription("measures the number of reconciliation requests received from Red Tiger"))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to create recon request counter")
+	}
+
+	return func(c *fiber.Ctx) error {
+		if reconToken == "" {
+			return c.Next()
+		}
+		var baseReq BaseRequest
+		if err := c.BodyParser(&baseReq); err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(newRTErrorResponse(fmt.Sprintf("Invalid base request. err: %s", err.Error()), InvalidInput))
+		}
+		if baseReq.Token != reconToken {
+			return c.Next()
+		}
+
+		c.SetUserContext(withRecon(c.UserContext()))
+		ops.AddLoggingContext(c, reconAttributeKey, "true")
+		trace.SpanFromContext(c.UserContext()).SetAttributes(attribute.Bool(reconAttributeKey, true))
+		if reconRequests != nil {
+			reconRequests.Add(c.UserContext(), 1, metric.WithAttributes(attribute.String("path", c.Route().Path)))
+		}
+
+		return c.Next()
+	}
+}
+
+// resolveReconPayout verifies that the round being paid out was started by the player
+// with a stake, since there is no player session to validate the payout against. Game
+// rounds are looked up per player, so a round of another player is not found. With
+// currency conversion, the stake of the round is returned to settle the payout in its
+// wallet currency.
+func (s *WalletService) resolveReconPayout(req *PayoutRequest, transType pam.TransactionType) (*pam.Transaction, error) {
+	if req.UserID == "" {
+		return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrReqInput, ErrMsg: "recon payout without userId"}
+	}
+	// Promo payouts are not necessarily tied to a game round
+	if transType == pam.PROMODEPOSIT && req.Round.ID == "" {
+		// Without a round there is no stake to take the wallet currency from
+		if s.exchange != nil {
+			return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrReqInput, ErrMsg: "recon promo payout without round not supported with currency conversion"}
+		}
+		return nil, nil
+	}
+
+	gameRound, err := s.pamClient.GetGameRound(s.getGameRoundMapper(req.BaseRequest, req.Round.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve recon payout: %w", err)
+	}
+	if gameRound == nil || gameRound.ProviderRoundId != req.Round.ID {
+		return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrOpRoundNotFound, ErrMsg: "recon payout round not found"}
+	}
+	if req.Game.Key == "" {
+		req.Game.Key = gameRound.ProviderGameId
+	}
+	if s.exchange == nil {
+		return nil, nil
+	}
+
+	transactions, err := s.pamClient.GetTransactions(s.getRoundTransactionsMapper(req.BaseRequest, req.Round.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve recon payout: %w", err)
+	}
+	stake, found := findStake(transactions)
+	if !found {
+		return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrOpTransNotFound, ErrMsg: "recon payout stake not found"}
+	}
+	if req.Currency == "" {
+		req.Currency = playCurrency(stake)
+	}
+	return stake, nil
+}
+
+// resolveReconRefund looks up the stake transaction being refunded, and uses it to fill in
+// details that would otherwise have been derived from the player session. Transactions are
+// looked up per player, so a stake of another player is not found.
+func (s *WalletService) resolveReconRefund(req *RefundRequest) (*pam.Transaction, error) {
+	if req.UserID == "" {
+		return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrReqInput, ErrMsg: "recon refund without userId"}
+	}
+
+	transactions, err := s.pamClient.GetTransactions(s.getTransactionsMapper(req.BaseRequest, req.Transaction.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve recon refund: %w", err)
+	}
+
+	stake, found := findStake(transactions)
+	if !found {
+		return nil, pam.ValkyrieError{ValkErrorCode: pam.ValkErrOpTransNotFound, ErrMsg: "recon refund stake not found"}
+	}
+	if req.Currency == "" {
+		req.Currency = playCurrency(stake)
+	}
+	if req.Game.Key == "" && stake.ProviderGameId != nil {
+		req.Game.Key = *stake.ProviderGameId
+	}
+	if req.Round.ID == "" && stake.ProviderRoundId != nil {
+		req.Round.ID = *stake.ProviderRoundId
+	}
+
+	return stake, nil
+}
+
+func findStake(transactions []pam.Transaction) (*pam.Transaction, bool) {
+	for i, t := range transactions {
+		if t.TransactionType == pam.WITHDRAW || t.TransactionType == pam.PROMOWITHDRAW {
+			return &transactions[i], true
+		}
+	}
+	return nil, false
+}
+
+// playCurrency returns the currency stake was played in
+func playCurrency(stake *pam.Transaction) string {
+	if stake.CurrencyConversion != nil {
+		return stake.CurrencyConversion.ProviderCurrency
+	}
+	return stake.Currency
+}
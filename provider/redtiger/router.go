@@ -6,8 +6,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
+	"github.com/valkyrie-fnd/valkyrie/ops/redact"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 	"github.com/valkyrie-fnd/valkyrie/provider"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
 )
 
 const (
@@ -15,19 +18,23 @@ const (
 )
 
 func init() {
+	provider.RegisterErrorMapping(ProviderName, provider.NewErrorMapping(errCodes))
+	redact.Register("token")
 	provider.ProviderFactory().
 		Register(ProviderName, func(args provider.ProviderArgs) (*provider.Router, error) {
 			if args.PamClient.GetTransactionSupplier() == pam.PROVIDER {
 				return nil, fmt.Errorf("unsupported transaction supplier")
 			}
-			service := NewService(args.PamClient)
+			service := NewService(args.PamClient).
+				WithExchange(fx.NewExchange(args.Converter, args.PamClient, ProviderName))
 			controller := NewProviderController(service)
 			return NewProviderRouter(args.Config, controller)
 		})
 	provider.OperatorFactory().
 		Register(ProviderName, func(args provider.OperatorArgs) (*provider.Router, error) {
-			return NewOperatorRouter(args.Config), nil
+			return NewOperatorRouter(args.Config, args.Converter), nil
 		})
+	simulation.Factory().Register(ProviderName, NewSimulator)
 }
 
 type Controller interface {
@@ -61,11 +68,13 @@ func NewProviderRouter(config configs.ProviderConf, controller Controller) (*pro
 			Path:        "/payout",
 			Method:      "POST",
 			HandlerFunc: controller.Payout,
+			Middlewares: []fiber.Handler{tagReconToken(auth.ReconToken)},
 		},
 		{
 			Path:        "/refund",
 			Method:      "POST",
 			HandlerFunc: controller.Refund,
+			Middlewares: []fiber.Handler{tagReconToken(auth.ReconToken)},
 		},
 		{
 			Path:        "/promo/buyin",
@@ -77,11 +86,13 @@ func NewProviderRouter(config configs.ProviderConf, controller Controller) (*pro
 			Path:        "/promo/settle",
 			Method:      "POST",
 			HandlerFunc: controller.PromoSettle,
+			Middlewares: []fiber.Handler{tagReconToken(auth.ReconToken)},
 		},
 		{
 			Path:        "/promo/refund",
 			Method:      "POST",
 			HandlerFunc: controller.PromoRefund,
+			Middlewares: []fiber.Handler{tagReconToken(auth.ReconToken)},
 		},
 	}
 	return &provider.Router{
@@ -95,11 +106,11 @@ func NewProviderRouter(config configs.ProviderConf, controller Controller) (*pro
 }
 
 // NewOperatorRouter Routes operator calls to execute actions toward the provider
-func NewOperatorRouter(config configs.ProviderConf) *provider.Router {
+func NewOperatorRouter(config configs.ProviderConf, converter *fx.Converter) *provider.Router {
 	rtService := RedTigerService{
 		Conf: &config,
 	}
-	glController := provider.NewGameLaunchController(rtService)
+	glController := provider.NewGameLaunchController(rtService).WithConverter(converter)
 
 	grCtrl := provider.NewGameRoundController(rtService)
 	routes := []provider.Route{
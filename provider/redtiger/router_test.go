@@ -254,6 +254,65 @@ func TestDeclineTokenMiddleware(t *testing.T) {
 	}
 }
 
+func TestTagReconTokenMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		userToken     string
+		expectedRecon bool
+	}{
+		{"Payout with individual token is not recon", "/test/redtiger/payout", "Some-secret-token", false},
+		{"Payout with recon token is recon", "/test/redtiger/payout", "recon", true},
+		{"Refund with recon token is recon", "/test/redtiger/refund", "recon", true},
+		{"Promo settle with recon token is recon", "/test/redtiger/promo/settle", "recon", true},
+		{"Promo refund with recon token is recon", "/test/redtiger/promo/refund", "recon", true},
+	}
+	controller := &reconController{}
+	router, _ := NewProviderRouter(configs.ProviderConf{
+		Auth:     map[string]any{"api_key": "pelle", "recon_token": "recon"},
+		BasePath: "/redtiger",
+	}, controller)
+	app := fiber.New()
+	reg := provider.NewRegistry(app, "/test")
+	_ = reg.Register(router)
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			baseRequest := fmt.Sprintf(`{"token":"%s", "userId":"1", "currency":"USD"}`, test.userToken)
+			req := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(baseRequest))
+			req.Header.Add("Authorization", "Basic pelle")
+			req.Header.Add("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, http.StatusOK, resp.StatusCode)
+			assert.Equal(tt, test.expectedRecon, controller.recon)
+		})
+	}
+}
+
+// reconController records whether the last request was flagged as recon
+type reconController struct {
+	NilController
+	recon bool
+}
+
+func (rc *reconController) record(c *fiber.Ctx) error {
+	rc.recon = isRecon(c.UserContext())
+	return nil
+}
+func (rc *reconController) Payout(c *fiber.Ctx) error {
+	return rc.record(c)
+}
+func (rc *reconController) Refund(c *fiber.Ctx) error {
+	return rc.record(c)
+}
+func (rc *reconController) PromoSettle(c *fiber.Ctx) error {
+	return rc.record(c)
+}
+func (rc *reconController) PromoRefund(c *fiber.Ctx) error {
+	return rc.record(c)
+}
+
 type NilController struct{}
 
 func (nc *NilController) Auth(_ *fiber.Ctx) error {
@@ -0,0 +1,95 @@
+package redtiger
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+const simulatorSuccess = "success"
+
+// simulator sends wallet requests the way Red Tiger does, authenticated by the api key
+type simulator struct {
+	client valkhttp.HTTPClient
+	url    string
+	apiKey string
+}
+
+// simulatorResponse fields of the wallet responses used by the simulator
+type simulatorResponse struct {
+	Success *bool  `json:"success"`
+	Error   *Error `json:"error"`
+	Result  struct {
+		Token string `json:"token"`
+	} `json:"result"`
+}
+
+// NewSimulator creates a simulator using the "api_key" auth configuration
+func NewSimulator(args simulation.Args) (simulation.Simulator, error) {
+	auth, err := GetAuthConf(args.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &simulator{client: args.HTTPClient, url: args.URL, apiKey: auth.APIKey}, nil
+}
+
+// post sends the request, using "success" as code of successful responses and the error code otherwise
+func (s *simulator) post(ctx context.Context, path string, req any) (simulation.Response, error) {
+	var resp simulatorResponse
+	err := s.client.Post(ctx, &valkhttp.JSONParser, &valkhttp.HTTPRequest{
+		URL:     s.url + path,
+		Headers: map[string]string{"Authorization": "Basic " + s.apiKey},
+		Body:    req,
+	}, &resp)
+	switch {
+	case resp.Error != nil:
+		return simulation.Response{Code: strconv.Itoa(int(resp.Error.Code))}, nil
+	case resp.Success != nil && *resp.Success:
+		return simulation.Response{Code: simulatorSuccess, OK: true, Token: resp.Result.Token}, nil
+	default:
+		return simulation.Response{}, err
+	}
+}
+
+func simulatorBaseRequest(session simulation.Session) BaseRequest {
+	return BaseRequest{Token: session.Token, UserID: session.PlayerID, Casino: "simulation", Currency: session.Currency}
+}
+
+func simulatorGame(session simulation.Session) Game {
+	return Game{Type: "slot", Key: session.GameID, Version: "1"}
+}
+
+func (s *simulator) Authenticate(ctx context.Context, session simulation.Session) (simulation.Response, error) {
+	return s.post(ctx, "/auth", &AuthRequest{BaseRequest: simulatorBaseRequest(session)})
+}
+
+func (s *simulator) Bet(ctx context.Context, session simulation.Session, bet simulation.Bet) (simulation.Response, error) {
+	return s.post(ctx, "/stake", &StakeRequest{
+		BaseRequest: simulatorBaseRequest(session),
+		Transaction: TransactionStake{ID: bet.TransactionID, Stake: Money(pam.Amt(bet.Amount)), StakePromo: zeroMoney()},
+		Game:        simulatorGame(session),
+		Round:       Round{ID: bet.RoundID, Starts: true},
+	})
+}
+
+func (s *simulator) Win(ctx context.Context, session simulation.Session, win simulation.Win) (simulation.Response, error) {
+	return s.post(ctx, "/payout", &PayoutRequest{
+		BaseRequest: simulatorBaseRequest(session),
+		Transaction: TransactionPayout{ID: win.TransactionID, Payout: Money(pam.Amt(win.Amount)), PayoutPromo: zeroMoney()},
+		Game:        simulatorGame(session),
+		Round:       Round{ID: win.Bet.RoundID, Ends: true},
+	})
+}
+
+// Rollback refunds the stake transaction, Red Tiger has no separate rollback transaction id
+func (s *simulator) Rollback(ctx context.Context, session simulation.Session, rollback simulation.Rollback) (simulation.Response, error) {
+	return s.post(ctx, "/refund", &RefundRequest{
+		BaseRequest: simulatorBaseRequest(session),
+		Transaction: TransactionStake{ID: rollback.Bet.TransactionID, Stake: Money(pam.Amt(rollback.Bet.Amount)), StakePromo: zeroMoney()},
+		Game:        simulatorGame(session),
+		Round:       Round{ID: rollback.Bet.RoundID, Ends: true},
+	})
+}
@@ -0,0 +1,41 @@
+package redtiger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/provider/providertest"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+func TestSimulator(t *testing.T) {
+	env := providertest.NewEnv(t, conformanceAdapter{})
+	client, err := valkhttp.Create(configs.HTTPClientConfig{RequestTimeout: time.Second})
+	require.NoError(t, err)
+	simulator, err := simulation.Factory().Build(ProviderName, simulation.Args{
+		URL:        env.Serve(t),
+		Config:     conformanceAdapter{}.Config(),
+		HTTPClient: client,
+	})
+	require.NoError(t, err)
+
+	report, err := simulation.Run(context.Background(), simulator, simulation.Options{
+		Sessions:   []simulation.Session{{Token: env.Session.Token, PlayerID: env.Session.PlayerID, Currency: env.Session.Currency, GameID: env.Session.GameID}},
+		Iterations: 2,
+		Script:     simulation.Script{Bets: 1, BetAmount: decimal.NewFromInt(10), WinAmount: decimal.NewFromInt(5), Rollback: true},
+	})
+	require.NoError(t, err)
+	assert.Zero(t, report.Failed())
+	require.NotNil(t, report.Requests(simulation.PlaceBet))
+	assert.Equal(t, map[string]int{simulatorSuccess: 4}, report.Requests(simulation.PlaceBet).Codes)
+	env.ExpectLedger(t, decimal.NewFromInt(90),
+		pam.WITHDRAW, pam.DEPOSIT, pam.WITHDRAW, pam.CANCEL, pam.WITHDRAW, pam.DEPOSIT, pam.WITHDRAW, pam.CANCEL)
+}
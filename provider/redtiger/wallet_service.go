@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
 
 type WalletService struct {
 	pamClient pam.PamClient
 	ctx       context.Context
+	exchange  *fx.Exchange
 }
 
 // NewService Create new red tiger provider service
@@ -19,7 +21,12 @@ func NewService(pamClient pam.PamClient) *WalletService {
 }
 
 func (s *WalletService) WithContext(ctx context.Context) Service {
-	return &WalletService{pamClient: s.pamClient, ctx: ctx}
+	return &WalletService{pamClient: s.pamClient, ctx: ctx, exchange: s.exchange}
+}
+
+// WithExchange returns a service converting wallet amounts between the play currency and the wallet currency
+func (s *WalletService) WithExchange(exchange *fx.Exchange) *WalletService {
+	return &WalletService{pamClient: s.pamClient, ctx: s.ctx, exchange: exchange}
 }
 
 // Auth implements Service
@@ -47,7 +54,16 @@ func (s *WalletService) Auth(req AuthRequest) (*AuthResponseWrapper, *ErrorRespo
 		req.UserID = session.PlayerId
 	}
 
+	// The game is played in the currency it was launched with, if converted from the wallet currency
+	currency := session.Currency
+	if s.exchange != nil && req.Currency != "" {
+		currency = req.Currency
+	}
+
 	balance, err := s.pamClient.GetBalance(s.getBalanceMapper(req.BaseRequest))
+	if err == nil {
+		err = s.exchange.Balance(s.ctx, req.Token, "", currency, balance)
+	}
 	if err != nil {
 		e := createRtErrorResponse(fmt.Errorf("failed to Auth: %w", err))
 		return nil, &e
@@ -58,16 +74,13 @@ func (s *WalletService) Auth(req AuthRequest) (*AuthResponseWrapper, *ErrorRespo
 		Result: AuthResponse{
 			BaseResponse: BaseResponse{
 				Token:    session.Token,
-				Currency: session.Currency,
+				Currency: currency,
 			},
 			UserID:   req.UserID,
 			Country:  session.Country,
 			Language: session.Language,
 			Casino:   req.Casino,
-			Balance: Balance{
-				Cash:  Money(balance.CashAmount),
-				Bonus: Money(balance.BonusAmount),
-			},
+			Balance:  toBalance(*balance, currency),
 		},
 	}, nil
 }
@@ -196,7 +209,7 @@ func handleStake(s *WalletService, req StakeRequest, transType pam.TransactionTy
 			return nil, &e
 		}
 	}
-	transactionResult, err := s.pamClient.AddTransaction(s.getStakeTransactionMapper(req, transType))
+	transactionResult, err := s.addTransaction(req.BaseRequest, s.getStakeTransactionMapper(req, transType))
 	if err != nil {
 		e := createRtErrorResponse(err)
 		return nil, &e
@@ -221,10 +234,7 @@ func handleStake(s *WalletService, req StakeRequest, transType pam.TransactionTy
 				Cash:  req.Transaction.Stake,
 				Bonus: zeroMoney(),
 			},
-			Balance: Balance{
-				Cash:  Money(transactionResult.Balance.CashAmount),
-				Bonus: Money(transactionResult.Balance.BonusAmount),
-			},
+			Balance: toBalance(*transactionResult.Balance, req.Currency),
 		},
 	}
 
@@ -232,7 +242,19 @@ func handleStake(s *WalletService, req StakeRequest, transType pam.TransactionTy
 }
 
 func handlePayout(s *WalletService, req PayoutRequest, transType pam.TransactionType) (*PayoutResponseWrapper, *ErrorResponse) {
-	transactionResult, err := s.pamClient.AddTransaction(s.getPayoutTransactionMapper(req, transType))
+	pamReq := req
+	var transactionResult *pam.TransactionResult
+	var err error
+	if isRecon(s.ctx) {
+		var stake *pam.Transaction
+		if stake, err = s.resolveReconPayout(&pamReq, transType); err != nil {
+			e := createRtErrorResponse(err)
+			return nil, &e
+		}
+		transactionResult, err = s.settleStake(pamReq.Currency, stake, false, s.getPayoutTransactionMapper(pamReq, transType))
+	} else {
+		transactionResult, err = s.addTransaction(pamReq.BaseRequest, s.getPayoutTransactionMapper(pamReq, transType))
+	}
 	if err != nil {
 		e := createRtErrorResponse(err)
 		return nil, &e
@@ -259,16 +281,25 @@ func handlePayout(s *WalletService, req PayoutRequest, transType pam.Transaction
 				Cash:  req.Transaction.Payout,
 				Bonus: zeroMoney(),
 			},
-			Balance: Balance{
-				Cash:  Money(transactionResult.Balance.CashAmount),
-				Bonus: Money(transactionResult.Balance.BonusAmount),
-			},
+			Balance: toBalance(*transactionResult.Balance, req.Currency),
 		},
 	}, nil
 }
 
 func handleRefund(s *WalletService, req RefundRequest, transType pam.TransactionType) (*RefundResponseWrapper, *ErrorResponse) {
-	transactionResult, err := s.pamClient.AddTransaction(s.getRefundTransactionMapper(req, transType))
+	pamReq := req
+	var transactionResult *pam.TransactionResult
+	var err error
+	if isRecon(s.ctx) {
+		var stake *pam.Transaction
+		if stake, err = s.resolveReconRefund(&pamReq); err != nil {
+			e := createRtErrorResponse(err)
+			return nil, &e
+		}
+		transactionResult, err = s.settleStake(pamReq.Currency, stake, true, s.getRefundTransactionMapper(pamReq, transType))
+	} else {
+		transactionResult, err = s.addTransaction(pamReq.BaseRequest, s.getRefundTransactionMapper(pamReq, transType))
+	}
 	if err != nil {
 		e := createRtErrorResponse(err)
 		return nil, &e
@@ -293,10 +324,31 @@ func handleRefund(s *WalletService, req RefundRequest, transType pam.Transaction
 				Bonus: zeroMoney(),
 			},
 		},
-		Balance: Balance{
-			Cash:  Money(transactionResult.Balance.CashAmount),
-			Bonus: Money(transactionResult.Balance.BonusAmount),
-		},
+		Balance: toBalance(*transactionResult.Balance, req.Currency),
 	}
 	return &resp, nil
 }
+
+// addTransaction adds the transaction converted to the wallet currency, returning the balance in the play currency
+func (s *WalletService) addTransaction(req BaseRequest, mapper pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	transactionResult, err := s.pamClient.AddTransaction(s.exchange.Transaction(mapper))
+	if err != nil {
+		return nil, err
+	}
+	return transactionResult, s.exchange.Balance(s.ctx, req.Token, "", req.Currency, transactionResult.Balance)
+}
+
+// settleStake adds a payout or refund of stake like addTransaction, converted to the wallet currency of the stake,
+// since recon requests have no player session to resolve the wallet currency from. Refunds are converted at the
+// rate of the stake, payouts at the current rate. Without stake, such as for promo payouts without a round when
+// currency conversion is not configured, the transaction is added unconverted.
+func (s *WalletService) settleStake(currency string, stake *pam.Transaction, stakeRate bool, mapper pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	if stake == nil {
+		return s.pamClient.AddTransaction(mapper)
+	}
+	transactionResult, err := s.pamClient.AddTransaction(s.exchange.Settlement(mapper, stake, stakeRate))
+	if err != nil {
+		return nil, err
+	}
+	return transactionResult, s.exchange.SettlementBalance(s.ctx, stake, currency, transactionResult.Balance)
+}
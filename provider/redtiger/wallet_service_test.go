@@ -1,12 +1,16 @@
 package redtiger
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/valkyrie-fnd/valkyrie/internal/testutils"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/valkyrie-fnd/valkyrie/pam"
 )
@@ -440,6 +444,129 @@ func Test_UnknownUserResponse(t *testing.T) {
 	}
 }
 
+func TestReconPayout(t *testing.T) {
+	tests := []struct {
+		name             string
+		getGameRoundFn   func() (*pam.GameRound, error)
+		addTransactionFn func() (*pam.TransactionResult, error)
+		req              PayoutRequest
+		transType        pam.TransactionType
+		wantErr          *ErrorResponse
+	}{
+		{
+			"Payout is forwarded when round was started by the player",
+			func() (*pam.GameRound, error) {
+				return &pam.GameRound{ProviderRoundId: "round1", ProviderGameId: "game1"}, nil
+			},
+			func() (*pam.TransactionResult, error) {
+				return &pam.TransactionResult{TransactionId: testutils.Ptr("1")}, nil
+			},
+			PayoutRequest{BaseRequest: BaseRequest{Token: "recon", UserID: "1"}, Round: Round{ID: "round1"}},
+			pam.DEPOSIT,
+			nil,
+		},
+		{
+			"Payout fails when round is not found",
+			func() (*pam.GameRound, error) {
+				return nil, pam.ValkyrieError{ErrMsg: "not found", ValkErrorCode: pam.ValkErrOpRoundNotFound}
+			},
+			nil,
+			PayoutRequest{BaseRequest: BaseRequest{Token: "recon", UserID: "1"}, Round: Round{ID: "round1"}},
+			pam.DEPOSIT,
+			&ErrorResponse{
+				Error: Error{
+					Message: "failed to resolve recon payout: Code: 38, Msg: not found",
+					Code:    TransactionNotFound,
+				},
+			},
+		},
+		{
+			"Payout fails without user id",
+			nil,
+			nil,
+			PayoutRequest{BaseRequest: BaseRequest{Token: "recon"}, Round: Round{ID: "round1"}},
+			pam.DEPOSIT,
+			&ErrorResponse{
+				Error: Error{
+					Message: "Code: 37, Msg: recon payout without userId",
+					Code:    InvalidInput,
+				},
+			},
+		},
+		{
+			"Promo payout without round is forwarded",
+			nil,
+			func() (*pam.TransactionResult, error) {
+				return &pam.TransactionResult{TransactionId: testutils.Ptr("1")}, nil
+			},
+			PayoutRequest{BaseRequest: BaseRequest{Token: "recon", UserID: "1"}},
+			pam.PROMODEPOSIT,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			pamStub := pamStub{getGameRoundFn: test.getGameRoundFn, addTransFn: test.addTransactionFn}
+			sut := NewService(&pamStub).WithContext(withRecon(context.Background())).(*WalletService)
+
+			resp, err := handlePayout(sut, test.req, test.transType)
+			assert.Equal(tt, test.wantErr, err)
+			if test.wantErr == nil {
+				assert.True(tt, resp.Success)
+				assert.Equal(tt, test.req.Token, resp.Result.Token)
+			}
+		})
+	}
+}
+
+func TestReconRefund(t *testing.T) {
+	tests := []struct {
+		name             string
+		getTransFn       func() ([]pam.Transaction, error)
+		wantErr          *ErrorResponse
+		expectedCurrency string
+	}{
+		{
+			"Refund is forwarded with details from the original stake",
+			func() ([]pam.Transaction, error) {
+				return []pam.Transaction{{TransactionType: pam.WITHDRAW, Currency: "EUR"}}, nil
+			},
+			nil,
+			"EUR",
+		},
+		{
+			"Refund fails when no stake is found",
+			func() ([]pam.Transaction, error) {
+				return []pam.Transaction{{TransactionType: pam.DEPOSIT, Currency: "EUR"}}, nil
+			},
+			&ErrorResponse{
+				Error: Error{
+					Message: "Code: 19, Msg: recon refund stake not found",
+					Code:    TransactionNotFound,
+				},
+			},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			pamStub := pamStub{getTransFn: test.getTransFn}
+			sut := NewService(&pamStub).WithContext(withRecon(context.Background())).(*WalletService)
+			req := RefundRequest{BaseRequest: BaseRequest{Token: "recon", UserID: "1"}, Transaction: TransactionStake{ID: "stake1"}}
+
+			_, err := sut.resolveReconRefund(&req)
+			if test.wantErr != nil {
+				assert.Equal(tt, test.wantErr, testutils.Ptr(createRtErrorResponse(err)))
+			} else {
+				assert.NoError(tt, err)
+			}
+			assert.Equal(tt, test.expectedCurrency, req.Currency)
+		})
+	}
+}
+
 type pamStub struct {
 	pam.PamClient
 	balanceFn        func() (*pam.Balance, error)
@@ -473,3 +600,97 @@ func (pam *pamStub) AddTransaction(_ pam.AddTransactionRequestMapper) (*pam.Tran
 func (pam *pamStub) GetGameRound(_ pam.GetGameRoundRequestMapper) (*pam.GameRound, error) {
 	return pam.getGameRoundFn()
 }
+
+// requestRecordingPamStub records the requests sent to the PAM
+type requestRecordingPamStub struct {
+	pamStub
+	transactionsReq pam.GetTransactionsRequest
+	addTransReq     *pam.AddTransactionRequest
+}
+
+func (s *requestRecordingPamStub) GetTransactions(rm pam.GetTransactionsRequestMapper) ([]pam.Transaction, error) {
+	_, s.transactionsReq, _ = rm()
+	return s.pamStub.GetTransactions(rm)
+}
+
+func (s *requestRecordingPamStub) AddTransaction(rm pam.AddTransactionRequestMapper) (*pam.TransactionResult, error) {
+	_, s.addTransReq, _ = rm(pam.SixDecimalRounder)
+	return s.pamStub.AddTransaction(rm)
+}
+
+func TestReconRefund_ReconToken(t *testing.T) {
+	stub := &requestRecordingPamStub{pamStub: pamStub{
+		getTransFn: func() ([]pam.Transaction, error) {
+			return []pam.Transaction{{TransactionType: pam.WITHDRAW, Currency: "EUR"}}, nil
+		},
+		addTransFn: func() (*pam.TransactionResult, error) {
+			return &pam.TransactionResult{TransactionId: testutils.Ptr("1")}, nil
+		},
+	}}
+	sut := NewService(stub).WithContext(withRecon(context.Background())).(*WalletService)
+	req := RefundRequest{BaseRequest: BaseRequest{Token: "recon", UserID: "1"}, Transaction: TransactionStake{ID: "stake1"}}
+
+	_, err := handleRefund(sut, req, pam.CANCEL)
+	require.Nil(t, err)
+	assert.Equal(t, "recon", stub.transactionsReq.Params.XPlayerToken, "the stake is looked up with the recon token")
+	assert.Equal(t, "1", stub.transactionsReq.PlayerID, "the stake is looked up for the player of the refund")
+	require.NotNil(t, stub.addTransReq)
+	assert.Equal(t, "recon", stub.addTransReq.Params.XPlayerToken, "the refund is sent with the recon token")
+	assert.Equal(t, "1", stub.addTransReq.PlayerID)
+	assert.Equal(t, "EUR", stub.addTransReq.Body.Currency)
+}
+
+type rateSourceStub struct{}
+
+func (rateSourceStub) Rate(_ context.Context, from, to string) (fx.Rate, error) {
+	rates := map[string]string{"EUR/SEK": "11.5", "SEK/EUR": "0.086956521739130435"}
+	return fx.Rate{From: from, To: to, Value: decimal.RequireFromString(rates[from+"/"+to])}, nil
+}
+
+func TestRecon_PlayCurrency(t *testing.T) {
+	stub := &requestRecordingPamStub{pamStub: pamStub{
+		getTransFn: func() ([]pam.Transaction, error) {
+			return []pam.Transaction{{
+				TransactionType:    pam.WITHDRAW,
+				Currency:           "SEK",
+				CashAmount:         pam.Amount(decimal.NewFromInt(120)),
+				CurrencyConversion: &pam.CurrencyConversion{ProviderCurrency: "EUR", Rate: "12"},
+			}}, nil
+		},
+		addTransFn: func() (*pam.TransactionResult, error) {
+			return &pam.TransactionResult{TransactionId: testutils.Ptr("1"), Balance: &pam.Balance{CashAmount: pam.Amount(decimal.NewFromInt(1150))}}, nil
+		},
+	}}
+	exchange := fx.NewExchange(fx.NewConverter(rateSourceStub{}), stub, ProviderName)
+	sut := NewService(stub).WithExchange(exchange).WithContext(withRecon(context.Background())).(*WalletService)
+
+	refund, errResp := handleRefund(sut, RefundRequest{
+		BaseRequest: BaseRequest{Token: "recon", UserID: "1"},
+		Transaction: TransactionStake{ID: "stake1", Stake: Money(decimal.NewFromInt(10))},
+	}, pam.CANCEL)
+	require.Nil(t, errResp)
+	require.NotNil(t, stub.addTransReq)
+	assert.Equal(t, "SEK", stub.addTransReq.Body.Currency)
+	assert.Equal(t, "120", decimal.Decimal(stub.addTransReq.Body.CashAmount).String(), "refunded at the rate of the stake")
+	assert.Equal(t, "100", decimal.Decimal(refund.Balance.Cash).String(), "balance in the play currency of the stake")
+
+	stub.getGameRoundFn = func() (*pam.GameRound, error) {
+		return &pam.GameRound{ProviderRoundId: "round1", ProviderGameId: "game1"}, nil
+	}
+	payout, errResp := handlePayout(sut, PayoutRequest{
+		BaseRequest: BaseRequest{Token: "recon", UserID: "1"},
+		Transaction: TransactionPayout{ID: "payout1", Payout: Money(decimal.NewFromInt(10))},
+		Round:       Round{ID: "round1"},
+	}, pam.DEPOSIT)
+	require.Nil(t, errResp)
+	require.NotNil(t, stub.transactionsReq.Params.ProviderRoundId)
+	assert.Equal(t, "round1", *stub.transactionsReq.Params.ProviderRoundId, "the stake is looked up by round")
+	assert.Equal(t, "SEK", stub.addTransReq.Body.Currency)
+	assert.Equal(t, "EUR", stub.addTransReq.Body.CurrencyConversion.ProviderCurrency)
+	assert.Equal(t, "115", decimal.Decimal(stub.addTransReq.Body.CashAmount).String(), "paid out at the current rate")
+	assert.Equal(t, "100", decimal.Decimal(payout.Result.Balance.Cash).String(), "balance in the play currency of the stake")
+
+	_, errResp = handlePayout(sut, PayoutRequest{BaseRequest: BaseRequest{Token: "recon", UserID: "1"}}, pam.PROMODEPOSIT)
+	require.NotNil(t, errResp)
+	assert.Equal(t, "Code: 37, Msg: recon promo payout without round not supported with currency conversion", errResp.Error.Message)
+}
@@ -0,0 +1,7 @@
+// Package simulation sends wallet requests to a running Valkyrie the way a game provider does,
+// to exercise a configured provider endpoint or to load test it.
+//
+// Providers take part by registering a Simulator with Factory, building requests from their
+// own request models and authenticating them like the real provider. Run plays scripted game
+// sessions using a simulator and reports latencies and response codes per request type.
+package simulation
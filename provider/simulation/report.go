@@ -0,0 +1,180 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+// Report latencies and response codes per request type of a simulation run
+type Report struct {
+	Duration time.Duration
+	mu       sync.Mutex
+	requests map[string]*RequestStats
+}
+
+// RequestStats latencies and response codes of a request type
+type RequestStats struct {
+	// Codes number of responses per native code, requests without response are counted by error
+	Codes     map[string]int
+	Failed    int
+	latencies []time.Duration
+	sorted    bool
+}
+
+// NewReport creates an empty report
+func NewReport() *Report {
+	return &Report{requests: map[string]*RequestStats{}}
+}
+
+// Requests returns the stats of a request type, nil if there were no requests of the type
+func (r *Report) Requests(requestType string) *RequestStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests[requestType]
+}
+
+// measure sends a request and records its latency and response code
+func (r *Report) measure(requestType string, send func() (Response, error)) Response {
+	start := time.Now()
+	res, err := send()
+	latency := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats, found := r.requests[requestType]
+	if !found {
+		stats = &RequestStats{Codes: map[string]int{}}
+		r.requests[requestType] = stats
+	}
+	stats.latencies = append(stats.latencies, latency)
+	stats.sorted = false
+	if err != nil {
+		res = Response{}
+		stats.Codes[errorCode(err)]++
+	} else {
+		stats.Codes[res.Code]++
+	}
+	if !res.OK {
+		stats.Failed++
+	}
+	return res
+}
+
+// errorCode groups errors of requests without response
+func errorCode(err error) string {
+	var httpErr valkhttp.HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		return fmt.Sprintf("HTTP %d", httpErr.Code)
+	case errors.Is(err, valkhttp.TimeoutError):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
+// Count number of requests
+func (s *RequestStats) Count() int {
+	return len(s.latencies)
+}
+
+// Percentile returns the latency at percentile p (0-100), using the nearest rank
+func (s *RequestStats) Percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	if !s.sorted {
+		sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+		s.sorted = true
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(s.latencies))))
+	if rank < 1 {
+		rank = 1
+	}
+	return s.latencies[rank-1]
+}
+
+// Mean returns the mean latency
+func (s *RequestStats) Mean() time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, latency := range s.latencies {
+		total += latency
+	}
+	return total / time.Duration(len(s.latencies))
+}
+
+// Failed returns the total number of failed requests
+func (r *Report) Failed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failed := 0
+	for _, stats := range r.requests {
+		failed += stats.Failed
+	}
+	return failed
+}
+
+// Write writes the report as a table with a row per request type, followed by the response
+// codes of each request type
+func (r *Report) Write(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := 0
+	for _, stats := range r.requests {
+		total += stats.Count()
+	}
+	_, _ = fmt.Fprintf(w, "%d requests in %s", total, r.Duration.Round(time.Millisecond))
+	if seconds := r.Duration.Seconds(); seconds > 0 {
+		_, _ = fmt.Fprintf(w, " (%.1f/s)", float64(total)/seconds)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintf(w, "%-14s %8s %8s %10s %10s %10s %10s %10s\n",
+		"request", "count", "failed", "mean", "p50", "p95", "p99", "max")
+	types := r.requestTypes()
+	for _, requestType := range types {
+		stats := r.requests[requestType]
+		_, _ = fmt.Fprintf(w, "%-14s %8d %8d %10s %10s %10s %10s %10s\n",
+			requestType, stats.Count(), stats.Failed, round(stats.Mean()), round(stats.Percentile(50)),
+			round(stats.Percentile(95)), round(stats.Percentile(99)), round(stats.Percentile(100)))
+	}
+
+	_, _ = fmt.Fprintln(w, "response codes:")
+	for _, requestType := range types {
+		codes := r.requests[requestType].Codes
+		names := make([]string, 0, len(codes))
+		for code := range codes {
+			names = append(names, code)
+		}
+		sort.Strings(names)
+		for _, code := range names {
+			_, _ = fmt.Fprintf(w, "  %-14s %-30s %8d\n", requestType, code, codes[code])
+		}
+	}
+}
+
+// requestTypes returns the request types of the report, in the order they are played
+func (r *Report) requestTypes() []string {
+	order := map[string]int{Authenticate: 0, PlaceBet: 1, SettleWin: 2, RollbackBet: 3}
+	types := make([]string, 0, len(r.requests))
+	for requestType := range r.requests {
+		types = append(types, requestType)
+	}
+	sort.Slice(types, func(i, j int) bool { return order[types[i]] < order[types[j]] })
+	return types
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,51 @@
+package simulation
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+func TestRequestStats_Percentile(t *testing.T) {
+	stats := &RequestStats{}
+	for _, ms := range []int{5, 1, 4, 2, 3, 10, 9, 8, 7, 6} {
+		stats.latencies = append(stats.latencies, time.Duration(ms)*time.Millisecond)
+	}
+
+	tests := []struct {
+		percentile float64
+		want       time.Duration
+	}{
+		{0, time.Millisecond},
+		{50, 5 * time.Millisecond},
+		{95, 10 * time.Millisecond},
+		{100, 10 * time.Millisecond},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, stats.Percentile(test.percentile), "p%v", test.percentile)
+	}
+	assert.Equal(t, 5500*time.Microsecond, stats.Mean())
+}
+
+func TestReport_Write(t *testing.T) {
+	report := NewReport()
+	report.measure(PlaceBet, func() (Response, error) { return Response{Code: "OK", OK: true}, nil })
+	report.measure(PlaceBet, func() (Response, error) { return Response{Code: "INSUFFICIENT_FUNDS"}, nil })
+	report.measure(Authenticate, func() (Response, error) { return Response{}, valkhttp.NewHTTPError(401, "") })
+	report.measure(Authenticate, func() (Response, error) { return Response{}, valkhttp.TimeoutError })
+	report.Duration = time.Second
+
+	var out bytes.Buffer
+	report.Write(&out)
+
+	assert.Equal(t, 3, report.Failed())
+	assert.Contains(t, out.String(), "4 requests in 1s (4.0/s)")
+	assert.Regexp(t, `(?s)authenticate +2 +2 .*bet +2 +1 `, out.String(), "request types in order played")
+	assert.Contains(t, out.String(), "HTTP 401")
+	assert.Contains(t, out.String(), "timeout")
+	assert.Contains(t, out.String(), "INSUFFICIENT_FUNDS")
+}
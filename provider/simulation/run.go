@@ -0,0 +1,117 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Request types reported
+const (
+	Authenticate = "authenticate"
+	PlaceBet     = "bet"
+	SettleWin    = "win"
+	RollbackBet  = "rollback"
+)
+
+// Script scripted play session: authentication, bets each settled by a win and optionally a
+// bet which is rolled back
+type Script struct {
+	Bets      int
+	BetAmount decimal.Decimal
+	WinAmount decimal.Decimal
+	Rollback  bool
+}
+
+// Options of a simulation run
+type Options struct {
+	// Sessions of players to play with. Each worker needs its own sessions, since authenticating
+	// replaces the session token.
+	Sessions []Session
+	// Concurrency number of workers playing in parallel
+	Concurrency int
+	// Iterations total number of play sessions
+	Iterations int
+	Script     Script
+}
+
+// Run plays opts.Iterations scripted play sessions, spread over opts.Concurrency workers, each
+// playing with its own share of the sessions. Stops early if ctx is cancelled.
+func Run(ctx context.Context, simulator Simulator, opts Options) (*Report, error) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if len(opts.Sessions) < opts.Concurrency {
+		return nil, fmt.Errorf("%d sessions for %d workers, each worker needs at least one session",
+			len(opts.Sessions), opts.Concurrency)
+	}
+
+	report := NewReport()
+	ids := &idGenerator{prefix: "sim" + strconv.FormatInt(time.Now().UnixNano(), 36)}
+	var remaining atomic.Int64
+	remaining.Store(int64(opts.Iterations))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Concurrency; w++ {
+		var sessions []Session
+		for i := w; i < len(opts.Sessions); i += opts.Concurrency {
+			sessions = append(sessions, opts.Sessions[i])
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ctx.Err() == nil && remaining.Add(-1) >= 0; i++ {
+				session := &sessions[i%len(sessions)]
+				play(ctx, simulator, session, opts.Script, ids, report)
+			}
+		}()
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// play runs the script with session, updating the session token if authentication returns a new one
+func play(ctx context.Context, simulator Simulator, session *Session, script Script, ids *idGenerator, report *Report) {
+	res := report.measure(Authenticate, func() (Response, error) { return simulator.Authenticate(ctx, *session) })
+	if !res.OK {
+		return
+	}
+	if res.Token != "" {
+		session.Token = res.Token
+	}
+
+	for i := 0; i < script.Bets; i++ {
+		bet := Bet{TransactionID: ids.next(), RoundID: ids.next(), Amount: script.BetAmount}
+		if res = report.measure(PlaceBet, func() (Response, error) { return simulator.Bet(ctx, *session, bet) }); !res.OK {
+			continue
+		}
+		win := Win{TransactionID: ids.next(), Bet: bet, Amount: script.WinAmount}
+		report.measure(SettleWin, func() (Response, error) { return simulator.Win(ctx, *session, win) })
+	}
+
+	if script.Rollback {
+		bet := Bet{TransactionID: ids.next(), RoundID: ids.next(), Amount: script.BetAmount}
+		if res = report.measure(PlaceBet, func() (Response, error) { return simulator.Bet(ctx, *session, bet) }); res.OK {
+			rollback := Rollback{TransactionID: ids.next(), Bet: bet}
+			report.measure(RollbackBet, func() (Response, error) { return simulator.Rollback(ctx, *session, rollback) })
+		}
+	}
+}
+
+// idGenerator generates transaction and round ids unique for the run, short enough for providers
+// limiting ids to 32 characters
+type idGenerator struct {
+	prefix  string
+	counter atomic.Int64
+}
+
+func (g *idGenerator) next() string {
+	return fmt.Sprintf("%s-%d", g.prefix, g.counter.Add(1))
+}
@@ -0,0 +1,130 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSimulator responds with "OK", unless the amount of a bet exceeds the balance
+type fakeSimulator struct {
+	mu      sync.Mutex
+	balance decimal.Decimal
+	tokens  []string
+	fail    bool
+}
+
+func (s *fakeSimulator) Authenticate(_ context.Context, session Session) (Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return Response{}, errors.New("connection refused")
+	}
+	s.tokens = append(s.tokens, session.Token)
+	return Response{Code: "OK", OK: true, Token: session.Token + "+"}, nil
+}
+
+func (s *fakeSimulator) Bet(_ context.Context, _ Session, bet Bet) (Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.balance.LessThan(bet.Amount) {
+		return Response{Code: "INSUFFICIENT_FUNDS"}, nil
+	}
+	s.balance = s.balance.Sub(bet.Amount)
+	return Response{Code: "OK", OK: true}, nil
+}
+
+func (s *fakeSimulator) Win(_ context.Context, _ Session, win Win) (Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = s.balance.Add(win.Amount)
+	return Response{Code: "OK", OK: true}, nil
+}
+
+func (s *fakeSimulator) Rollback(_ context.Context, _ Session, rollback Rollback) (Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = s.balance.Add(rollback.Bet.Amount)
+	return Response{Code: "OK", OK: true}, nil
+}
+
+func TestRun(t *testing.T) {
+	script := Script{Bets: 2, BetAmount: decimal.NewFromInt(10), WinAmount: decimal.NewFromInt(5), Rollback: true}
+	sessions := []Session{{Token: "a"}, {Token: "b"}}
+
+	tests := []struct {
+		name      string
+		simulator *fakeSimulator
+		opts      Options
+		wantCodes map[string]map[string]int
+		wantErr   string
+	}{
+		{
+			name:      "concurrent play sessions",
+			simulator: &fakeSimulator{balance: decimal.NewFromInt(100)},
+			opts:      Options{Sessions: sessions, Concurrency: 2, Iterations: 3, Script: script},
+			wantCodes: map[string]map[string]int{
+				Authenticate: {"OK": 3},
+				PlaceBet:     {"OK": 9},
+				SettleWin:    {"OK": 6},
+				RollbackBet:  {"OK": 3},
+			},
+		},
+		{
+			name:      "failed bets are neither settled nor rolled back",
+			simulator: &fakeSimulator{balance: decimal.NewFromInt(10)},
+			opts:      Options{Sessions: sessions, Iterations: 1, Script: script},
+			wantCodes: map[string]map[string]int{
+				Authenticate: {"OK": 1},
+				PlaceBet:     {"OK": 1, "INSUFFICIENT_FUNDS": 2},
+				SettleWin:    {"OK": 1},
+			},
+		},
+		{
+			name:      "failed authentication ends play session",
+			simulator: &fakeSimulator{fail: true},
+			opts:      Options{Sessions: sessions, Iterations: 2, Script: script},
+			wantCodes: map[string]map[string]int{Authenticate: {"error": 2}},
+		},
+		{
+			name:      "session per worker",
+			simulator: &fakeSimulator{},
+			opts:      Options{Sessions: sessions, Concurrency: 3, Iterations: 3, Script: script},
+			wantErr:   "2 sessions for 3 workers, each worker needs at least one session",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			report, err := Run(context.Background(), test.simulator, test.opts)
+			if test.wantErr != "" {
+				assert.EqualError(tt, err, test.wantErr)
+				return
+			}
+			require.NoError(tt, err)
+			for _, requestType := range []string{Authenticate, PlaceBet, SettleWin, RollbackBet} {
+				stats := report.Requests(requestType)
+				if test.wantCodes[requestType] == nil {
+					assert.Nil(tt, stats, requestType)
+					continue
+				}
+				require.NotNil(tt, stats, requestType)
+				assert.Equal(tt, test.wantCodes[requestType], stats.Codes, requestType)
+			}
+		})
+	}
+}
+
+func TestRun_refreshedTokens(t *testing.T) {
+	simulator := &fakeSimulator{balance: decimal.NewFromInt(100)}
+	_, err := Run(context.Background(), simulator, Options{
+		Sessions:   []Session{{Token: "a"}, {Token: "b"}},
+		Iterations: 4,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "a+", "b+"}, simulator.tokens, "tokens returned by authentication are used")
+}
@@ -0,0 +1,84 @@
+package simulation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/internal"
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+// Args composes all arguments required to build a simulator
+type Args struct {
+	// URL base url of the provider wallet endpoints, including provider base paths
+	URL string
+	// Config of the provider, with the credentials the provider uses when calling Valkyrie
+	Config     configs.ProviderConf
+	HTTPClient valkhttp.HTTPClient
+}
+
+// Session player session handed to the provider when launching a game
+type Session struct {
+	Token    string
+	PlayerID string
+	Currency string
+	GameID   string
+}
+
+// Bet bet placed in a game round
+type Bet struct {
+	TransactionID string
+	RoundID       string
+	Amount        decimal.Decimal
+}
+
+// Win win settling the game round of a bet
+type Win struct {
+	TransactionID string
+	Bet           Bet
+	Amount        decimal.Decimal
+}
+
+// Rollback rollback of a bet
+type Rollback struct {
+	TransactionID string
+	Bet           Bet
+}
+
+// Response native response of the provider wallet endpoints
+type Response struct {
+	// Code native status or error code
+	Code string
+	// OK if the code means success
+	OK bool
+	// Token session token to use for following requests, if returned
+	Token string
+}
+
+// Simulator sends wallet requests the way the provider does. Errors are only returned when
+// there is no native response, responses with error codes are not errors.
+type Simulator interface {
+	Authenticate(ctx context.Context, session Session) (Response, error)
+	Bet(ctx context.Context, session Session, bet Bet) (Response, error)
+	Win(ctx context.Context, session Session, win Win) (Response, error)
+	Rollback(ctx context.Context, session Session, rollback Rollback) (Response, error)
+}
+
+type simulatorFactory = internal.AbstractFactory[Args, Simulator]
+
+var (
+	once    sync.Once
+	factory *simulatorFactory
+)
+
+// Factory returns a single instance of the simulator factory, with simulators registered by provider name
+func Factory() *simulatorFactory {
+	once.Do(func() {
+		factory = internal.NewAbstractFactory[Args, Simulator]()
+	})
+
+	return factory
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/valkyrie-fnd/valkyrie/ops"
+)
+
+// keyValueFlag repeatable flag of "key=value" pairs
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f keyValueFlag) Set(value string) error {
+	k, v, found := strings.Cut(value, "=")
+	if !found {
+		return fmt.Errorf("expected key=value, got '%s'", value)
+	}
+	f[k] = v
+	return nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// replayCommand re-sends provider requests recorded by the traffic recorder to a running Valkyrie
+// and reports responses differing from the recorded ones
+func replayCommand(ctx context.Context, args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(out)
+	file := flags.String("file", "", "Traffic recording to replay (required)")
+	target := flags.String("target", "http://localhost:8083", "Base url of the Valkyrie provider server")
+	traces := flags.String("trace", "", "Comma separated trace ids to replay, all if not set")
+	ignore := flags.String("ignore", "", "Comma separated names of JSON fields to not compare")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout of each request")
+	headers, query := keyValueFlag{}, keyValueFlag{}
+	flags.Var(headers, "header", "Header replacing the recorded one, as name=value (repeatable)")
+	flags.Var(query, "query", "Query parameter replacing the recorded one, as name=value (repeatable)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		_, _ = fmt.Fprintln(out, "replay: -file is required")
+		flags.Usage()
+		return 2
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "replay: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+	records, err := ops.ReadTrafficRecords(f)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "replay: %v\n", err)
+		return 1
+	}
+
+	results := ops.Replay(ctx, records, ops.ReplayOptions{
+		TargetURL: *target,
+		TraceIDs:  splitList(*traces),
+		Headers:   headers,
+		Query:     query,
+		Ignore:    splitList(*ignore),
+		Timeout:   *timeout,
+	})
+
+	failed := 0
+	for _, result := range results {
+		request := fmt.Sprintf("%s %s (trace %s)", result.Record.Request.Method, result.Record.Request.URL, result.Record.TraceID)
+		switch {
+		case result.Err != nil:
+			failed++
+			_, _ = fmt.Fprintf(out, "FAIL  %s: %v\n", request, result.Err)
+		case len(result.Diffs) > 0:
+			failed++
+			_, _ = fmt.Fprintf(out, "DIFF  %s\n", request)
+			for _, diff := range result.Diffs {
+				_, _ = fmt.Fprintf(out, "      %s\n", diff)
+			}
+		default:
+			_, _ = fmt.Fprintf(out, "OK    %s\n", request)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "%d requests replayed, %d differing\n", len(results), failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
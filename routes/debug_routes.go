@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/ops"
+)
+
+// DebugRoutes mounts operator routes changing the log level, enabling debug logging of providers and players
+// for a limited time and turning pprof on or off. The pprof profiles are served below /debug/pprof when turned on,
+// initially if the PPROF environment variable is present.
+func DebugRoutes(operator fiber.Router, config *configs.ValkyrieConfig) {
+	route := operator.Group("/debug")
+
+	// provider and operator routes of each provider
+	providerRoutes := map[string][]string{}
+	for _, p := range config.Providers {
+		name := strings.ToLower(p.Name)
+		providerRoutes[name] = append(providerRoutes[name],
+			config.ProviderBasePath+p.BasePath, config.OperatorBasePath+p.BasePath)
+	}
+
+	route.Get("/log-level", ops.LogLevelEndpoint())
+	route.Put("/log-level", ops.SetLogLevelEndpoint())
+	route.Get("/windows", ops.DebugWindowsEndpoint())
+	route.Post("/providers/:provider", ops.EnableProviderDebugEndpoint(providerRoutes))
+	route.Delete("/providers/:provider", ops.DisableDebugEndpoint(ops.DebugKindProvider, "provider"))
+	route.Post("/players/:playerId", ops.EnablePlayerDebugEndpoint())
+	route.Delete("/players/:playerId", ops.DisableDebugEndpoint(ops.DebugKindPlayer, "playerId"))
+	route.Get("/pprof", ops.PprofEndpoint())
+	route.Put("/pprof", ops.SetPprofEndpoint())
+	route.Use(pprof.New(pprof.Config{
+		Prefix: config.OperatorBasePath,
+		Next:   func(_ *fiber.Ctx) bool { return !ops.PprofEnabled() },
+	}))
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/ops"
+)
+
+func TestDebugRoutes_Pprof(t *testing.T) {
+	app := fiber.New()
+	config := &configs.ValkyrieConfig{OperatorBasePath: "/operator", OperatorAPIKey: "key"}
+	operator, err := OperatorRoutes(app, config, testProfiles(t), nil)
+	require.NoError(t, err)
+	DebugRoutes(operator, config)
+	t.Cleanup(func() { ops.SetPprofEnabled(false) })
+
+	tests := []struct {
+		name    string
+		enabled bool
+		auth    string
+		want    int
+	}{
+		{"unauthorized", true, "", fiber.StatusUnauthorized},
+		{"turned off", false, "Bearer key", fiber.StatusNotFound},
+		{"turned on", true, "Bearer key", fiber.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ops.SetPprofEnabled(test.enabled)
+			req := httptest.NewRequest(fiber.MethodGet, "/operator/debug/pprof/heap?debug=1", nil)
+			if test.auth != "" {
+				req.Header.Set("Authorization", test.auth)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, resp.StatusCode)
+		})
+	}
+
+	// the state of pprof is still reported by the debug routes
+	req := httptest.NewRequest(fiber.MethodGet, "/operator/debug/pprof", nil)
+	req.Header.Set("Authorization", "Bearer key")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+}
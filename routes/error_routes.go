@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/provider"
+)
+
+// ErrorRoutes mounts the operator route publishing the error catalogue and the error mappings of the configured
+// providers
+func ErrorRoutes(operator fiber.Router, config *configs.ValkyrieConfig) {
+	providers := make([]string, 0, len(config.Providers))
+	for _, p := range config.Providers {
+		providers = append(providers, lCaseNoWhitespace(p.Name))
+	}
+
+	operator.Get("/errors", provider.ErrorCatalogueEndpoint(providers))
+}
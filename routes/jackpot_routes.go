@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/valkyrie-fnd/valkyrie/pam/jackpot"
+)
+
+// JackpotRoutes mounts operator routes reporting jackpots recorded in the ledger
+func JackpotRoutes(operator fiber.Router, ledger *jackpot.Ledger) {
+	route := operator.Group("/jackpots")
+
+	route.Get("/contributions", jackpot.ContributionsEndpoint(ledger))
+}
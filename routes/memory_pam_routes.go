@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/valkyrie-fnd/valkyrie/pam/memory"
+)
+
+// MemoryPAMRoutes mounts operator routes to adjust player balances and sessions of the in-memory PAM
+func MemoryPAMRoutes(operator fiber.Router, p *memory.PAM) {
+	route := operator.Group("/memory-pam")
+
+	route.Get("/players/:playerId", memory.PlayerEndpoint(p))
+	route.Post("/players/:playerId/balance", memory.AdjustBalanceEndpoint(p))
+	route.Post("/players/:playerId/sessions", memory.AddSessionEndpoint(p))
+	route.Post("/sessions/:token/expire", memory.ExpireSessionEndpoint(p))
+}
@@ -1,13 +1,17 @@
 package routes
 
 import (
+	"net/http/httptest"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
 	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/pam/jackpot"
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
 
 func Test_ProviderRoutes(t *testing.T) {
@@ -41,7 +45,7 @@ func Test_ProviderRoutes(t *testing.T) {
 				},
 				URL: "url",
 			},
-			wantHandlers: 12,
+			wantHandlers: 16,
 			pamClient:    &mockPamClient{},
 		},
 	}
@@ -49,7 +53,7 @@ func Test_ProviderRoutes(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(tt *testing.T) {
 			app := fiber.New()
-			err := ProviderRoutes(app, &configs.ValkyrieConfig{Providers: []configs.ProviderConf{test.conf}}, test.pamClient, nil)
+			err := ProviderRoutes(app, &configs.ValkyrieConfig{Providers: []configs.ProviderConf{test.conf}}, test.pamClient, testProfiles(tt), nil)
 			assert.NoError(tt, err)
 			assert.Equal(tt, test.wantHandlers, int(app.HandlersCount()))
 		})
@@ -73,7 +77,7 @@ func Test_OperatorRoutes(t *testing.T) {
 				},
 				URL: "url",
 			},
-			wantHandlers: 5,
+			wantHandlers: 7,
 		},
 		{
 			name: "Red Tiger",
@@ -92,7 +96,7 @@ func Test_OperatorRoutes(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(tt *testing.T) {
 			app := fiber.New()
-			err := OperatorRoutes(app, &configs.ValkyrieConfig{Providers: []configs.ProviderConf{test.conf}}, nil)
+			_, err := OperatorRoutes(app, &configs.ValkyrieConfig{Providers: []configs.ProviderConf{test.conf}}, testProfiles(tt), nil)
 			assert.NoError(tt, err)
 			assert.Equal(tt, test.wantHandlers, int(app.HandlersCount()))
 		})
@@ -106,3 +110,37 @@ type mockPamClient struct {
 func (p *mockPamClient) GetTransactionSupplier() pam.TransactionSupplier {
 	return pam.OPERATOR
 }
+
+func Test_ProviderRoutesUnknownHTTPClientProfile(t *testing.T) {
+	conf := configs.ProviderConf{Name: "Evolution", HTTPClientProfile: "missing"}
+	profiles := testProfiles(t)
+
+	err := ProviderRoutes(fiber.New(), &configs.ValkyrieConfig{Providers: []configs.ProviderConf{conf}}, &mockPamClient{}, profiles, nil)
+	assert.ErrorContains(t, err, "unknown http client profile 'missing'")
+
+	_, err = OperatorRoutes(fiber.New(), &configs.ValkyrieConfig{Providers: []configs.ProviderConf{conf}}, profiles, nil)
+	assert.ErrorContains(t, err, "unknown http client profile 'missing'")
+}
+
+func testProfiles(t *testing.T) *valkhttp.Profiles {
+	profiles, err := valkhttp.CreateProfiles(configs.HTTPClientConfig{}, nil)
+	assert.NoError(t, err)
+	return profiles
+}
+
+func Test_OperatorRoutesAuthorization(t *testing.T) {
+	app := fiber.New()
+	operator, err := OperatorRoutes(app, &configs.ValkyrieConfig{OperatorBasePath: "/operator", OperatorAPIKey: "key"}, testProfiles(t), nil)
+	require.NoError(t, err)
+	JackpotRoutes(operator, jackpot.NewLedger(0))
+
+	req := httptest.NewRequest(fiber.MethodGet, "/operator/jackpots/contributions", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	req.Header.Set("Authorization", "Bearer key")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
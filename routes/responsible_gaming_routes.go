@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/valkyrie-fnd/valkyrie/pam/rg"
+)
+
+// ResponsibleGamingRoutes mounts operator routes reporting responsible gaming events and the state of players
+func ResponsibleGamingRoutes(operator fiber.Router, guard *rg.Guard) {
+	route := operator.Group("/responsible-gaming")
+
+	route.Get("/events", rg.EventsEndpoint(guard, ""))
+	route.Get("/reality-checks", rg.EventsEndpoint(guard, rg.RealityCheckEvent))
+	route.Get("/players/:playerId", rg.PlayerEndpoint(guard))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/pam"
 	"github.com/valkyrie-fnd/valkyrie/provider"
 
@@ -21,8 +22,8 @@ import (
 	_ "github.com/valkyrie-fnd/valkyrie/provider/redtiger"
 )
 
-// ProviderRoutes Init the provider routes
-func ProviderRoutes(a *fiber.App, config *configs.ValkyrieConfig, pam pam.PamClient, httpClient valkhttp.HTTPClient) error {
+// ProviderRoutes Init the provider routes. The converter is nil unless currency conversion is configured.
+func ProviderRoutes(a *fiber.App, config *configs.ValkyrieConfig, pam pam.PamClient, httpClients *valkhttp.Profiles, converter *fx.Converter) error {
 	// ping endpoint is public and used by load balancers for health checking
 	a.Get("/ping", pingHandler)
 
@@ -32,11 +33,16 @@ func ProviderRoutes(a *fiber.App, config *configs.ValkyrieConfig, pam pam.PamCli
 	// Register all configured providers
 	for _, c := range config.Providers {
 		c.Name = lCaseNoWhitespace(c.Name)
+		httpClient, err := providerHTTPClient(httpClients, c)
+		if err != nil {
+			return err
+		}
 		providerRouter, err := provider.ProviderFactory().
 			Build(c.Name, provider.ProviderArgs{
 				Config:     c,
 				PamClient:  pam,
 				HTTPClient: httpClient,
+				Converter:  converter,
 			})
 		if err != nil {
 			return fmt.Errorf("implementation of provider '%s' does not exist (%w)", c.Name, err)
@@ -45,18 +51,22 @@ func ProviderRoutes(a *fiber.App, config *configs.ValkyrieConfig, pam pam.PamCli
 		if err := registry.Register(providerRouter); err != nil {
 			return err
 		}
+		if err := provider.CheckErrorMapping(c.Name); err != nil {
+			log.Warn().Err(err).Msg("Unmapped errors are responded to as generic errors")
+		}
 	}
 
 	return nil
 }
 
-// OperatorRoutes Init the operator side routes
-func OperatorRoutes(a *fiber.App, config *configs.ValkyrieConfig, httpClient valkhttp.HTTPClient) error {
+// OperatorRoutes Init the operator side routes. The converter is nil unless currency conversion is configured.
+// Returns the operator group requiring the operator authorization, for mounting further operator routes.
+func OperatorRoutes(a *fiber.App, config *configs.ValkyrieConfig, httpClients *valkhttp.Profiles, converter *fx.Converter) (fiber.Router, error) {
 	// ping endpoint is public and used by load balancers for health checking
 	a.Get("/ping", pingHandler)
 
 	// Add authorization for operator paths
-	a.Use(config.OperatorBasePath, provider.OperatorAuthorization(config.OperatorAPIKey))
+	operator := a.Group(config.OperatorBasePath, provider.OperatorAuthorization(config.OperatorAPIKey))
 
 	// Create subgroup and registry
 	registry := provider.NewRegistry(a, config.OperatorBasePath)
@@ -64,23 +74,37 @@ func OperatorRoutes(a *fiber.App, config *configs.ValkyrieConfig, httpClient val
 	// Register all configured providers
 	for _, c := range config.Providers {
 		c.Name = lCaseNoWhitespace(c.Name)
+		httpClient, err := providerHTTPClient(httpClients, c)
+		if err != nil {
+			return nil, err
+		}
 		operatorRouter, err := provider.OperatorFactory().
 			Build(c.Name, provider.OperatorArgs{
 				Config:     c,
 				HTTPClient: httpClient,
+				Converter:  converter,
 			})
 		if err != nil {
-			return fmt.Errorf("implementation of operator routes for provider '%s' does not exist (%w)", c.Name, err)
+			return nil, fmt.Errorf("implementation of operator routes for provider '%s' does not exist (%w)", c.Name, err)
 		}
 		log.Info().Msgf("Registering %s operator routes", operatorRouter.Name)
 		if err := registry.Register(operatorRouter); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return operator, nil
 }
 
 func lCaseNoWhitespace(str string) string {
 	return strings.ReplaceAll(strings.ToLower(str), " ", "")
 }
+
+// providerHTTPClient returns the client of the provider's http client profile, using the provider's retry policy
+func providerHTTPClient(httpClients *valkhttp.Profiles, c configs.ProviderConf) (valkhttp.HTTPClient, error) {
+	httpClient, err := httpClients.Get(c.HTTPClientProfile)
+	if err != nil {
+		return nil, fmt.Errorf("provider '%s': %w", c.Name, err)
+	}
+	return valkhttp.WithRetry(httpClient, c.Retry), nil
+}
@@ -19,7 +19,7 @@ func Test_AllProvidersGetsRegistered(t *testing.T) {
 	require.NoError(t, err)
 
 	for _, f := range fs {
-		if f.IsDir() && f.Name() != "internal" && f.Name() != "docs" {
+		if f.IsDir() && f.Name() != "internal" && f.Name() != "docs" && f.Name() != "providertest" && f.Name() != "simulation" {
 
 			r, err := provider.ProviderFactory().Build(f.Name(), provider.ProviderArgs{
 				PamClient: &dummyPamClient{},
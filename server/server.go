@@ -6,22 +6,22 @@ import (
 	"sync"
 	"time"
 
-
-	"github.com/valkyrie-fnd/valkyrie/internal/routine"
-	"github.com/valkyrie-fnd/valkyrie/pam/genericpam"
-	"github.com/valkyrie-fnd/valkyrie/pam/vplugin"
-	"github.com/valkyrie-fnd/valkyrie/valkhttp"
-
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
 
 	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/internal/routine"
+	"github.com/valkyrie-fnd/valkyrie/money/fx"
 	"github.com/valkyrie-fnd/valkyrie/ops"
 	"github.com/valkyrie-fnd/valkyrie/pam"
+	"github.com/valkyrie-fnd/valkyrie/pam/audit"
+	"github.com/valkyrie-fnd/valkyrie/pam/genericpam" // also inits generic pam
+	"github.com/valkyrie-fnd/valkyrie/pam/jackpot"
+	"github.com/valkyrie-fnd/valkyrie/pam/memory" // also inits in-memory pam
+	"github.com/valkyrie-fnd/valkyrie/pam/rg"
+	"github.com/valkyrie-fnd/valkyrie/pam/vplugin" // also inits pam plugins
 	"github.com/valkyrie-fnd/valkyrie/routes"
-
-	_ "github.com/valkyrie-fnd/valkyrie/pam/genericpam" // init generic pam
-	_ "github.com/valkyrie-fnd/valkyrie/pam/vplugin"    // init pam plugins
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
 )
 
 // Valkyrie struct containing information and configuration on configured providers and operator
@@ -54,13 +54,29 @@ func NewValkyrie(ctx context.Context, cfg *configs.ValkyrieConfig) (*Valkyrie, e
 		return nil, err
 	}
 
-	// Http client
-	httpClient := valkhttp.Create(cfg.HTTPClient)
+	// Recording of provider traffic and the PAM calls it causes
+	recorder := ops.NewTrafficRecorder(cfg.Recording)
+	if recorder != nil {
+		v.provider.Use(ops.TrafficRecordingMiddleware(recorder))
+		v.provider.Hooks().OnShutdown(recorder.Close)
+	}
+
+	// Http clients, one per configured profile
+	httpClients, err := valkhttp.CreateProfiles(cfg.HTTPClient, cfg.HTTPClientProfiles)
+	if err != nil {
+		log.Err(err).Msg("Error creating http clients")
+		return nil, err
+	}
+	pamHTTPClient, err := httpClients.Get(pam.GetHTTPClientProfile(cfg.Pam))
+	if err != nil {
+		log.Err(err).Msg("Error getting pam http client")
+		return nil, err
+	}
 
 	// PAM client.
 	pamClient, err := pam.GetPamClient(pam.ClientArgs{
 		Context:     cc,
-		Client:      httpClient,
+		Client:      pamHTTPClient,
 		Config:      cfg.Pam,
 		LogConfig:   cfg.Logging,
 		TraceConfig: cfg.Telemetry.Tracing,
@@ -70,15 +86,76 @@ func NewValkyrie(ctx context.Context, cfg *configs.ValkyrieConfig) (*Valkyrie, e
 		return nil, err
 	}
 
+	// Backdoor of the in-memory PAM, before it gets wrapped
+	memoryPAM, _ := pamClient.(*memory.PAM)
+
+	// Keep track of jackpots booked through the PAM, if enabled
+	jackpotLedger, err := jackpot.NewLedgerFromConfig(cfg.Jackpots)
+	if err != nil {
+		log.Err(err).Msg("Error configuring jackpot ledger")
+		return nil, err
+	}
+	if jackpotLedger != nil {
+		pamClient = jackpot.NewRecordingClient(pamClient, jackpotLedger)
+		v.provider.Hooks().OnShutdown(jackpotLedger.Close)
+	}
+	// Business metrics on transaction amounts, with tips reported separately
+	pamClient = ops.InstrumentPAMTransactions(pamClient)
+	// Debug logging of players with a debug window
+	pamClient = ops.DebugPAMPlayers(pamClient)
+	if recorder != nil {
+		pamClient = ops.RecordPAMTraffic(pamClient, recorder)
+	}
+	// Responsible gaming checks before stakes, if configured
+	guard, err := rg.NewGuardFromConfig(cfg.ResponsibleGaming)
+	if err != nil {
+		log.Err(err).Msg("Error configuring responsible gaming")
+		return nil, err
+	}
+	if guard != nil {
+		pamClient = rg.NewGuardingClient(pamClient, guard)
+	}
+	// Hash chained audit log of all transactions, if configured
+	auditLog, err := audit.NewLogFromConfig(cfg.Audit)
+	if err != nil {
+		log.Err(err).Msg("Error configuring audit log")
+		return nil, err
+	}
+	if auditLog != nil {
+		pamClient = audit.NewAuditingClient(pamClient, auditLog)
+		v.provider.Hooks().OnShutdown(auditLog.Close)
+	}
+	// Correlation ID of requests sent to the PAM, seen by all the clients above
+	pamClient = ops.CorrelatePAMRequests(pamClient)
+
+	// Currency conversion between play and wallet currencies, if configured
+	converter, err := fx.NewConverterFromConfig(cfg.FX)
+	if err != nil {
+		log.Err(err).Msg("Error configuring currency conversion")
+		return nil, err
+	}
+
 	// Provider routes.
-	if err = routes.ProviderRoutes(v.provider, cfg, pamClient, httpClient); err != nil {
+	if err = routes.ProviderRoutes(v.provider, cfg, pamClient, httpClients, converter); err != nil {
 		log.Err(err).Msg("Unable to setup the intended provider routes")
 		return nil, err
 	}
-	if err = routes.OperatorRoutes(v.operator, cfg, httpClient); err != nil {
+	operator, err := routes.OperatorRoutes(v.operator, cfg, httpClients, converter)
+	if err != nil {
 		log.Err(err).Msg("Unable to setup the intended operator routes")
 		return nil, err
 	}
+	routes.DebugRoutes(operator, cfg)
+	routes.ErrorRoutes(operator, cfg)
+	if jackpotLedger != nil {
+		routes.JackpotRoutes(operator, jackpotLedger)
+	}
+	if guard != nil {
+		routes.ResponsibleGamingRoutes(operator, guard)
+	}
+	if memoryPAM != nil {
+		routes.MemoryPAMRoutes(operator, memoryPAM)
+	}
 
 	// Swagger
 	err = configureSwagger(v)
@@ -92,7 +169,7 @@ func configureOps(cfg *configs.ValkyrieConfig, v *Valkyrie) error {
 	// Profile
 	profiles := ops.NewProfiles().Load()
 	// Configure logging
-	ops.ConfigureLogging(cfg.Logging, profiles)
+	ops.ConfigureLogging(cfg.Logging, cfg.Telemetry, profiles)
 
 	// Metrics config
 	if err := ops.ConfigureMetrics(cfg); err != nil {
@@ -106,8 +183,9 @@ func configureOps(cfg *configs.ValkyrieConfig, v *Valkyrie) error {
 	// Middlewares
 	fiberMiddleware(v.provider, v.operator)
 
-	// Setup tracing and logging
+	// Setup tracing, correlation IDs and logging
 	ops.TracingMiddleware(tracing, v.provider, v.operator)
+	ops.CorrelationMiddleware(v.provider, v.operator)
 	ops.LoggingMiddleware(v.provider, v.operator)
 
 	// Instrument other components to capture telemetry data
@@ -169,6 +247,8 @@ func (v *Valkyrie) Run(ready func()) {
 		log.Info().Msgf("Operator server '%v' shutting down", v.config.HTTPServer.OperatorAddress)
 		return nil
 	})
+	// Flush the network log outputs once the operator server, shut down last, has logged its shutdown
+	v.operator.Hooks().OnShutdown(ops.CloseLogOutputs)
 
 	v.provider.Hooks().OnShutdown(func() error {
 		log.Info().Msgf("Provider server '%v' shutting down", v.config.HTTPServer.ProviderAddress)
@@ -151,7 +151,8 @@ func TestTimeout(t *testing.T) {
 			})
 
 			valkyrie.Start()
-			client := valkhttp.Create(valkyrieConfig.HTTPClient)
+			client, err := valkhttp.Create(valkyrieConfig.HTTPClient)
+			assert.NoError(t, err)
 
 			req := &valkhttp.HTTPRequest{
 				Headers: map[string]string{"Accept": "application/json"},
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+	"github.com/valkyrie-fnd/valkyrie/provider/simulation"
+	"github.com/valkyrie-fnd/valkyrie/valkhttp"
+)
+
+// simulateCommand plays scripted game sessions against the wallet endpoints of a provider in a
+// running Valkyrie, the way the provider does, and reports latencies and response codes
+func simulateCommand(ctx context.Context, args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	providerName := flags.String("provider", "", "Name of the provider to simulate (required)")
+	configFile := flags.String("config", "", "Valkyrie configuration to read base paths and provider auth from")
+	target := flags.String("target", "http://localhost:8083", "Base url of the Valkyrie provider server")
+	basePath := flags.String("base-path", "", "Base path of the provider wallet endpoints, from -config or /providers/<provider> if not set")
+	currency := flags.String("currency", "EUR", "Currency of the players")
+	game := flags.String("game", "simulation", "Game id to play")
+	concurrency := flags.Int("concurrency", 1, "Number of play sessions run in parallel, each needs its own -session")
+	iterations := flags.Int("iterations", 1, "Total number of play sessions")
+	bets := flags.Int("bets", 1, "Number of bets in each play session, each settled by a win")
+	betAmount := flags.String("bet", "1", "Amount of each bet")
+	winAmount := flags.String("win", "0.5", "Amount of each win")
+	rollback := flags.Bool("rollback", true, "Roll back an additional bet in each play session")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout of each request")
+	auth, sessions := keyValueFlag{}, keyValueFlag{}
+	flags.Var(auth, "auth", "Provider auth config as name=value, or name=@file to read the value from a file (repeatable)")
+	flags.Var(sessions, "session", "Player session as token=playerId (repeatable, required)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *providerName == "" || len(sessions) == 0 {
+		_, _ = fmt.Fprintln(out, "simulate: -provider and -session are required")
+		flags.Usage()
+		return 2
+	}
+	script := simulation.Script{Bets: *bets, Rollback: *rollback}
+	var err error
+	if script.BetAmount, err = decimal.NewFromString(*betAmount); err != nil {
+		_, _ = fmt.Fprintf(out, "simulate: invalid -bet: %v\n", err)
+		return 2
+	}
+	if script.WinAmount, err = decimal.NewFromString(*winAmount); err != nil {
+		_, _ = fmt.Fprintf(out, "simulate: invalid -win: %v\n", err)
+		return 2
+	}
+
+	providerConf, path, err := simulatedProvider(*configFile, *providerName)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "simulate: %v\n", err)
+		return 1
+	}
+	if *basePath != "" {
+		path = *basePath
+	}
+	for name, value := range auth {
+		if providerConf.Auth[name], err = readFlagValue(value); err != nil {
+			_, _ = fmt.Fprintf(out, "simulate: -auth %s: %v\n", name, err)
+			return 1
+		}
+	}
+
+	client, err := valkhttp.Create(configs.HTTPClientConfig{
+		ReadTimeout:     *timeout,
+		WriteTimeout:    *timeout,
+		RequestTimeout:  *timeout,
+		IdleTimeout:     30 * time.Second,
+		MaxConnsPerHost: *concurrency,
+		Retry:           configs.RetryConfig{MaxAttempts: 1},
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "simulate: %v\n", err)
+		return 1
+	}
+	simulator, err := simulation.Factory().Build(providerConf.Name, simulation.Args{
+		URL:        strings.TrimSuffix(*target, "/") + path,
+		Config:     providerConf,
+		HTTPClient: client,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "simulate: provider simulator: %v\n", err)
+		return 1
+	}
+
+	opts := simulation.Options{Concurrency: *concurrency, Iterations: *iterations, Script: script}
+	for token, playerID := range sessions {
+		opts.Sessions = append(opts.Sessions, simulation.Session{Token: token, PlayerID: playerID, Currency: *currency, GameID: *game})
+	}
+	report, err := simulation.Run(ctx, simulator, opts)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "simulate: %v\n", err)
+		return 2
+	}
+	report.Write(out)
+	if report.Failed() > 0 {
+		return 1
+	}
+	return 0
+}
+
+// simulatedProvider returns the configuration and base path of the provider, from the Valkyrie
+// configuration file if given
+func simulatedProvider(configFile, name string) (configs.ProviderConf, string, error) {
+	name = strings.ReplaceAll(strings.ToLower(name), " ", "")
+	if configFile == "" {
+		return configs.ProviderConf{Name: name, Auth: map[string]any{}}, "/providers/" + name, nil
+	}
+	cfg, err := configs.Read(&configFile)
+	if err != nil {
+		return configs.ProviderConf{}, "", err
+	}
+	for _, p := range cfg.Providers {
+		if strings.ReplaceAll(strings.ToLower(p.Name), " ", "") == name {
+			p.Name = name
+			if p.Auth == nil {
+				p.Auth = map[string]any{}
+			}
+			return p, cfg.ProviderBasePath + p.BasePath, nil
+		}
+	}
+	return configs.ProviderConf{}, "", fmt.Errorf("provider '%s' not found in '%s'", name, configFile)
+}
+
+// readFlagValue returns the value, or the content of the file if the value is "@<file>"
+func readFlagValue(value string) (string, error) {
+	file, found := strings.CutPrefix(value, "@")
+	if !found {
+		return value, nil
+	}
+	content, err := os.ReadFile(file)
+	return string(content), err
+}
@@ -0,0 +1,88 @@
+package valkhttp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+)
+
+// streamThreshold response bodies larger than this, or without content length, are read as streams,
+// allowing them to be size limited and streamed through without buffering the whole body first
+const streamThreshold = 64 * 1024
+
+// ResponseTooLargeError returned when a response body exceeds the maximum response body size
+func ResponseTooLargeError(limit int) error {
+	return NewHTTPError(http.StatusBadGateway, fmt.Sprintf("response body exceeds maximum size of %d bytes", limit))
+}
+
+// bodyReader returns a reader of the response body, limited to limit bytes if limit > 0. The reader
+// fails with ResponseTooLargeError when reading beyond the limit.
+func bodyReader(resp *fasthttp.Response, limit int) (io.Reader, error) {
+	stream := resp.BodyStream()
+	if stream == nil {
+		if limit > 0 && len(resp.Body()) > limit {
+			return nil, ResponseTooLargeError(limit)
+		}
+		return bytes.NewReader(resp.Body()), nil
+	}
+	if limit <= 0 {
+		return stream, nil
+	}
+	if resp.Header.ContentLength() > limit {
+		return nil, ResponseTooLargeError(limit)
+	}
+	return &limitedReader{r: stream, remaining: int64(limit), limit: limit}, nil
+}
+
+// bufferBody reads a streamed response body into the response, failing if it exceeds limit
+func bufferBody(resp *fasthttp.Response, limit int) error {
+	if resp.BodyStream() == nil {
+		return nil
+	}
+	r, err := bodyReader(resp, limit)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	resp.SetBody(body)
+	return nil
+}
+
+// streamBody writes the response body to w, failing if it exceeds limit
+func streamBody(resp *fasthttp.Response, limit int, w io.Writer) error {
+	r, err := bodyReader(resp, limit)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// limitedReader reads up to limit bytes, failing with ResponseTooLargeError if there is more
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	limit     int
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ResponseTooLargeError(l.limit)
+	}
+	// read one byte more than remaining, to detect bodies exceeding the limit
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ResponseTooLargeError(l.limit)
+	}
+	return n, err
+}
@@ -0,0 +1,169 @@
+package valkhttp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+// newBodyServer responds with "size" bytes, chunked if "chunked" is set, and status "status"
+func newBodyServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		body := []byte(strings.Repeat("a", size))
+		if status, _ := strconv.Atoi(r.URL.Query().Get("status")); status != 0 {
+			w.WriteHeader(status)
+		}
+		if r.URL.Query().Has("chunked") {
+			// flushing before writing the whole body makes the response chunked
+			_, _ = w.Write(body[:size/2])
+			w.(http.Flusher).Flush()
+			_, _ = w.Write(body[size/2:])
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_maxResponseBodySize(t *testing.T) {
+	server := newBodyServer(t)
+	tests := []struct {
+		name        string
+		profileMax  int
+		requestMax  int
+		query       map[string]string
+		wantSize    int
+		wantTooLong bool
+	}{
+		{
+			name:     "unlimited large body",
+			query:    map[string]string{"size": "200000"},
+			wantSize: 200000,
+		},
+		{
+			name:       "body within limit",
+			profileMax: 10,
+			query:      map[string]string{"size": "10"},
+			wantSize:   10,
+		},
+		{
+			name:        "body exceeding limit",
+			profileMax:  10,
+			query:       map[string]string{"size": "11"},
+			wantTooLong: true,
+		},
+		{
+			name:        "chunked body exceeding limit",
+			profileMax:  100000,
+			query:       map[string]string{"size": "200000", "chunked": "true"},
+			wantTooLong: true,
+		},
+		{
+			name:       "request limit replaces profile limit",
+			profileMax: 10,
+			requestMax: 200000,
+			query:      map[string]string{"size": "200000", "chunked": "true"},
+			wantSize:   200000,
+		},
+		{
+			name:        "error responses are limited",
+			profileMax:  10,
+			query:       map[string]string{"size": "100", "status": "500"},
+			wantTooLong: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			c, err := Create(configs.HTTPClientConfig{RequestTimeout: 5 * time.Second, MaxResponseBodySize: test.profileMax})
+			require.NoError(tt, err)
+
+			var resp []byte
+			err = c.Get(context.Background(), &PlainParser,
+				&HTTPRequest{URL: server.URL, Query: test.query, MaxResponseBodySize: test.requestMax}, &resp)
+
+			if test.wantTooLong {
+				hErr := HTTPError{}
+				require.ErrorAs(tt, err, &hErr)
+				assert.Equal(tt, http.StatusBadGateway, hErr.Code)
+			} else {
+				assert.NoError(tt, err)
+				assert.Len(tt, resp, test.wantSize)
+			}
+		})
+	}
+}
+
+func TestClient_streamTo(t *testing.T) {
+	server := newBodyServer(t)
+	tests := []struct {
+		name       string
+		maxSize    int
+		query      map[string]string
+		wantSize   int
+		wantStatus int
+	}{
+		{
+			name:     "large body is streamed",
+			query:    map[string]string{"size": "200000"},
+			wantSize: 200000,
+		},
+		{
+			name:     "chunked body is streamed",
+			query:    map[string]string{"size": "200000", "chunked": "true"},
+			wantSize: 200000,
+		},
+		{
+			name:       "body exceeding limit is not streamed",
+			maxSize:    100000,
+			query:      map[string]string{"size": "200000"},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "error response is not streamed",
+			query:      map[string]string{"size": "10", "status": "404"},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			c, err := Create(configs.HTTPClientConfig{RequestTimeout: 5 * time.Second})
+			require.NoError(tt, err)
+
+			w := &bytes.Buffer{}
+			err = c.Get(context.Background(), &PlainParser,
+				&HTTPRequest{URL: server.URL, Query: test.query, MaxResponseBodySize: test.maxSize, StreamTo: w}, nil)
+
+			if test.wantStatus != 0 {
+				hErr := HTTPError{}
+				require.ErrorAs(tt, err, &hErr)
+				assert.Equal(tt, test.wantStatus, hErr.Code)
+				assert.Zero(tt, w.Len())
+			} else {
+				assert.NoError(tt, err)
+				assert.Equal(tt, test.wantSize, w.Len())
+			}
+		})
+	}
+}
+
+func TestLimitedReader(t *testing.T) {
+	r := &limitedReader{r: strings.NewReader("12345"), remaining: 3, limit: 3}
+	buf := make([]byte, 10)
+
+	n, err := r.Read(buf)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, ResponseTooLargeError(3), err)
+}
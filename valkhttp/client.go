@@ -6,6 +6,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -51,27 +52,40 @@ type Client struct {
 	config     configs.HTTPClientConfig
 }
 
-func Create(config configs.HTTPClientConfig) *Client {
+// Create creates a Client for the default profile
+func Create(config configs.HTTPClientConfig) (*Client, error) {
+	return createProfile(DefaultProfile, config)
+}
+
+// createProfile creates a Client with its own connection pool, reporting pool statistics for profile
+func createProfile(profile string, config configs.HTTPClientConfig) (*Client, error) {
+	tlsConfig, err := newTLSConfig(config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("http client profile '%s': %w", profile, err)
+	}
+	dial, err := newDialFunc(config.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("http client profile '%s': %w", profile, err)
+	}
 	return &Client{
 		config: config,
 		fastClient: &fasthttp.Client{
 			ReadTimeout:                   config.ReadTimeout,
 			WriteTimeout:                  config.WriteTimeout,
 			MaxIdleConnDuration:           config.IdleTimeout,
+			MaxConnsPerHost:               config.MaxConnsPerHost,
+			MaxResponseBodySize:           streamThreshold, // larger bodies are streamed, limited by config.MaxResponseBodySize
+			TLSConfig:                     tlsConfig,
 			NoDefaultUserAgentHeader:      true, // Don't send: User-Agent: fasthttp
 			DisableHeaderNamesNormalizing: true, // If you set the case on your headers correctly you can enable this
 			DisablePathNormalizing:        true,
 			RetryIfErr: func(_ *fasthttp.Request, _ int, _ error) (resetTimeout bool, retry bool) {
-				return false, false // Disable automatic retries for GET/PATCH/PUT
+				return false, false // Disable automatic retries, retries are handled according to config.Retry
 			},
 			MaxIdemponentCallAttempts: 1,
-			// increase DNS cache time to an hour instead of default minute
-			Dial: (&fasthttp.TCPDialer{
-				Concurrency:      4096,
-				DNSCacheDuration: time.Hour,
-			}).Dial,
+			Dial:                      newPoolStats(profile).dial(dial),
 		},
-	}
+	}, nil
 }
 
 // Parser used to parse response and write to request body
@@ -166,14 +180,15 @@ func (p *xmlParser) Write(content interface{}) requestContentFn {
 	}
 }
 
-// Read will read response body target *[]byte.
+// Read will read response body target *[]byte. The body is copied, since the response is
+// released when the request is done.
 func (p *plainParser) Read(target any) responseParseFn {
 	return func(r *fasthttp.Response) error {
 		t, ok := target.(*[]byte)
 		if !ok {
 			return fmt.Errorf("invalid type of target, should be *[]byte")
 		}
-		*t = r.Body()
+		*t = append([]byte(nil), r.Body()...)
 		return nil
 	}
 }
@@ -199,6 +214,15 @@ type HTTPRequest struct {
 	Headers map[string]string
 	Query   map[string]string
 	URL     string
+	// Retry policy replacing the one of the client, if set
+	Retry *configs.RetryConfig
+	// Idempotent marks a POST request as safe to retry on retryable status codes.
+	// GET and PUT requests are always considered idempotent.
+	Idempotent bool
+	// MaxResponseBodySize in bytes replacing the one of the client, if set
+	MaxResponseBodySize int
+	// StreamTo streams successful response bodies to the writer, instead of parsing them
+	StreamTo io.Writer
 }
 
 // HTTPClient interface for client where user can provide Parser for request and response
@@ -210,74 +234,35 @@ type HTTPClient interface {
 
 // Get Issue Get request  with expected response body set to resp
 func (c *Client) Get(ctx context.Context, p Parser, req *HTTPRequest, resp any) error {
-	return c.get(ctx, req.URL, p.Read(resp), req.Headers, req.Query)
+	return c.handle(ctx, req, nil, p.Read(resp), fasthttp.MethodGet)
 }
 
 // Post issue Post request with expected response body set to resp
 func (c *Client) Post(ctx context.Context, p Parser, req *HTTPRequest, resp any) error {
-	return c.post(ctx, req.URL, p.Write(req.Body), p.Read(resp), req.Headers, req.Query)
+	return c.handle(ctx, req, p.Write(req.Body), p.Read(resp), fasthttp.MethodPost)
 }
 
 // Put issue Put request  with expected response body set to resp
 func (c *Client) Put(ctx context.Context, p Parser, req *HTTPRequest, resp any) error {
-	return c.put(ctx, req.URL, p.Write(req.Body), p.Read(resp), req.Headers, req.Query)
-}
-
-func (c *Client) post(
-	ctx context.Context,
-	uri string,
-	bodyFn requestContentFn,
-	parseFn responseParseFn,
-	headers map[string]string,
-	query map[string]string) error {
-	return c.handle(ctx, uri, bodyFn, parseFn, fasthttp.MethodPost, headers, query)
-}
-
-func (c *Client) put(
-	ctx context.Context,
-	uri string,
-	bodyFn requestContentFn,
-	parseFn responseParseFn,
-	headers map[string]string,
-	query map[string]string) error {
-	return c.handle(ctx, uri, bodyFn, parseFn, fasthttp.MethodPut, headers, query)
-}
-
-func (c *Client) get(
-	ctx context.Context,
-	uri string,
-	parseFn responseParseFn,
-	headers map[string]string,
-	query map[string]string) error {
-	return c.handle(ctx, uri, nil, parseFn, fasthttp.MethodGet, headers, query)
-}
-
-const maxRetries = 1
-
-var retriedErrors = []error{
-	// Retry ErrConnectionClosed, caused by server closing keepalive connection
-	// before notifying client
-	fasthttp.ErrConnectionClosed,
+	return c.handle(ctx, req, p.Write(req.Body), p.Read(resp), fasthttp.MethodPut)
 }
 
 func (c *Client) handle(
 	ctx context.Context,
-	uri string,
+	r *HTTPRequest,
 	bodyFn requestContentFn,
 	parseFn responseParseFn,
-	method string,
-	headers map[string]string,
-	query map[string]string) error {
+	method string) error {
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
-	req.SetRequestURI(uri)
+	req.SetRequestURI(r.URL)
 
 	req.Header.SetMethod(method)
-	for k, v := range headers {
+	for k, v := range r.Headers {
 		req.Header.Add(k, v)
 	}
 
-	for k, v := range query {
+	for k, v := range r.Query {
 		req.URI().QueryArgs().Add(k, v)
 	}
 
@@ -289,16 +274,41 @@ func (c *Client) handle(
 
 	resp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseResponse(resp)
+	resp.StreamBody = true
+
+	maxBodySize := c.config.MaxResponseBodySize
+	if r.MaxResponseBodySize > 0 {
+		maxBodySize = r.MaxResponseBodySize
+	}
+
+	retryConfig := c.config.Retry
+	if r.Retry != nil {
+		retryConfig = *r.Retry
+	}
+	policy := newRetryPolicy(retryConfig, method != fasthttp.MethodPost || r.Idempotent)
 
 	err := Pipeline.Execute(ctx,
 		PipelinePayload{req, resp},
 		func(pc pipeline.PipelineContext[PipelinePayload]) error {
-			return retry(func() error {
-				return c.fastClient.DoTimeout(pc.Payload().Request(), pc.Payload().Response(), c.config.RequestTimeout)
-			}, maxRetries, retriedErrors)
+			return retry(pc.Context(), policy, func() (int, error) {
+				resp := pc.Payload().Response()
+				if err := c.fastClient.DoTimeout(pc.Payload().Request(), resp, c.config.RequestTimeout); err != nil {
+					return 0, err
+				}
+				// Only successful responses are streamed, others are buffered to be parsed
+				if r.StreamTo == nil || resp.StatusCode() != http.StatusOK {
+					if err := bufferBody(resp, maxBodySize); err != nil {
+						return 0, err
+					}
+				}
+				return resp.StatusCode(), nil
+			})
 		})
 
 	statusCode := resp.StatusCode()
+	if err == nil && r.StreamTo != nil && statusCode == http.StatusOK {
+		return streamBody(resp, maxBodySize, r.StreamTo)
+	}
 	if err == nil {
 		return handleResponse(statusCode, resp, parseFn)
 	}
@@ -324,35 +334,6 @@ func handleResponse(statusCode int, resp *fasthttp.Response, parseFn responsePar
 	return nil
 }
 
-// retry will run call() and check its returned error. Errors matching any of retriedErrors
-// are retried up to maxRetries amount of times.
-func retry(call func() error, maxRetries int, retriedErrors []error) (err error) {
-	for r := 0; r <= maxRetries; r++ {
-		err = call()
-
-		switch {
-		case err == nil:
-			// never retry successful calls
-			return err
-		case !containsError(err, retriedErrors):
-			// don't retry errors not part of retriedErrors
-			return err
-		}
-	}
-
-	return err
-}
-
-// containsError returns true if checkedErrors contains err, otherwise false
-func containsError(err error, checkedErrors []error) bool {
-	for _, e := range checkedErrors {
-		if errors.Is(err, e) {
-			return true
-		}
-	}
-	return false
-}
-
 func handleError(err error) error {
 	if errors.Is(err, fasthttp.ErrTimeout) {
 		return TimeoutError // don't leak fasthttp timeout error
@@ -0,0 +1,79 @@
+package valkhttp
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	metricNamePoolConnections = "http.client.pool.connections"
+	metricNamePoolDials       = "http.client.pool.dials"
+	metricNamePoolDialErrors  = "http.client.pool.dial_errors"
+)
+
+// poolStats records connection pool statistics of a profile as metrics
+type poolStats struct {
+	attributes  metric.MeasurementOption
+	connections metric.Int64UpDownCounter
+	dials       metric.Int64Counter
+	dialErrors  metric.Int64Counter
+}
+
+func newPoolStats(profile string) *poolStats {
+	meter := otel.Meter(meterName)
+	s := &poolStats{attributes: metric.WithAttributes(attribute.String("profile", profile))}
+
+	var err error
+	if s.connections, err = meter.Int64UpDownCounter(metricNamePoolConnections,
+		metric.WithUnit("1"),
+		metric.WithDescription("measures the number of open connections of the HTTP client profile")); err != nil {
+		s.connections = noop.Int64UpDownCounter{}
+	}
+	if s.dials, err = meter.Int64Counter(metricNamePoolDials,
+		metric.WithUnit("1"),
+		metric.WithDescription("measures the number of connections opened by the HTTP client profile")); err != nil {
+		s.dials = noop.Int64Counter{}
+	}
+	if s.dialErrors, err = meter.Int64Counter(metricNamePoolDialErrors,
+		metric.WithUnit("1"),
+		metric.WithDescription("measures the number of failed connection attempts of the HTTP client profile")); err != nil {
+		s.dialErrors = noop.Int64Counter{}
+	}
+	return s
+}
+
+// dial wraps dial, recording opened, closed and failed connections
+func (s *poolStats) dial(dial fasthttp.DialFunc) fasthttp.DialFunc {
+	return func(addr string) (net.Conn, error) {
+		ctx := context.Background()
+		conn, err := dial(addr)
+		if err != nil {
+			s.dialErrors.Add(ctx, 1, s.attributes)
+			return nil, err
+		}
+		s.dials.Add(ctx, 1, s.attributes)
+		s.connections.Add(ctx, 1, s.attributes)
+		return &countedConn{Conn: conn, onClose: func() {
+			s.connections.Add(ctx, -1, s.attributes)
+		}}, nil
+	}
+}
+
+// countedConn calls onClose once when the connection is closed
+type countedConn struct {
+	net.Conn
+	once    sync.Once
+	onClose func()
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(c.onClose)
+	return c.Conn.Close()
+}
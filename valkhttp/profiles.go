@@ -0,0 +1,48 @@
+package valkhttp
+
+import (
+	"fmt"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+// DefaultProfile name of the profile configured by http_client
+const DefaultProfile = "default"
+
+// Profiles holds a Client per configured http client profile, each with its own connection pool
+type Profiles struct {
+	clients map[string]*Client
+}
+
+// CreateProfiles creates the default client from defaultConfig and one client per named profile.
+// A profile named "default" replaces the default client.
+func CreateProfiles(defaultConfig configs.HTTPClientConfig, profiles map[string]configs.HTTPClientConfig) (*Profiles, error) {
+	defaultClient, err := createProfile(DefaultProfile, defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	clients := map[string]*Client{DefaultProfile: defaultClient}
+	for name, config := range profiles {
+		if clients[name], err = createProfile(name, config); err != nil {
+			return nil, err
+		}
+	}
+	return &Profiles{clients: clients}, nil
+}
+
+// Default returns the client of the default profile
+func (p *Profiles) Default() *Client {
+	return p.clients[DefaultProfile]
+}
+
+// Get returns the client of the named profile, or the default client if name is empty
+func (p *Profiles) Get(name string) (*Client, error) {
+	if name == "" {
+		return p.Default(), nil
+	}
+	client, found := p.clients[name]
+	if !found {
+		return nil, fmt.Errorf("unknown http client profile '%s'", name)
+	}
+	return client, nil
+}
@@ -0,0 +1,52 @@
+package valkhttp
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+func TestProfiles_Get(t *testing.T) {
+	profiles, err := CreateProfiles(
+		configs.HTTPClientConfig{RequestTimeout: time.Second},
+		map[string]configs.HTTPClientConfig{"slow": {RequestTimeout: time.Minute, MaxConnsPerHost: 10}})
+	require.NoError(t, err)
+
+	c, err := profiles.Get("")
+	require.NoError(t, err)
+	assert.Same(t, profiles.Default(), c)
+	assert.Equal(t, time.Second, c.config.RequestTimeout)
+
+	c, err = profiles.Get("slow")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, c.config.RequestTimeout)
+	assert.Equal(t, 10, c.fastClient.(*fasthttp.Client).MaxConnsPerHost)
+	assert.NotSame(t, profiles.Default().fastClient, c.fastClient, "profiles have separate connection pools")
+
+	_, err = profiles.Get("missing")
+	assert.Error(t, err)
+}
+
+func TestPoolStats_dial(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	stats := newPoolStats("test")
+
+	conn, err := stats.dial(func(string) (net.Conn, error) { return client, nil })("addr")
+	require.NoError(t, err)
+	closed := 0
+	conn.(*countedConn).onClose = func() { closed++ }
+	assert.NoError(t, conn.Close())
+	_ = conn.Close()
+	assert.Equal(t, 1, closed, "closing a connection is only counted once")
+
+	_, err = stats.dial(func(string) (net.Conn, error) { return nil, assert.AnError })("addr")
+	assert.True(t, errors.Is(err, assert.AnError))
+}
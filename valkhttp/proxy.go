@@ -0,0 +1,48 @@
+package valkhttp
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpproxy"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+// newDialFunc creates the dial function of a profile, connecting through the configured proxy, if any.
+// Requests to hosts matching NoProxy, as well as to localhost, are not proxied.
+func newDialFunc(config configs.ProxyConfig) (fasthttp.DialFunc, error) {
+	dialer := &fasthttpproxy.Dialer{
+		TCPDialer: fasthttp.TCPDialer{
+			Concurrency:      4096,
+			DNSCacheDuration: time.Hour, // increase DNS cache time to an hour instead of default minute
+		},
+	}
+
+	if config.FromEnvironment {
+		return dialer.GetDialFunc(true)
+	}
+	if config.URL == "" {
+		return dialer.TCPDialer.Dial, nil
+	}
+
+	proxyURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme '%s', use http, socks5 or socks5h", proxyURL.Scheme)
+	}
+
+	dialer.Config = httpproxy.Config{
+		HTTPProxy:  config.URL,
+		HTTPSProxy: config.URL,
+		NoProxy:    config.NoProxy,
+	}
+	return dialer.GetDialFunc(false)
+}
@@ -0,0 +1,149 @@
+package valkhttp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+// startProxy starts a proxy accepting connections with handshake, answering all proxied requests itself
+func startProxy(t *testing.T, handshake func(conn net.Conn, r *bufio.Reader) error) (addr string, proxied *atomic.Int32) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	proxied = &atomic.Int32{}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				if handshake(conn, r) != nil {
+					return
+				}
+				req, err := http.ReadRequest(r)
+				if err != nil {
+					return
+				}
+				proxied.Add(1)
+				resp := &http.Response{StatusCode: http.StatusOK, ProtoMajor: 1, ProtoMinor: 1, Request: req,
+					ContentLength: 7, Body: io.NopCloser(stringReader("proxied"))}
+				_ = resp.Write(conn)
+			}()
+		}
+	}()
+	return ln.Addr().String(), proxied
+}
+
+type stringReader string
+
+func (s stringReader) Read(p []byte) (int, error) {
+	return copy(p, s), io.EOF
+}
+
+func httpConnectHandshake(conn net.Conn, r *bufio.Reader) error {
+	req, err := http.ReadRequest(r)
+	if err != nil {
+		return err
+	}
+	if req.Method != http.MethodConnect {
+		return io.ErrUnexpectedEOF
+	}
+	_, err = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	return err
+}
+
+func socks5Handshake(conn net.Conn, r *bufio.Reader) error {
+	// greeting: version, number of methods, methods
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, make([]byte, header[1])); err != nil {
+		return err
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return err
+	}
+	// connect request: version, command, reserved, address type, address, port
+	request := make([]byte, 5)
+	if _, err := io.ReadFull(r, request); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, make([]byte, int(request[4])+2)); err != nil { // domain name address
+		return err
+	}
+	_, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+func TestClient_proxy(t *testing.T) {
+	httpProxy, httpProxied := startProxy(t, httpConnectHandshake)
+	socksProxy, socksProxied := startProxy(t, socks5Handshake)
+
+	tests := []struct {
+		name        string
+		proxy       configs.ProxyConfig
+		proxied     *atomic.Int32
+		wantProxied bool
+	}{
+		{
+			name:        "HTTP CONNECT proxy",
+			proxy:       configs.ProxyConfig{URL: "http://" + httpProxy},
+			proxied:     httpProxied,
+			wantProxied: true,
+		},
+		{
+			name:        "SOCKS5 proxy",
+			proxy:       configs.ProxyConfig{URL: "socks5://" + socksProxy},
+			proxied:     socksProxied,
+			wantProxied: true,
+		},
+		{
+			name:    "excluded host is not proxied",
+			proxy:   configs.ProxyConfig{URL: "http://" + httpProxy, NoProxy: "localhost,.test"},
+			proxied: httpProxied,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			c, err := Create(configs.HTTPClientConfig{RequestTimeout: 5 * time.Second, Proxy: test.proxy})
+			require.NoError(tt, err)
+			before := test.proxied.Load()
+
+			var resp []byte
+			err = c.Get(context.Background(), &PlainParser, &HTTPRequest{URL: "http://valkyrie.test/ping"}, &resp)
+
+			if test.wantProxied {
+				assert.NoError(tt, err)
+				assert.Equal(tt, "proxied", string(resp))
+				assert.Equal(tt, before+1, test.proxied.Load())
+			} else {
+				assert.Error(tt, err, "valkyrie.test is dialed directly and can't be resolved")
+				assert.Equal(tt, before, test.proxied.Load())
+			}
+		})
+	}
+}
+
+func TestNewDialFunc_invalid(t *testing.T) {
+	_, err := newDialFunc(configs.ProxyConfig{URL: "https://proxy:443"})
+	assert.Error(t, err)
+
+	_, err = newDialFunc(configs.ProxyConfig{URL: "://proxy"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package valkhttp
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+const (
+	defaultMaxAttempts    = 2
+	defaultInitialBackoff = 50 * time.Millisecond
+	defaultMaxBackoff     = time.Second
+
+	meterName                   = "valkhttp"
+	metricNameHTTPClientRetries = "http.client.retries"
+	retryEventName              = "retry"
+)
+
+var retriedErrors = []error{
+	// Retry ErrConnectionClosed, caused by server closing keepalive connection
+	// before notifying client
+	fasthttp.ErrConnectionClosed,
+	// The request was never sent
+	fasthttp.ErrDialTimeout,
+}
+
+// retryPolicy is the retry configuration resolved for a single request
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	statusCodes    []int
+	idempotent     bool
+}
+
+// newRetryPolicy creates a retryPolicy from config, using defaults for unset values. Retryable
+// status codes are only retried for idempotent requests.
+func newRetryPolicy(config configs.RetryConfig, idempotent bool) retryPolicy {
+	p := retryPolicy{
+		maxAttempts:    config.MaxAttempts,
+		initialBackoff: config.InitialBackoff,
+		maxBackoff:     config.MaxBackoff,
+		statusCodes:    config.StatusCodes,
+		idempotent:     idempotent,
+	}
+	if p.maxAttempts <= 0 {
+		p.maxAttempts = defaultMaxAttempts
+	}
+	if p.initialBackoff <= 0 {
+		p.initialBackoff = defaultInitialBackoff
+	}
+	if p.maxBackoff <= 0 {
+		p.maxBackoff = defaultMaxBackoff
+	}
+	return p
+}
+
+// retryable returns true if an attempt with the outcome err and statusCode should be retried
+func (p retryPolicy) retryable(err error, statusCode int) bool {
+	if err != nil {
+		return containsError(err, retriedErrors) || isDialError(err)
+	}
+	return p.idempotent && slices.Contains(p.statusCodes, statusCode)
+}
+
+// backoff returns the exponential backoff with full jitter to wait before the retry:th retry
+func (p retryPolicy) backoff(retry int) time.Duration {
+	b := p.initialBackoff
+	for i := 1; i < retry && b < p.maxBackoff; i++ {
+		b *= 2
+	}
+	return rand.N(min(b, p.maxBackoff) + 1)
+}
+
+// retry will run call() until it succeeds, its outcome is not retryable or the policy is out of attempts.
+// call returns the status code of the response and any error. Each retry is recorded as a span event
+// and in the "http.client.retries" metric.
+func retry(ctx context.Context, policy retryPolicy, call func() (int, error)) error {
+	for attempt := 1; ; attempt++ {
+		statusCode, err := call()
+		if attempt >= policy.maxAttempts || !policy.retryable(err, statusCode) {
+			return err
+		}
+
+		recordRetry(ctx, attempt, statusCode, err)
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err == nil {
+				return ctx.Err()
+			}
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+var (
+	retryCounter     metric.Int64Counter
+	retryCounterOnce sync.Once
+)
+
+func recordRetry(ctx context.Context, attempt, statusCode int, err error) {
+	attributes := []attribute.KeyValue{attribute.Int("http.status_code", statusCode)}
+	if err != nil {
+		attributes = append(attributes, attribute.String("error", err.Error()))
+	}
+
+	trace.SpanFromContext(ctx).AddEvent(retryEventName,
+		trace.WithAttributes(append(attributes, attribute.Int("attempt", attempt))...))
+
+	retryCounterOnce.Do(func() {
+		retryCounter, _ = otel.Meter(meterName).Int64Counter(metricNameHTTPClientRetries,
+			metric.WithUnit("1"),
+			metric.WithDescription("measures the number of retried outbound HTTP client requests"))
+	})
+	if retryCounter != nil {
+		retryCounter.Add(ctx, 1, metric.WithAttributes(attribute.Int("http.status_code", statusCode)))
+	}
+}
+
+// containsError returns true if checkedErrors contains err, otherwise false
+func containsError(err error, checkedErrors []error) bool {
+	for _, e := range checkedErrors {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDialError returns true if err happened while connecting, before the request was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryClient applies a retry policy to requests without their own
+type retryClient struct {
+	HTTPClient
+	retry *configs.RetryConfig
+}
+
+// WithRetry returns a client using the retry policy for all requests not having their own,
+// for example to use a provider specific policy. Returns client as is if retry is nil.
+func WithRetry(client HTTPClient, retry *configs.RetryConfig) HTTPClient {
+	if retry == nil {
+		return client
+	}
+	return &retryClient{HTTPClient: client, retry: retry}
+}
+
+func (c *retryClient) Get(ctx context.Context, p Parser, req *HTTPRequest, resp any) error {
+	return c.HTTPClient.Get(ctx, p, c.withRetry(req), resp)
+}
+
+func (c *retryClient) Post(ctx context.Context, p Parser, req *HTTPRequest, resp any) error {
+	return c.HTTPClient.Post(ctx, p, c.withRetry(req), resp)
+}
+
+func (c *retryClient) Put(ctx context.Context, p Parser, req *HTTPRequest, resp any) error {
+	return c.HTTPClient.Put(ctx, p, c.withRetry(req), resp)
+}
+
+func (c *retryClient) withRetry(req *HTTPRequest) *HTTPRequest {
+	if req.Retry != nil {
+		return req
+	}
+	r := *req
+	r.Retry = c.retry
+	return &r
+}
@@ -0,0 +1,202 @@
+package valkhttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+var noBackoff = configs.RetryConfig{InitialBackoff: time.Nanosecond, MaxBackoff: time.Nanosecond}
+
+func Test_retry(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		statusCodes []int
+		idempotent  bool
+		statusCode  int
+		err         error
+		called      int
+	}{
+		{
+			name:        "run once",
+			maxAttempts: 1,
+			err:         fasthttp.ErrConnectionClosed,
+			called:      1,
+		},
+		{
+			name:        "run once with error not retried",
+			maxAttempts: 3,
+			err:         assert.AnError,
+			called:      1,
+		},
+		{
+			name:        "retry closed connection",
+			maxAttempts: 2,
+			err:         fasthttp.ErrConnectionClosed,
+			called:      2,
+		},
+		{
+			name:        "retry dial error until out of attempts",
+			maxAttempts: 3,
+			err:         &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			called:      3,
+		},
+		{
+			name:        "retry retryable status code of idempotent request",
+			maxAttempts: 3,
+			statusCodes: []int{http.StatusServiceUnavailable},
+			idempotent:  true,
+			statusCode:  http.StatusServiceUnavailable,
+			called:      3,
+		},
+		{
+			name:        "don't retry retryable status code of non idempotent request",
+			maxAttempts: 3,
+			statusCodes: []int{http.StatusServiceUnavailable},
+			statusCode:  http.StatusServiceUnavailable,
+			called:      1,
+		},
+		{
+			name:        "don't retry other status codes",
+			maxAttempts: 3,
+			statusCodes: []int{http.StatusServiceUnavailable},
+			idempotent:  true,
+			statusCode:  http.StatusInternalServerError,
+			called:      1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			config := noBackoff
+			config.MaxAttempts = test.maxAttempts
+			config.StatusCodes = test.statusCodes
+			called := 0
+
+			err := retry(context.Background(), newRetryPolicy(config, test.idempotent), func() (int, error) {
+				called++
+				return test.statusCode, test.err
+			})
+
+			assert.Equal(tt, test.called, called)
+			assert.Equal(tt, test.err, err)
+		})
+	}
+}
+
+func Test_retry_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := 0
+
+	err := retry(ctx, newRetryPolicy(configs.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Minute}, true), func() (int, error) {
+		called++
+		return 0, fasthttp.ErrConnectionClosed
+	})
+
+	assert.Equal(t, 1, called, "no retries after context is done")
+	assert.ErrorIs(t, err, fasthttp.ErrConnectionClosed)
+}
+
+func Test_retryPolicy_backoff(t *testing.T) {
+	p := newRetryPolicy(configs.RetryConfig{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond}, true)
+
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, p.backoff(1), 10*time.Millisecond)
+		assert.LessOrEqual(t, p.backoff(2), 20*time.Millisecond)
+		assert.LessOrEqual(t, p.backoff(10), 25*time.Millisecond)
+	}
+}
+
+// A stub of fasthttp responding with the status codes in order
+type sequenceFasthttp struct {
+	statusCodes []int
+	called      int
+}
+
+func (s *sequenceFasthttp) DoTimeout(_ *fasthttp.Request, resp *fasthttp.Response, _ time.Duration) error {
+	resp.SetStatusCode(s.statusCodes[s.called])
+	s.called++
+	return nil
+}
+
+func TestClient_retryStatusCodes(t *testing.T) {
+	retryConfig := noBackoff
+	retryConfig.MaxAttempts = 3
+	retryConfig.StatusCodes = []int{http.StatusBadGateway}
+
+	tests := []struct {
+		name       string
+		call       func(c HTTPClient, req *HTTPRequest) error
+		req        HTTPRequest
+		wantCalled int
+		wantErr    error
+	}{
+		{
+			name: "GET is retried",
+			call: func(c HTTPClient, req *HTTPRequest) error {
+				return c.Get(context.Background(), &PlainParser, req, &[]byte{})
+			},
+			wantCalled: 2,
+		},
+		{
+			name: "POST is not retried",
+			call: func(c HTTPClient, req *HTTPRequest) error {
+				return c.Post(context.Background(), &PlainParser, req, &[]byte{})
+			},
+			req:        HTTPRequest{Body: []byte{}},
+			wantCalled: 1,
+			wantErr:    NewHTTPError(http.StatusBadGateway, ""),
+		},
+		{
+			name: "idempotent POST is retried",
+			call: func(c HTTPClient, req *HTTPRequest) error {
+				return c.Post(context.Background(), &PlainParser, req, &[]byte{})
+			},
+			req:        HTTPRequest{Body: []byte{}, Idempotent: true},
+			wantCalled: 2,
+		},
+		{
+			name: "request retry policy replaces client policy",
+			call: func(c HTTPClient, req *HTTPRequest) error {
+				return c.Get(context.Background(), &PlainParser, req, &[]byte{})
+			},
+			req:        HTTPRequest{Retry: &configs.RetryConfig{MaxAttempts: 1}},
+			wantCalled: 1,
+			wantErr:    NewHTTPError(http.StatusBadGateway, ""),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			stub := &sequenceFasthttp{statusCodes: []int{http.StatusBadGateway, http.StatusOK}}
+			c := &Client{fastClient: stub, config: configs.HTTPClientConfig{Retry: retryConfig}}
+
+			err := test.call(c, &test.req)
+
+			assert.Equal(tt, test.wantErr, err)
+			assert.Equal(tt, test.wantCalled, stub.called)
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	stub := &sequenceFasthttp{statusCodes: []int{http.StatusBadGateway, http.StatusOK}}
+	c := &Client{fastClient: stub}
+	providerRetry := noBackoff
+	providerRetry.StatusCodes = []int{http.StatusBadGateway}
+
+	err := WithRetry(c, &providerRetry).Get(context.Background(), &PlainParser, &HTTPRequest{}, &[]byte{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stub.called)
+	assert.Same(t, c, WithRetry(c, nil))
+}
@@ -0,0 +1,170 @@
+package valkhttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+const defaultTLSReloadInterval = time.Minute
+
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig creates a tls.Config from config, or nil if nothing is configured. Client certificate
+// and CA bundle are reloaded from file when changed.
+func newTLSConfig(config configs.TLSConfig) (*tls.Config, error) {
+	if config.CertFile == "" && config.KeyFile == "" && config.CAFile == "" &&
+		config.MinVersion == "" && config.ServerName == "" {
+		return nil, nil
+	}
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, errors.New("tls cert_file and key_file must be configured together")
+	}
+	minVersion, found := tlsVersions[config.MinVersion]
+	if !found {
+		return nil, fmt.Errorf("unsupported tls min_version '%s'", config.MinVersion)
+	}
+
+	files := &tlsFiles{config: config, modTimes: map[string]time.Time{}}
+	if files.config.ReloadInterval <= 0 {
+		files.config.ReloadInterval = defaultTLSReloadInterval
+	}
+	if err := files.load(); err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: minVersion,
+		ServerName: config.ServerName,
+	}
+	if config.CertFile != "" {
+		tlsConfig.GetClientCertificate = files.clientCertificate
+	}
+	if config.CAFile != "" {
+		// Server certificates are verified in VerifyConnection instead, using the reloadable CA bundle
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec
+		tlsConfig.VerifyConnection = files.verifyConnection
+	}
+	return tlsConfig, nil
+}
+
+// tlsFiles holds certificates loaded from the configured files, reloading them when changed
+type tlsFiles struct {
+	config   configs.TLSConfig
+	mu       sync.Mutex
+	checked  time.Time
+	modTimes map[string]time.Time
+	cert     *tls.Certificate
+	roots    *x509.CertPool
+}
+
+func (f *tlsFiles) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	f.reloadIfChanged()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cert, nil
+}
+
+func (f *tlsFiles) verifyConnection(cs tls.ConnectionState) error {
+	f.reloadIfChanged()
+	f.mu.Lock()
+	roots := f.roots
+	f.mu.Unlock()
+
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server presented no certificates")
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, c := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// reloadIfChanged reloads the files if the reload interval has passed and any of them changed.
+// Failing reloads keep the previously loaded certificates.
+func (f *tlsFiles) reloadIfChanged() {
+	f.mu.Lock()
+	due := time.Since(f.checked) >= f.config.ReloadInterval
+	f.mu.Unlock()
+	if !due {
+		return
+	}
+	if err := f.load(); err != nil {
+		log.Warn().Err(err).Msg("Failed to reload tls files, using previously loaded")
+	}
+}
+
+// load (re)loads certificate and CA bundle if any of their files changed since last load
+func (f *tlsFiles) load() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checked = time.Now()
+
+	modTimes, changed, err := f.statFiles()
+	if err != nil || !changed {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if f.config.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(f.config.CertFile, f.config.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	var roots *x509.CertPool
+	if f.config.CAFile != "" {
+		pem, err := os.ReadFile(f.config.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read tls ca_file: %w", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in tls ca_file '%s'", f.config.CAFile)
+		}
+	}
+
+	f.cert, f.roots, f.modTimes = cert, roots, modTimes
+	return nil
+}
+
+func (f *tlsFiles) statFiles() (map[string]time.Time, bool, error) {
+	modTimes := map[string]time.Time{}
+	changed := false
+	for _, file := range []string{f.config.CertFile, f.config.KeyFile, f.config.CAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read tls file: %w", err)
+		}
+		modTimes[file] = info.ModTime()
+		if prev, found := f.modTimes[file]; !found || !prev.Equal(info.ModTime()) {
+			changed = true
+		}
+	}
+	return modTimes, changed, nil
+}
@@ -0,0 +1,204 @@
+package valkhttp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/valkyrie-fnd/valkyrie/configs"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a certificate and key (PEM) signed by the CA
+func (ca testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "valkyrie.test"},
+		DNSNames:     []string{"valkyrie.test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, dir, name string, content []byte) string {
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(file, content, 0o600))
+	return file
+}
+
+func newMTLSServer(t *testing.T, ca testCA) *httptest.Server {
+	certPEM, keyPEM := ca.issue(t, 2, x509.ExtKeyUsageServerAuth)
+	serverCert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_mutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	server := newMTLSServer(t, ca)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, 3, x509.ExtKeyUsageClientAuth)
+	caFile := writeFile(t, dir, "ca.pem", ca.pem)
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "client.key", keyPEM)
+
+	tests := []struct {
+		name    string
+		tls     configs.TLSConfig
+		wantErr bool
+	}{
+		{
+			name: "client certificate and CA bundle",
+			tls:  configs.TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile},
+		},
+		{
+			name: "SNI override verified against CA bundle",
+			tls:  configs.TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile, ServerName: "valkyrie.test"},
+		},
+		{
+			name:    "without client certificate",
+			tls:     configs.TLSConfig{CAFile: caFile},
+			wantErr: true,
+		},
+		{
+			name:    "without CA bundle server is not trusted",
+			tls:     configs.TLSConfig{CertFile: certFile, KeyFile: keyFile},
+			wantErr: true,
+		},
+		{
+			name:    "server name not matching server certificate",
+			tls:     configs.TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile, ServerName: "other.test"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			c, err := Create(configs.HTTPClientConfig{RequestTimeout: 5 * time.Second, TLS: test.tls})
+			require.NoError(tt, err)
+
+			var resp []byte
+			err = c.Get(context.Background(), &PlainParser, &HTTPRequest{URL: server.URL}, &resp)
+			if test.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+				assert.Equal(tt, "ok", string(resp))
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig_invalid(t *testing.T) {
+	_, err := newTLSConfig(configs.TLSConfig{CertFile: "client.pem"})
+	assert.Error(t, err, "key file is required")
+
+	_, err = newTLSConfig(configs.TLSConfig{MinVersion: "2.0"})
+	assert.Error(t, err, "unknown tls version")
+
+	_, err = newTLSConfig(configs.TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err, "missing ca file")
+
+	tlsConfig, err := newTLSConfig(configs.TLSConfig{ReloadInterval: time.Minute})
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig, "nothing configured")
+}
+
+func TestTLSFiles_reload(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, 3, x509.ExtKeyUsageClientAuth)
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "client.key", keyPEM)
+
+	files := &tlsFiles{
+		config:   configs.TLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: time.Nanosecond},
+		modTimes: map[string]time.Time{},
+	}
+	require.NoError(t, files.load())
+	first, err := files.clientCertificate(nil)
+	require.NoError(t, err)
+
+	// replace with a new certificate
+	certPEM, keyPEM = ca.issue(t, 4, x509.ExtKeyUsageClientAuth)
+	writeFile(t, dir, "client.pem", certPEM)
+	writeFile(t, dir, "client.key", keyPEM)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+
+	second, err := files.clientCertificate(nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Certificate, second.Certificate, "changed certificate is reloaded")
+
+	// broken files keep the previous certificate
+	writeFile(t, dir, "client.pem", []byte("broken"))
+	require.NoError(t, os.Chtimes(certFile, later.Add(time.Minute), later.Add(time.Minute)))
+
+	third, err := files.clientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second.Certificate, third.Certificate)
+}